@@ -17,6 +17,10 @@ package core
 // All matches all of the given parsers in order or rolls back the input.
 func All[T any](parsers ...Parser[T]) Parser[[]T] {
 	return func(in Input) ([]T, bool, error) {
+		if err := enter(in); err != nil {
+			return nil, false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		match := make([]T, 0, len(parsers))
 		for _, parser := range parsers {
@@ -17,8 +17,13 @@ package core
 // Any looks for matches in the given parsers, returning the first match or rolls back the input if no match is found.
 func Any[T any](parsers ...Parser[T]) Parser[T] {
 	return func(in Input) (T, bool, error) {
+		if err := enter(in); err != nil {
+			var t T
+			return t, false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
-		for _, parser := range parsers {
+		for _, parser := range shuffle(in, parsers) {
 			match, ok, err := parser(in)
 			if err != nil || ok {
 				return match, true, err
@@ -0,0 +1,205 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+)
+
+// Inputs shaped like the notes the parsers are written for, each around a kilobyte.
+var (
+	benchProse = strings.Repeat("the quick brown fox jumps over the lazy dog ", 24)
+	benchTask  = "- [ ] water the plants due:2024-02-01 every:7 priority:1\n"
+	benchTasks = strings.Repeat(benchTask, 18)
+	benchSpace = strings.Repeat(" \t\n", 340)
+	benchRunes = strings.Repeat("a", 1024)
+	benchWords = strings.Fields(benchProse)
+)
+
+var (
+	benchLower  = "abcdefghijklmnopqrstuvwxyz"
+	benchWord   = core.StringFrom(core.SkipOneOrMore(core.RuneIn(benchLower)))
+	benchSpaced = core.SequenceOf2(benchWord, core.Rune(' '))
+)
+
+// benchWordSpace matches a word followed by a space, returning the word.
+var benchWordSpace = func(in core.Input) (string, bool, error) {
+	m, ok, err := benchSpaced(in)
+	if err != nil || !ok {
+		return "", false, err
+	}
+	word, _ := m.Values()
+	return word, true, nil
+}
+
+func BenchmarkRune(b *testing.B) {
+	BenchTests(b, []ParserTest[string]{
+		{Name: "Rune", Input: benchRunes, Parser: core.StringFrom(core.ZeroOrMore(core.Rune('a'))), ExpectedMatch: benchRunes, ExpectedOK: true},
+		{Name: "RuneIn", Input: benchProse, Parser: core.StringFrom(core.ZeroOrMore(core.RuneIn(benchLower + " "))), ExpectedMatch: benchProse, ExpectedOK: true},
+		{Name: "RuneNotIn", Input: benchProse, Parser: core.StringFrom(core.ZeroOrMore(core.RuneNotIn("\n"))), ExpectedMatch: benchProse, ExpectedOK: true},
+		{Name: "Letter", Input: benchRunes, Parser: core.StringFrom(core.ZeroOrMore(core.Letter)), ExpectedMatch: benchRunes, ExpectedOK: true},
+		{Name: "AnyRune", Input: benchProse, Parser: core.StringFrom(core.ZeroOrMore(core.AnyRune)), ExpectedMatch: benchProse, ExpectedOK: true},
+	})
+}
+
+func BenchmarkString(b *testing.B) {
+	sentence := "the quick brown fox jumps over the lazy dog "
+	sentences := make([]string, len(benchProse)/len(sentence))
+	for i := range sentences {
+		sentences[i] = sentence
+	}
+	BenchTests(b, []ParserTest[[]string]{
+		{Name: "String", Input: benchProse, Parser: core.ZeroOrMore(core.String(sentence)), ExpectedMatch: sentences, ExpectedOK: true},
+		{Name: "StringInsensitive", Input: benchProse, Parser: core.ZeroOrMore(core.StringInsensitive(strings.ToUpper(sentence))), ExpectedMatch: sentences, ExpectedOK: true},
+	})
+}
+
+func BenchmarkStringFrom(b *testing.B) {
+	BenchTests(b, []ParserTest[string]{
+		{Name: "Words", Input: benchProse, Parser: core.StringFrom(core.ZeroOrMore(benchWordSpace)), ExpectedMatch: benchProse, ExpectedOK: true},
+		{Name: "SkipWords", Input: benchProse, Parser: core.StringFrom(core.SkipZeroOrMore(benchWordSpace)), ExpectedMatch: benchProse, ExpectedOK: true},
+		{Name: "SkipRunes", Input: benchProse, Parser: core.StringFrom(core.SkipZeroOrMore(core.RuneIn(benchLower + " "))), ExpectedMatch: benchProse, ExpectedOK: true},
+		{Name: "Whitespace", Input: benchSpace, Parser: core.Whitespace, ExpectedMatch: benchSpace, ExpectedOK: true},
+		{Name: "InlineWhitespace", Input: strings.Repeat(" \t", 512), Parser: core.InlineWhitespace, ExpectedMatch: strings.Repeat(" \t", 512), ExpectedOK: true},
+	})
+}
+
+func BenchmarkStringUntil(b *testing.B) {
+	line := strings.TrimSuffix(benchTask, "\n")
+	BenchTests(b, []ParserTest[string]{
+		{Name: "StringUntil", Input: benchTask, Parser: core.StringUntil(core.Rune('\n')), ExpectedMatch: line, ExpectedOK: true, RemainingInput: "\n"},
+		{Name: "StringUntilEOF", Input: line, Parser: core.StringUntilEOF(core.Rune('\n')), ExpectedMatch: line, ExpectedOK: true},
+		{Name: "StringWhileNot", Input: benchTask, Parser: core.StringWhileNot(core.Rune('\n')), ExpectedMatch: line, ExpectedOK: true, RemainingInput: "\n"},
+		{Name: "StringWhileNotEOFOr", Input: line, Parser: core.StringWhileNotEOFOr(core.Rune('\n')), ExpectedMatch: line, ExpectedOK: true},
+	})
+}
+
+func BenchmarkTimes(b *testing.B) {
+	BenchTests(b, []ParserTest[[]string]{
+		{Name: "ZeroOrMore", Input: benchProse, Parser: core.ZeroOrMore(benchWordSpace), ExpectedMatch: benchWords, ExpectedOK: true},
+		{Name: "Times", Input: benchProse, Parser: core.Times(len(benchWords), benchWordSpace), ExpectedMatch: benchWords, ExpectedOK: true},
+		{Name: "Until", Input: benchProse + "\n", Parser: core.Until(benchWordSpace, core.Rune('\n')), ExpectedMatch: benchWords, ExpectedOK: true, RemainingInput: "\n"},
+		{Name: "WhileNot", Input: benchProse + "\n", Parser: core.WhileNot(benchWordSpace, core.Rune('\n')), ExpectedMatch: benchWords, ExpectedOK: true, RemainingInput: "\n"},
+	})
+}
+
+func BenchmarkChoice(b *testing.B) {
+	animal := []core.Parser[string]{core.String("cat "), core.String("dog "), core.String("fox "), benchWordSpace}
+	animals := make([]string, len(benchWords))
+	for i, word := range benchWords {
+		animals[i] = word
+		if word == "dog" || word == "fox" {
+			animals[i] += " "
+		}
+	}
+	BenchTests(b, []ParserTest[[]string]{
+		{Name: "Any", Input: benchProse, Parser: core.ZeroOrMore(core.Any(animal...)), ExpectedMatch: animals, ExpectedOK: true},
+		{Name: "Longest", Input: benchProse, Parser: core.ZeroOrMore(core.Longest(animal...)), ExpectedMatch: animals, ExpectedOK: true},
+		{Name: "Ambiguous", Input: benchProse, Parser: core.ZeroOrMore(core.Ambiguous(animal[0], benchWordSpace)), ExpectedMatch: benchWords, ExpectedOK: true},
+	})
+}
+
+// benchCount returns the number of matches of a parser that matches the whole of the input.
+func benchCount[T any](parser core.Parser[[]T]) core.Parser[int] {
+	return func(in core.Input) (int, bool, error) {
+		m, ok, err := parser(in)
+		return len(m), ok, err
+	}
+}
+
+func BenchmarkSequence(b *testing.B) {
+	checkbox := core.SequenceOf3(core.String("- ["), core.RuneIn(" x"), core.String("] "))
+	field := core.SequenceOf3(core.StringFrom(core.SkipOneOrMore(core.Letter)), core.Rune(':'), core.StringFrom(core.SkipOneOrMore(core.RuneIn("0123456789-"))))
+	task := core.SequenceOf4(checkbox, core.Until(benchWordSpace, field), core.OneOrMore(core.SequenceOf2(field, core.Optional(core.InlineWhitespace))), core.Rune('\n'))
+	BenchTests(b, []ParserTest[int]{
+		{Name: "SequenceOf", Input: benchTasks, Parser: benchCount(core.ZeroOrMore(task)), ExpectedMatch: strings.Count(benchTasks, "\n"), ExpectedOK: true},
+		{Name: "Optional", Input: benchProse, Parser: benchCount(core.ZeroOrMore(core.Optional(benchWordSpace))), ExpectedMatch: len(benchWords), ExpectedOK: true},
+		{Name: "Or", Input: benchProse, Parser: benchCount(core.ZeroOrMore(core.Or(core.String("dog "), benchWordSpace))), ExpectedMatch: len(benchWords), ExpectedOK: true},
+		{Name: "All", Input: benchProse, Parser: benchCount(core.ZeroOrMore(core.All(benchWord, core.Rune(' ')))), ExpectedMatch: len(benchWords), ExpectedOK: true},
+	})
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	metadata := core.SequenceOf2(taskMetadata(core.InlineWhitespace), core.Rune('\n'))
+	input := strings.Repeat("priority:1 due:2024-02-01 every:7\n", 30)
+	BenchTests(b, []ParserTest[int]{
+		{Name: "Permutation", Input: input, Parser: benchCount(core.ZeroOrMore(metadata)), ExpectedMatch: 30, ExpectedOK: true},
+	})
+}
+
+func BenchmarkState(b *testing.B) {
+	BenchTests(b, []ParserTest[[]string]{
+		{Name: "WithState", Input: benchProse, Parser: core.WithState(depth(1), core.ZeroOrMore(core.WithState(depth(2), benchWordSpace))), ExpectedMatch: benchWords, ExpectedOK: true},
+	})
+}
+
+func BenchmarkParse(b *testing.B) {
+	parser := core.ZeroOrMore(benchWordSpace)
+	limits := core.Limits{MaxSteps: 1_000_000, MaxDepth: 100, MaxBacktracks: 10_000}
+	b.ReportAllocs()
+	b.SetBytes(int64(len(benchProse)))
+	for range b.N {
+		if _, ok, err := core.Parse(context.Background(), parser, benchProse, limits); !ok || err != nil {
+			b.Fatalf("failed to parse: %v", err)
+		}
+	}
+}
+
+// The parsers that are called for every rune or word of a document should not allocate, apart from building slices of matches.
+func TestAllocations(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		parser core.Parser[string]
+	}{
+		{"Rune", "a", core.Rune('a')},
+		{"RuneIn", "x", core.RuneIn(benchLower)},
+		{"RuneNotIn", "x", core.RuneNotIn("\n")},
+		{"Letter", "é", core.Letter},
+		{"String", "due:", core.String("due:")},
+		{"StringInsensitive", "DUE:", core.StringInsensitive("due:")},
+		{"StringFrom", benchProse, core.StringFrom(core.SkipZeroOrMore(benchWordSpace))},
+		{"StringUntil", benchTask, core.StringUntil(core.Rune('\n'))},
+		{"StringUntilEOF", benchTask, core.StringUntilEOF(core.Rune('\n'))},
+		{"StringWhileNotEOFOr", benchTask, core.StringWhileNotEOFOr(core.Rune('\n'))},
+		{"Whitespace", benchSpace, core.Whitespace},
+		{"InlineWhitespace", " \t ", core.InlineWhitespace},
+		{"Any", benchProse, core.Any(core.String("cat "), benchWordSpace)},
+		{"Longest", benchProse, core.Longest(core.String("cat "), benchWordSpace)},
+		{"SequenceOf", benchProse, core.StringFrom(core.SequenceOf3(benchWord, core.Rune(' '), benchWord))},
+		{"Optional", benchProse, core.StringFrom(core.Optional(benchWordSpace), core.Optional(core.Rune('!')))},
+		{"Or", benchProse, core.StringFrom(core.Or(core.String("cat "), benchWordSpace))},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			in := core.NewInput(test.input)
+			start := in.Checkpoint()
+			allocs := testing.AllocsPerRun(100, func() {
+				in.Restore(start)
+				if _, ok, err := test.parser(in); !ok || err != nil {
+					t.Fatalf("failed to parse %q: %v", test.input, err)
+				}
+			})
+			if allocs != 0 {
+				t.Errorf("expected no allocations, got %v", allocs)
+			}
+		})
+	}
+}
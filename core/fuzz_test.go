@@ -0,0 +1,39 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+)
+
+func FuzzLetters(f *testing.F) {
+	FuzzParser(f, core.StringFrom(core.OneOrMore(core.Letter)), "abc", "été", "日本語", "a1", "\xff")
+}
+
+func FuzzWords(f *testing.F) {
+	word := core.StringFrom(core.OneOrMore(core.RuneNotIn(" \t\n")))
+	FuzzParser(f, core.SequenceOf2(word, core.ZeroOrMore(core.SequenceOf2(core.Whitespace, word))), "a b  c", "naïve café", " leading", "")
+}
+
+func FuzzLongest(f *testing.F) {
+	FuzzParser(f, core.Longest(core.String("ab"), core.StringInsensitive("abc"), core.StringUntil(core.Rune(';'))), "abc", "ABC;", "ab", "–;")
+}
+
+func FuzzUntil(f *testing.F) {
+	FuzzParser(f, core.Until(core.AnyRune, core.NewLine), "line\nnext", "no newline", "\r\n", "")
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sync"
+)
+
+// generator grows the text of a generating input whenever a parser reaches its end.
+type generator struct {
+	rand *rand.Rand
+	// Set while running a parser that has its own generator, see WithGenerator.
+	suspended bool
+}
+
+// Probability that an optional or repeated parser is matched again while generating.
+const repeatProbability = 0.7
+
+// Length in bytes beyond which generated text is not extended, which ends parsers that repeat without a combinator.
+const generateMaxLength = 256
+
+// Maximum steps a parser may take over a generating input, as a grammar may recurse without end if it keeps extending.
+const generateMaxSteps = 100_000
+
+// Runes RuneWhere chooses from when generating, so a predicate that matches none of them cannot be generated.
+var generateRunes = []rune(" \t\n!\"#$%&'()*+,-./0123456789:;<=>?@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_`abcdefghijklmnopqrstuvwxyz{|}~éüßçñ–日本")
+
+// NewGeneratingInput creates an empty input that grows as parsers reach its end, so running a parser over it generates
+// random text the parser is likely to accept. String, StringInsensitive and RuneWhere, and so every parser built from them,
+// append text they would match, while parsers that read the input directly can describe their text with WithGenerator.
+// Any and Longest try their parsers in a random order so that every alternative can be generated, and optional
+// and repeated parsers are matched a random number of times.
+// The generated text is rolled back along with the input, but the parser may still fail or the text may parse differently
+// on its own, see test.Generate.
+func NewGeneratingInput(r *rand.Rand) Input {
+	return &input{
+		gen:    &generator{rand: r},
+		budget: &budget{ctx: context.Background(), limits: Limits{MaxSteps: generateMaxSteps}},
+	}
+}
+
+// WithGenerator describes the text a parser accepts so that it can be generated by NewGeneratingInput.
+// It is needed for parsers that read the input directly rather than through the core parsers, or that only accept
+// some of the text their parsers match, e.g. a number in a range. The parser does not generate any text of its own.
+func WithGenerator[T any](parser Parser[T], generate func(r *rand.Rand) string) Parser[T] {
+	return func(in Input) (T, bool, error) {
+		start := in.Checkpoint()
+		extend(in, generate)
+		if i, ok := underlying(in); ok && i.gen != nil && !i.gen.suspended {
+			i.gen.suspended = true
+			defer func() { i.gen.suspended = false }()
+		}
+		match, ok, err := parser(in)
+		if err != nil || !ok {
+			in.Restore(start)
+		}
+		return match, ok, err
+	}
+}
+
+// generating returns the input if it is a generating input that has been consumed in full.
+func generating(in Input) (*input, bool) {
+	i, ok := underlying(in)
+	if !ok || i.gen == nil || i.gen.suspended || i.index < len(i.s) {
+		return nil, false
+	}
+	return i, true
+}
+
+// extend appends generated text to a generating input that has been consumed in full.
+func extend(in Input, generate func(r *rand.Rand) string) {
+	if i, ok := generating(in); ok && len(i.s) < generateMaxLength {
+		i.s += generate(i.gen.rand)
+	}
+}
+
+// repeat reports whether a generating input that has been consumed in full should be extended by another match
+// of an optional or repeated parser, rather than by whatever follows it.
+func repeat(in Input) bool {
+	i, ok := generating(in)
+	return ok && i.gen.rand.Float64() < repeatProbability
+}
+
+// stop reports whether a generating input that has been consumed in full should not be extended by another match
+// of an optional or repeated parser.
+func stop(in Input) bool {
+	i, ok := generating(in)
+	return ok && i.gen.rand.Float64() >= repeatProbability
+}
+
+// shuffle returns the parsers in a random order if the input is being generated, otherwise it returns them unchanged.
+func shuffle[T any](in Input, parsers []Parser[T]) []Parser[T] {
+	i, ok := generating(in)
+	if !ok {
+		return parsers
+	}
+	shuffled := slices.Clone(parsers)
+	i.gen.rand.Shuffle(len(shuffled), func(a, b int) { shuffled[a], shuffled[b] = shuffled[b], shuffled[a] })
+	return shuffled
+}
+
+// generateAnyRune generates the text consumed by parsers that match any rune.
+var generateAnyRune = generateRune(func(r rune) bool { return true })
+
+// generateRune returns a generator of a random rune from generateRunes matching the predicate.
+func generateRune(predicate func(r rune) bool) func(r *rand.Rand) string {
+	matching := sync.OnceValue(func() []rune {
+		var runes []rune
+		for _, candidate := range generateRunes {
+			if predicate(candidate) {
+				runes = append(runes, candidate)
+			}
+		}
+		return runes
+	})
+	return func(r *rand.Rand) string {
+		runes := matching()
+		if len(runes) == 0 {
+			return ""
+		}
+		return string(runes[r.IntN(len(runes))])
+	}
+}
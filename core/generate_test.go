@@ -0,0 +1,191 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	. "github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// number reads digits directly from the input, so it needs a generator description.
+func number(in Input) (int, bool, error) {
+	n := 0
+	for {
+		next, ok := in.Peek(n + 1)
+		if !ok || !unicode.IsDigit(rune(next[n])) {
+			break
+		}
+		n++
+	}
+	digits, _ := in.Take(n)
+	value, err := strconv.Atoi(digits)
+	return value, err == nil, nil
+}
+
+func TestGenerate(t *testing.T) {
+	word := StringFrom(OneOrMore(RuneInRanges(unicode.Letter)))
+	tests := []struct {
+		name   string
+		parser Parser[string]
+		check  func(t *testing.T, s string)
+	}{
+		{
+			name:   "String",
+			parser: String("hello"),
+			check:  func(t *testing.T, s string) { assert.Equal(t, "hello", s) },
+		},
+		{
+			name:   "StringInsensitive",
+			parser: StringInsensitive("hello"),
+			check:  func(t *testing.T, s string) { assert.True(t, strings.EqualFold("hello", s)) },
+		},
+		{
+			name:   "RuneWhere",
+			parser: StringFrom(Times(3, RuneInRanges(unicode.Digit))),
+			check: func(t *testing.T, s string) {
+				assert.Equal(t, 3, utf8.RuneCountInString(s), s)
+				assert.Less(t, strings.IndexFunc(s, func(r rune) bool { return !unicode.IsDigit(r) }), 0, s)
+			},
+		},
+		{
+			name:   "Words",
+			parser: StringFrom(word, StringFrom(ZeroOrMore(SequenceOf2(Whitespace, word)))),
+			check: func(t *testing.T, s string) {
+				for _, w := range strings.Fields(s) {
+					assert.Less(t, strings.IndexFunc(w, func(r rune) bool { return !unicode.IsLetter(r) }), 0, s)
+				}
+			},
+		},
+		{
+			name:   "Any",
+			parser: Any(String("yes"), String("no"), EOF[string]()),
+			check:  func(t *testing.T, s string) { assert.Contains(t, []string{"yes", "no", ""}, s) },
+		},
+		{
+			name:   "Longest",
+			parser: Longest(String("a"), String("ab"), String("abc")),
+			check:  func(t *testing.T, s string) { assert.Contains(t, []string{"a", "ab", "abc"}, s) },
+		},
+		{
+			name:   "StringUntil",
+			parser: StringFrom(String("<"), StringUntil(String(">")), String(">")),
+			check: func(t *testing.T, s string) {
+				assert.True(t, strings.HasPrefix(s, "<") && strings.HasSuffix(s, ">"), s)
+				// The first rune is consumed before looking for the delimiter.
+				assert.NotContains(t, s[2:len(s)-1], ">")
+			},
+		},
+		{
+			name:   "SepBy",
+			parser: StringFrom(SepBy(String("a"), String(", "))),
+			check: func(t *testing.T, s string) {
+				if s != "" {
+					assert.Equal(t, strings.Repeat("a, ", strings.Count(s, ",")+1), s+", ")
+				}
+			},
+		},
+		{
+			name:   "QuotedString",
+			parser: StringFrom(QuotedString('"', '\\')),
+			check: func(t *testing.T, s string) {
+				assert.True(t, strings.HasPrefix(s, `"`) && strings.HasSuffix(s, `"`), s)
+				assert.Equal(t, strings.Count(s, `"`)-2, strings.Count(s, `\"`), s)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewPCG(1, 2))
+			for range 100 {
+				s, ok := Generate(tt.parser, r)
+				if assert.True(t, ok) {
+					tt.check(t, s)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateVariety(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	parser := StringFrom(ZeroOrMore(Any(String("a"), String("b"))))
+	seen := map[string]bool{}
+	for range 100 {
+		s, ok := Generate(parser, r)
+		assert.True(t, ok)
+		seen[s] = true
+	}
+	assert.Greater(t, len(seen), 10)
+}
+
+func TestWithGenerator(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	t.Run("Without a generator", func(t *testing.T) {
+		_, ok := Generate(number, r)
+		assert.False(t, ok)
+	})
+	t.Run("With a generator", func(t *testing.T) {
+		parser := WithGenerator(number, func(r *rand.Rand) string { return strconv.Itoa(r.IntN(1000)) })
+		for range 100 {
+			s, ok := Generate(parser, r)
+			if assert.True(t, ok) {
+				_, err := strconv.Atoi(s)
+				assert.NoError(t, err)
+			}
+		}
+	})
+	t.Run("Parsing is unchanged", func(t *testing.T) {
+		parser := WithGenerator(number, func(r *rand.Rand) string { return "1" })
+		in := NewInput("42x")
+		value, ok, err := parser(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, 42, value)
+		assert.Equal(t, 2, in.Checkpoint().Position())
+	})
+}
+
+func TestGeneratingInput(t *testing.T) {
+	t.Run("Restore discards generated text", func(t *testing.T) {
+		in := NewGeneratingInput(rand.New(rand.NewPCG(1, 2)))
+		start := in.Checkpoint()
+		for {
+			if _, ok, _ := String("abc")(in); ok {
+				break
+			}
+		}
+		s, _ := in.Peek(-3)
+		assert.Equal(t, "abc", s)
+		in.Restore(start)
+		_, ok := in.Peek(1)
+		assert.False(t, ok)
+	})
+	t.Run("Generation is bounded", func(t *testing.T) {
+		var value Parser[string]
+		value = func(in Input) (string, bool, error) {
+			return StringFrom(String("["), value, String("]"))(in)
+		}
+		_, ok := Generate(value, rand.New(rand.NewPCG(1, 2)))
+		assert.False(t, ok)
+	})
+}
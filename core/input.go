@@ -16,19 +16,100 @@ package core
 
 import (
 	"strings"
+	"unicode/utf8"
 )
 
+// Input is the text being parsed and the position parsing has reached.
+//
+// Only inputs created by NewInput, NewInputContext, NewGeneratingInput and NewNestedInput carry user state, limits
+// and generated text. An Input that wraps one of them should delegate Checkpoint and Restore to it and implement
+// Unwrap() Input returning it, so that they still apply. Other implementations have none of them, and WithState
+// returns ErrStateUnsupported.
 type Input interface {
 	Peek(n int) (string, bool)
 	Take(n int) (string, bool)
-	Checkpoint() int
-	Restore(checkpoint int)
+	Checkpoint() Checkpoint
+	Restore(checkpoint Checkpoint)
 	Debug() string
 }
 
-type input struct {
-	s     string
+// Checkpoint is a snapshot of the parsing position and any user state attached to the input.
+type Checkpoint struct {
 	index int
+	state *state
+	// Text of a generating input, which grows as it is parsed, see NewGeneratingInput.
+	generated string
+}
+
+// Position returns the byte offset into the input that the checkpoint was taken at.
+func (c Checkpoint) Position() int {
+	return c.index
+}
+
+// Range is the part of the input a match was parsed from, as byte offsets where End is exclusive.
+type Range struct {
+	Start int
+	End   int
+}
+
+// RangeFrom returns the range of the input taken since the checkpoint.
+func RangeFrom(start Checkpoint, in Input) Range {
+	return Range{Start: start.Position(), End: in.Checkpoint().Position()}
+}
+
+// LineColumn returns the line and column of the byte offset into the text, both counted from 1. Columns are counted in runes.
+func LineColumn(text string, offset int) (line, column int) {
+	var l LineCounter
+	return l.Find(text, offset)
+}
+
+// LineCounter finds the line and column of offsets into a text like LineColumn, without rescanning the text from
+// the start when each offset is at or after the last. The zero value is ready to use.
+type LineCounter struct {
+	line      int
+	lineStart int
+	scanned   int
+}
+
+// Find returns the line and column of the byte offset into the text, which must be the same text on every call.
+func (l *LineCounter) Find(text string, offset int) (line, column int) {
+	offset = min(max(offset, 0), len(text))
+	if offset < l.scanned {
+		*l = LineCounter{}
+	}
+	for {
+		i := strings.IndexByte(text[l.scanned:offset], '\n')
+		if i < 0 {
+			break
+		}
+		l.line++
+		l.scanned += i + 1
+		l.lineStart = l.scanned
+	}
+	l.scanned = offset
+	return l.line + 1, utf8.RuneCountInString(text[l.lineStart:offset]) + 1
+}
+
+type input struct {
+	s      string
+	index  int
+	state  *state
+	budget *budget
+	gen    *generator
+}
+
+// underlying returns the input created by NewInput that the input is or wraps, see Input.
+func underlying(in Input) (*input, bool) {
+	for {
+		switch i := in.(type) {
+		case *input:
+			return i, true
+		case interface{ Unwrap() Input }:
+			in = i.Unwrap()
+		default:
+			return nil, false
+		}
+	}
 }
 
 func NewInput(s string) Input {
@@ -37,6 +118,18 @@ func NewInput(s string) Input {
 	}
 }
 
+// NewNestedInput creates an input for parsing text taken from another input, e.g. the content of a block once its markers
+// are removed, that shares the other input's limits and context, so any work done counts towards them, and starts with
+// its user state. State set while parsing the nested input is not seen by the other input.
+func NewNestedInput(parent Input, s string) Input {
+	in := &input{s: s}
+	if p, ok := underlying(parent); ok {
+		in.state = p.state
+		in.budget = p.budget
+	}
+	return in
+}
+
 func (i *input) Peek(n int) (s string, ok bool) {
 	if i.index+n > len(i.s) || i.index+n < 0 {
 		return
@@ -59,20 +152,31 @@ func (i *input) Take(n int) (s string, ok bool) {
 	return i.s[from:i.index], true
 }
 
-// Take a snapshot of the current parsing position
-func (i *input) Checkpoint() int {
-	return i.index
+// Take a snapshot of the current parsing position and user state
+func (i *input) Checkpoint() Checkpoint {
+	if i.gen != nil {
+		return Checkpoint{index: i.index, state: i.state, generated: i.s}
+	}
+	return Checkpoint{index: i.index, state: i.state}
 }
 
-// Restore the parsing position to a previous snapshot
-func (i *input) Restore(checkpoint int) {
-	if checkpoint < 0 {
-		checkpoint = 0
+// Restore the parsing position and user state to a previous snapshot
+func (i *input) Restore(checkpoint Checkpoint) {
+	if i.gen != nil {
+		i.s = checkpoint.generated
+	}
+	index := checkpoint.index
+	if index < 0 {
+		index = 0
+	}
+	if index > len(i.s) {
+		index = len(i.s)
 	}
-	if checkpoint > len(i.s) {
-		checkpoint = len(i.s)
+	if i.budget != nil && index < i.index {
+		i.budget.backtracks++
 	}
-	i.index = checkpoint
+	i.index = index
+	i.state = checkpoint.state
 }
 
 // Outputs the full input string with a marker at the current parsing position
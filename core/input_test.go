@@ -95,16 +95,59 @@ func TestInput(t *testing.T) {
 		assert.False(t, ok)
 		assert.Equal(t, "", s)
 	})
-	t.Run("Restore negative int", func(t *testing.T) {
+	t.Run("Restore start", func(t *testing.T) {
 		i.Take(10)
-		i.Restore(-1)
-		assert.Equal(t, 0, i.Checkpoint())
+		i.Restore(start)
+		assert.Equal(t, 0, i.Checkpoint().Position())
+	})
+	t.Run("Restore end", func(t *testing.T) {
+		i.Take(len(input))
+		end := i.Checkpoint()
+		i.Restore(start)
+		i.Restore(end)
+		assert.Equal(t, len(input), i.Checkpoint().Position())
+		_, ok := i.Peek(1)
+		assert.False(t, ok)
+		i.Restore(start)
 	})
 	t.Run("Restore beyond end", func(t *testing.T) {
-		i.Restore(len(input))
-		assert.Equal(t, len(input), i.Checkpoint())
+		longer := NewInput(input + input)
+		longer.Take(len(input) + 10)
+		i.Restore(longer.Checkpoint())
+		assert.Equal(t, len(input), i.Checkpoint().Position())
 		_, ok := i.Peek(1)
 		assert.False(t, ok)
 	})
 
 }
+
+func TestRangeFrom(t *testing.T) {
+	in := NewInput(input)
+	in.Take(5)
+	start := in.Checkpoint()
+	in.Take(5)
+	assert.Equal(t, Range{Start: 5, End: 10}, RangeFrom(start, in))
+}
+
+func TestLineColumn(t *testing.T) {
+	text := "ab\nçd\r\n\nef"
+	for _, test := range []struct {
+		offset, line, column int
+	}{
+		{0, 1, 1}, {2, 1, 3}, {3, 2, 1}, {5, 2, 2}, {7, 2, 4}, {8, 3, 1}, {9, 4, 1}, {11, 4, 3}, {-1, 1, 1}, {100, 4, 3},
+	} {
+		line, column := LineColumn(text, test.offset)
+		assert.Equal(t, test.line, line, "line of offset %d", test.offset)
+		assert.Equal(t, test.column, column, "column of offset %d", test.offset)
+	}
+
+	t.Run("Counter matches for any order of offsets", func(t *testing.T) {
+		var lines LineCounter
+		for _, offset := range []int{2, 8, 9, 3, 11, 0, 5} {
+			wantLine, wantColumn := LineColumn(text, offset)
+			line, column := lines.Find(text, offset)
+			assert.Equal(t, wantLine, line, "line of offset %d", offset)
+			assert.Equal(t, wantColumn, column, "column of offset %d", offset)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"context"
+	"fmt"
+)
+
+// Limits bounds the resources a single parse may use. A zero value for any limit means it is unbounded.
+type Limits struct {
+	// MaxSteps is the maximum number of combinator invocations and loop iterations.
+	MaxSteps int
+	// MaxDepth is the maximum nesting depth of combinator invocations.
+	MaxDepth int
+	// MaxBacktracks is the maximum number of times the input may be restored to an earlier position.
+	MaxBacktracks int
+	// MaxInputSize is the maximum length of the input in bytes.
+	MaxInputSize int
+}
+
+// Limit identifies which of the Limits was exceeded.
+type Limit int
+
+const (
+	StepLimit Limit = iota
+	DepthLimit
+	BacktrackLimit
+	InputSizeLimit
+)
+
+func (l Limit) String() string {
+	switch l {
+	case StepLimit:
+		return "steps"
+	case DepthLimit:
+		return "depth"
+	case BacktrackLimit:
+		return "backtracks"
+	case InputSizeLimit:
+		return "input size"
+	}
+	return fmt.Sprintf("Limit(%d)", int(l))
+}
+
+// LimitError is returned when a parse exceeds one of its Limits.
+type LimitError struct {
+	Limit    Limit
+	Max      int
+	Position int
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("parse exceeded maximum %s of %d at position %d", e.Limit, e.Max, e.Position)
+}
+
+// How many steps are taken between checks of the context, as checking it requires taking a lock.
+const contextCheckInterval = 256
+
+type budget struct {
+	ctx        context.Context
+	limits     Limits
+	steps      int
+	depth      int
+	backtracks int
+	err        error
+}
+
+// NewInputContext creates an input that aborts parsing with an error once the context is done or any of the limits are exceeded.
+func NewInputContext(ctx context.Context, s string, limits Limits) (Input, error) {
+	if limits.MaxInputSize > 0 && len(s) > limits.MaxInputSize {
+		return nil, &LimitError{Limit: InputSizeLimit, Max: limits.MaxInputSize}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("parse cancelled: %w", err)
+	}
+	return &input{s: s, budget: &budget{ctx: ctx, limits: limits}}, nil
+}
+
+// Parse runs the parser over the string, aborting with an error once the context is done or any of the limits are exceeded.
+func Parse[T any](ctx context.Context, parser Parser[T], s string, limits Limits) (T, bool, error) {
+	var t T
+	in, err := NewInputContext(ctx, s, limits)
+	if err != nil {
+		return t, false, err
+	}
+	match, ok, err := parser(in)
+	// Parsers are not obliged to propagate errors from the parsers they wrap so make sure the limit isn't lost.
+	if b := in.(*input).budget; b.err != nil {
+		return t, false, b.err
+	}
+	return match, ok, err
+}
+
+func (b *budget) step(position int) error {
+	if b.err != nil {
+		return b.err
+	}
+	b.steps++
+	switch {
+	case b.limits.MaxSteps > 0 && b.steps > b.limits.MaxSteps:
+		b.err = &LimitError{Limit: StepLimit, Max: b.limits.MaxSteps, Position: position}
+	case b.limits.MaxDepth > 0 && b.depth > b.limits.MaxDepth:
+		b.err = &LimitError{Limit: DepthLimit, Max: b.limits.MaxDepth, Position: position}
+	case b.limits.MaxBacktracks > 0 && b.backtracks > b.limits.MaxBacktracks:
+		b.err = &LimitError{Limit: BacktrackLimit, Max: b.limits.MaxBacktracks, Position: position}
+	case b.steps%contextCheckInterval == 0 && b.ctx.Err() != nil:
+		b.err = fmt.Errorf("parse cancelled at position %d: %w", position, b.ctx.Err())
+	}
+	return b.err
+}
+
+// step records a single unit of work, such as a loop iteration, against the input's budget.
+func step(in Input) error {
+	i, ok := underlying(in)
+	if !ok || i.budget == nil {
+		return nil
+	}
+	return i.budget.step(i.index)
+}
+
+// enter records the start of a combinator invocation against the input's budget.
+// It must be paired with a call to leave once the combinator returns.
+func enter(in Input) error {
+	i, ok := underlying(in)
+	if !ok || i.budget == nil {
+		return nil
+	}
+	i.budget.depth++
+	return i.budget.step(i.index)
+}
+
+// leave records the end of a combinator invocation.
+func leave(in Input) {
+	if i, ok := underlying(in); ok && i.budget != nil {
+		i.budget.depth--
+	}
+}
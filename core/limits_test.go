@@ -0,0 +1,139 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// nested matches balanced parentheses, recursing once per level.
+func nested(in Input) (string, bool, error) {
+	return StringFrom(SequenceOf3(Rune('('), Optional(nested), Rune(')')))(in)
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Within limits", func(t *testing.T) {
+		match, ok, err := Parse(context.Background(), StringFrom(ZeroOrMore(AnyRune)), "ABC", Limits{MaxSteps: 100, MaxDepth: 10, MaxBacktracks: 10, MaxInputSize: 10})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "ABC", match)
+	})
+	t.Run("No limits", func(t *testing.T) {
+		match, ok, err := Parse(context.Background(), nested, strings.Repeat("(", 1000)+strings.Repeat(")", 1000), Limits{})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Len(t, match, 2000)
+	})
+	t.Run("Max steps", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), ZeroOrMore(AnyRune), strings.Repeat("A", 100), Limits{MaxSteps: 10})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, StepLimit, limitErr.Limit)
+		assert.Equal(t, 10, limitErr.Max)
+		assert.Equal(t, 9, limitErr.Position)
+	})
+	t.Run("Max steps in string loops", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), StringWhileNot(EOF[string]()), strings.Repeat("A", 100), Limits{MaxSteps: 10})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, StepLimit, limitErr.Limit)
+	})
+	t.Run("Max depth", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), nested, strings.Repeat("(", 100)+strings.Repeat(")", 100), Limits{MaxDepth: 20})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, DepthLimit, limitErr.Limit)
+	})
+	t.Run("Max steps through wrapping input", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), func(in Input) ([]string, bool, error) {
+			return ZeroOrMore(AnyRune)(wrappedInput{in})
+		}, strings.Repeat("A", 100), Limits{MaxSteps: 10})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, StepLimit, limitErr.Limit)
+	})
+	t.Run("Case insensitive matches do not backtrack", func(t *testing.T) {
+		match, ok, err := Parse(context.Background(), ZeroOrMore(StringInsensitive("ab")), "ABABABABAB", Limits{MaxBacktracks: 3})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []string{"AB", "AB", "AB", "AB", "AB"}, match)
+	})
+	t.Run("Max steps through nested input", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), func(in Input) ([]string, bool, error) {
+			return ZeroOrMore(AnyRune)(NewNestedInput(in, strings.Repeat("A", 100)))
+		}, "", Limits{MaxSteps: 10})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, StepLimit, limitErr.Limit)
+	})
+	t.Run("Max backtracks", func(t *testing.T) {
+		word := Any(StringFrom(String("AB"), String("D")), String("ABC"))
+		_, ok, err := Parse(context.Background(), ZeroOrMore(word), strings.Repeat("ABC", 100), Limits{MaxBacktracks: 5})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, BacktrackLimit, limitErr.Limit)
+	})
+	t.Run("Max input size", func(t *testing.T) {
+		_, ok, err := Parse(context.Background(), AnyRune, "ABC", Limits{MaxInputSize: 2})
+		assert.False(t, ok)
+		var limitErr *LimitError
+		assert.ErrorAs(t, err, &limitErr)
+		assert.Equal(t, InputSizeLimit, limitErr.Limit)
+	})
+	t.Run("Context cancelled before parsing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, ok, err := Parse(ctx, AnyRune, "ABC", Limits{})
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("Context cancelled while parsing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		calls := 0
+		parser := ZeroOrMore(func(in Input) (string, bool, error) {
+			calls++
+			if calls == 10 {
+				cancel()
+			}
+			return AnyRune(in)
+		})
+		_, ok, err := Parse(ctx, parser, strings.Repeat("A", 10000), Limits{})
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Less(t, calls, 10000)
+	})
+	t.Run("Limit is reported even if swallowed", func(t *testing.T) {
+		swallow := func(in Input) (string, bool, error) {
+			ZeroOrMore(AnyRune)(in)
+			return "", true, nil
+		}
+		_, ok, err := Parse(context.Background(), swallow, strings.Repeat("A", 100), Limits{MaxSteps: 10})
+		assert.False(t, ok)
+		assert.True(t, errors.As(err, new(*LimitError)))
+	})
+}
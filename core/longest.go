@@ -0,0 +1,112 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Longest tries all of the given parsers, returning the match that consumed the most input or rolls back the input if no match is found.
+// Unlike Any the order of the parsers only matters when two matches are the same length, in which case the first wins.
+func Longest[T any](parsers ...Parser[T]) Parser[T] {
+	return func(in Input) (T, bool, error) {
+		if _, ok := generating(in); ok {
+			// Generate any of the alternatives rather than always the longest.
+			return Any(parsers...)(in)
+		}
+		var t T
+		if err := enter(in); err != nil {
+			return t, false, err
+		}
+		defer leave(in)
+		start := in.Checkpoint()
+		var longest T
+		var end Checkpoint
+		found := false
+		for _, parser := range parsers {
+			match, ok, err := parser(in)
+			if err != nil {
+				in.Restore(start)
+				return t, false, err
+			}
+			if ok && (!found || in.Checkpoint().index > end.index) {
+				longest, end, found = match, in.Checkpoint(), true
+			}
+			in.Restore(start)
+		}
+		if !found {
+			return t, false, nil
+		}
+		in.Restore(end)
+		return longest, true, nil
+	}
+}
+
+// AmbiguityError is returned by Ambiguous when more than one of its parsers match.
+type AmbiguityError struct {
+	Position int
+	// Indexes of the parsers that matched.
+	Parsers []int
+	// Matched input for each of the parsers that matched.
+	Matches []string
+}
+
+func (e *AmbiguityError) Error() string {
+	alternatives := make([]string, len(e.Parsers))
+	for i := range e.Parsers {
+		alternatives[i] = fmt.Sprintf("%d (%q)", e.Parsers[i], e.Matches[i])
+	}
+	return fmt.Sprintf("ambiguous match at position %d: parsers %s all match", e.Position, strings.Join(alternatives, ", "))
+}
+
+// Ambiguous tries all of the given parsers, returning the match if exactly one matches, an error if more than one matches or rolls back the input if no match is found.
+// It is intended for debugging grammars that rely on the ordering of Any.
+func Ambiguous[T any](parsers ...Parser[T]) Parser[T] {
+	return func(in Input) (T, bool, error) {
+		var t T
+		if err := enter(in); err != nil {
+			return t, false, err
+		}
+		defer leave(in)
+		start := in.Checkpoint()
+		var match T
+		var end Checkpoint
+		var ambiguity AmbiguityError
+		for i, parser := range parsers {
+			m, ok, err := parser(in)
+			if err != nil {
+				in.Restore(start)
+				return t, false, err
+			}
+			if ok {
+				matched, _ := in.Peek(start.index - in.Checkpoint().index)
+				ambiguity.Parsers = append(ambiguity.Parsers, i)
+				ambiguity.Matches = append(ambiguity.Matches, matched)
+				match, end = m, in.Checkpoint()
+			}
+			in.Restore(start)
+		}
+		switch len(ambiguity.Parsers) {
+		case 0:
+			return t, false, nil
+		case 1:
+			in.Restore(end)
+			return match, true, nil
+		}
+		ambiguity.Position = start.index
+		return t, false, &ambiguity
+	}
+}
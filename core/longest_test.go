@@ -0,0 +1,108 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongest(t *testing.T) {
+	tests := []ParserTest[string]{
+		{
+			Name:           "no match",
+			Input:          "C",
+			Parser:         core.Longest(core.String("A"), core.String("AB")),
+			ExpectedOK:     false,
+			RemainingInput: "C",
+		},
+		{
+			Name:           "no match rolls back input even if one of the parsers consumed input",
+			Input:          "C",
+			Parser:         core.Longest(NaughtyParser[string](), core.Rune('A')),
+			ExpectedOK:     false,
+			RemainingInput: "C",
+		},
+		{
+			Name:           "longest match wins regardless of order",
+			Input:          "MONDAY!",
+			Parser:         core.Longest(core.StringInsensitive("mon"), core.StringInsensitive("monday"), core.StringInsensitive("mond")),
+			ExpectedMatch:  "MONDAY",
+			ExpectedOK:     true,
+			RemainingInput: "!",
+		},
+		{
+			Name:           "first match wins a tie",
+			Input:          "AB",
+			Parser:         core.Longest(core.String("A"), core.StringInsensitive("a")),
+			ExpectedMatch:  "A",
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "later parsers run after a naughty parser",
+			Input:          "AB",
+			Parser:         core.Longest(NaughtyParser[string](), core.String("AB")),
+			ExpectedMatch:  "AB",
+			ExpectedOK:     true,
+			RemainingInput: "",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestAmbiguous(t *testing.T) {
+	tests := []ParserTest[string]{
+		{
+			Name:           "no match",
+			Input:          "C",
+			Parser:         core.Ambiguous(core.String("A"), core.String("B")),
+			ExpectedOK:     false,
+			RemainingInput: "C",
+		},
+		{
+			Name:           "single match",
+			Input:          "BC",
+			Parser:         core.Ambiguous(core.String("A"), core.String("B")),
+			ExpectedMatch:  "B",
+			ExpectedOK:     true,
+			RemainingInput: "C",
+		},
+		{
+			Name:           "multiple matches",
+			Input:          "ABC",
+			Parser:         core.Ambiguous(core.String("A"), core.String("B"), core.String("AB")),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "ABC",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestAmbiguityError(t *testing.T) {
+	in := core.NewInput("xmar")
+	in.Take(1)
+	_, _, err := core.Ambiguous(core.StringInsensitive("march"), core.StringInsensitive("mar"), core.StringInsensitive("ma"))(in)
+	var ambiguity *core.AmbiguityError
+	assert.ErrorAs(t, err, &ambiguity)
+	assert.Equal(t, 1, ambiguity.Position)
+	assert.Equal(t, []int{1, 2}, ambiguity.Parsers)
+	assert.Equal(t, []string{"mar", "ma"}, ambiguity.Matches)
+	assert.Equal(t, `ambiguous match at position 1: parsers 1 ("mar"), 2 ("ma") all match`, err.Error())
+}
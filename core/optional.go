@@ -14,25 +14,22 @@
 
 package core
 
-type Match[T any] interface {
-	Values() T
-	Ok() bool
-}
-
-type match[T any] struct {
+// Match is the result of a parser that may not have matched, e.g. Optional.
+// Like the tuples it is a struct so that returning it from a parser does not allocate.
+type Match[T any] struct {
 	value T
 	ok    bool
 }
 
 func NewMatch[T any](value T, ok bool) Match[T] {
-	return match[T]{value: value, ok: ok}
+	return Match[T]{value: value, ok: ok}
 }
 
-func (m match[T]) Values() T {
+func (m Match[T]) Values() T {
 	return m.value
 }
 
-func (m match[T]) Ok() bool {
+func (m Match[T]) Ok() bool {
 	return m.ok
 }
 
@@ -40,10 +37,21 @@ func (m match[T]) Ok() bool {
 // The top level parser will always return true, unless an error occurs.
 func Optional[T any](parser Parser[T]) Parser[Match[T]] {
 	return func(in Input) (Match[T], bool, error) {
+		if err := enter(in); err != nil {
+			return Match[T]{}, false, err
+		}
+		defer leave(in)
+		if stop(in) {
+			return Match[T]{}, true, nil
+		}
+		start := in.Checkpoint()
 		m, ok, err := parser(in)
 		if err != nil {
-			return match[T]{}, false, err
+			return Match[T]{}, false, err
+		}
+		if !ok {
+			in.Restore(start)
 		}
-		return match[T]{value: m, ok: ok}, true, nil
+		return Match[T]{value: m, ok: ok}, true, nil
 	}
 }
@@ -18,8 +18,12 @@ package core
 // It returns as soon as one of the parsers are successful or rolls back when none are.
 func Or[A any, B any](a Parser[A], b Parser[B]) Parser[Tuple2[Match[A], Match[B]]] {
 	return func(in Input) (Tuple2[Match[A], Match[B]], bool, error) {
+		if err := enter(in); err != nil {
+			return Tuple2[Match[A], Match[B]]{}, false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
-		var res tuple2[Match[A], Match[B]]
+		var res Tuple2[Match[A], Match[B]]
 
 		matchA, okA, errA := a(in)
 		if errA != nil {
@@ -43,7 +43,7 @@ func TestOr(t *testing.T) {
 			Name:          "first match",
 			Input:         "A",
 			Parser:        Or(Rune('A'), Rune('B')),
-			ExpectedMatch: NewTuple2[Match[string], Match[string]](NewMatch("A", true), nil),
+			ExpectedMatch: NewTuple2(NewMatch("A", true), Match[string]{}),
 			ExpectedOK:    true,
 		},
 		{
@@ -0,0 +1,143 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "fmt"
+
+// Member is a parser within a Permutation along with how its match is assigned to the result.
+type Member[S any] struct {
+	name     string
+	required bool
+	parse    func(in Input, result *S) (bool, error)
+}
+
+func member[S, T any](name string, required bool, parser Parser[T], assign func(result *S, match T)) Member[S] {
+	return Member[S]{
+		name:     name,
+		required: required,
+		parse: func(in Input, result *S) (bool, error) {
+			match, ok, err := parser(in)
+			if err != nil || !ok {
+				return false, err
+			}
+			assign(result, match)
+			return true, nil
+		},
+	}
+}
+
+// RequiredMember creates a member of a Permutation that must match exactly once.
+func RequiredMember[S, T any](name string, parser Parser[T], assign func(result *S, match T)) Member[S] {
+	return member(name, true, parser, assign)
+}
+
+// OptionalMember creates a member of a Permutation that may match at most once.
+func OptionalMember[S, T any](name string, parser Parser[T], assign func(result *S, match T)) Member[S] {
+	return member(name, false, parser, assign)
+}
+
+// DuplicateError is returned by Permutation when one of its members matches more than once.
+type DuplicateError struct {
+	Name     string
+	Position int
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("duplicate %s at position %d", e.Name, e.Position)
+}
+
+// Permutation matches each of the members at most once in any order, separated by the separator, and assigns their matches to the result.
+// It rolls back the input if any of the required members are not found and returns an error if any member matches more than once.
+// A nil separator means members are not separated.
+func Permutation[S, D any](separator Parser[D], members ...Member[S]) Parser[S] {
+	return func(in Input) (S, bool, error) {
+		var zero, result S
+		if err := enter(in); err != nil {
+			return zero, false, err
+		}
+		defer leave(in)
+		start := in.Checkpoint()
+		matched := make([]bool, len(members))
+		// Once every member has matched, one more iteration checks for a duplicate after them.
+		for i := 0; i <= len(members); i++ {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return zero, false, err
+			}
+			beforeSeparator := in.Checkpoint()
+			if i > 0 && separator != nil {
+				_, ok, err := separator(in)
+				if err != nil {
+					in.Restore(start)
+					return zero, false, err
+				}
+				if !ok {
+					break
+				}
+			}
+			ok, err := permute(in, members, matched, &result)
+			if err != nil {
+				in.Restore(start)
+				return zero, false, err
+			}
+			if !ok {
+				in.Restore(beforeSeparator)
+				break
+			}
+		}
+		for i, m := range members {
+			if m.required && !matched[i] {
+				in.Restore(start)
+				return zero, false, nil
+			}
+		}
+		return result, true, nil
+	}
+}
+
+// permute matches the first member that hasn't already been matched.
+// If none do it checks whether any of the already matched members would match again.
+func permute[S any](in Input, members []Member[S], matched []bool, result *S) (bool, error) {
+	start := in.Checkpoint()
+	for i, m := range members {
+		if matched[i] {
+			continue
+		}
+		ok, err := m.parse(in, result)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			matched[i] = true
+			return true, nil
+		}
+		in.Restore(start)
+	}
+	for i, m := range members {
+		if !matched[i] {
+			continue
+		}
+		var discard S
+		ok, err := m.parse(in, &discard)
+		in.Restore(start)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return false, &DuplicateError{Name: m.name, Position: start.index}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type task struct {
+	Due      string
+	Every    string
+	Priority string
+}
+
+// field matches name:value, where value is made up of digits and dashes, and returns the value.
+func field(name string) core.Parser[string] {
+	parser := core.SequenceOf2(core.String(name+":"), core.StringFrom(core.SkipOneOrMore(core.RuneIn("0123456789-"))))
+	return func(in core.Input) (string, bool, error) {
+		match, ok, err := parser(in)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		_, value := match.Values()
+		return value, true, nil
+	}
+}
+
+func taskMetadata(separator core.Parser[string]) core.Parser[task] {
+	return core.Permutation(separator,
+		core.RequiredMember("due", field("due"), func(t *task, due string) { t.Due = due }),
+		core.OptionalMember("every", field("every"), func(t *task, every string) { t.Every = every }),
+		core.OptionalMember("priority", field("priority"), func(t *task, priority string) { t.Priority = priority }),
+	)
+}
+
+func TestPermutation(t *testing.T) {
+	tests := []ParserTest[task]{
+		{
+			Name:          "in order",
+			Input:         "due:2024-01-01 every:7 priority:1",
+			Parser:        taskMetadata(core.InlineWhitespace),
+			ExpectedMatch: task{Due: "2024-01-01", Every: "7", Priority: "1"},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "out of order",
+			Input:         "priority:1 due:2024-01-01 every:7",
+			Parser:        taskMetadata(core.InlineWhitespace),
+			ExpectedMatch: task{Due: "2024-01-01", Every: "7", Priority: "1"},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "optional members missing",
+			Input:         "due:2024-01-01",
+			Parser:        taskMetadata(core.InlineWhitespace),
+			ExpectedMatch: task{Due: "2024-01-01"},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "required member missing",
+			Input:          "every:7 priority:1",
+			Parser:         taskMetadata(core.InlineWhitespace),
+			ExpectedOK:     false,
+			RemainingInput: "every:7 priority:1",
+		},
+		{
+			Name:           "duplicate member",
+			Input:          "due:2024-01-01 every:7 due:2024-01-02",
+			Parser:         taskMetadata(core.InlineWhitespace),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "due:2024-01-01 every:7 due:2024-01-02",
+		},
+		{
+			Name:           "duplicate after every member has matched",
+			Input:          "due:2024-01-01 every:7 priority:1 due:2024-01-02",
+			Parser:         taskMetadata(core.InlineWhitespace),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "due:2024-01-01 every:7 priority:1 due:2024-01-02",
+		},
+		{
+			Name:           "trailing separator is not consumed",
+			Input:          "every:7 due:2024-01-01 ",
+			Parser:         taskMetadata(core.InlineWhitespace),
+			ExpectedMatch:  task{Due: "2024-01-01", Every: "7"},
+			ExpectedOK:     true,
+			RemainingInput: " ",
+		},
+		{
+			Name:           "stops at unknown member",
+			Input:          "due:2024-01-01 colour:1 priority:1",
+			Parser:         taskMetadata(core.InlineWhitespace),
+			ExpectedMatch:  task{Due: "2024-01-01"},
+			ExpectedOK:     true,
+			RemainingInput: " colour:1 priority:1",
+		},
+		{
+			Name:           "optional separator",
+			Input:          "due:2024-01-01 priority:1every:7",
+			Parser:         taskMetadata(core.OptionalInlineWhitespace),
+			ExpectedMatch:  task{Due: "2024-01-01", Every: "7", Priority: "1"},
+			ExpectedOK:     true,
+			RemainingInput: "",
+		},
+		{
+			Name:          "no separator",
+			Input:         "priority:1due:2024-01-01",
+			Parser:        taskMetadata(nil),
+			ExpectedMatch: task{Due: "2024-01-01", Priority: "1"},
+			ExpectedOK:    true,
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestPermutationDuplicateError(t *testing.T) {
+	_, _, err := taskMetadata(core.InlineWhitespace)(core.NewInput("every:7 due:2024-01-01 every:1"))
+	var duplicate *core.DuplicateError
+	assert.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, "every", duplicate.Name)
+	assert.Equal(t, 23, duplicate.Position)
+	assert.Equal(t, "duplicate every at position 23", err.Error())
+
+	_, _, err = taskMetadata(core.InlineWhitespace)(core.NewInput("due:1 every:2 priority:3 due:4"))
+	assert.ErrorAs(t, err, &duplicate)
+	assert.Equal(t, "due", duplicate.Name)
+	assert.Equal(t, 25, duplicate.Position)
+}
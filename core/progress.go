@@ -0,0 +1,52 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// NoProgressError is returned by repeating combinators when their parser succeeds without consuming any input.
+// Without it the combinator would keep matching the same empty string forever.
+type NoProgressError struct {
+	Combinator string
+	Parser     string
+	Position   int
+}
+
+func (e *NoProgressError) Error() string {
+	return fmt.Sprintf("%s: parser %s matched without consuming input at position %d", e.Combinator, e.Parser, e.Position)
+}
+
+// parserName returns a best effort name for the parser's underlying function, e.g. core.Optional[...].func1.
+func parserName(parser any) string {
+	fn := runtime.FuncForPC(reflect.ValueOf(parser).Pointer())
+	if fn == nil {
+		return "unknown"
+	}
+	name := fn.Name()
+	// Generic instantiations contain slashes within the brackets so only trim the package path preceding them.
+	path := name
+	if i := strings.Index(path, "["); i >= 0 {
+		path = path[:i]
+	}
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
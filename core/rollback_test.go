@@ -0,0 +1,57 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+)
+
+type depth int
+
+func TestRollback(t *testing.T) {
+	ab := core.String("AB")
+	inputs := []string{"ABABC", "AB AB\nC", "A1B2", "aBab"}
+
+	t.Run("Rune", func(t *testing.T) { CheckRollback(t, core.RuneIn("AB"), inputs...) })
+	t.Run("String", func(t *testing.T) { CheckRollback(t, ab, inputs...) })
+	t.Run("StringInsensitive", func(t *testing.T) { CheckRollback(t, core.StringInsensitive("ab"), inputs...) })
+	t.Run("StringFrom", func(t *testing.T) { CheckRollback(t, core.StringFrom(ab, core.Rune('C')), inputs...) })
+	t.Run("StringUntil", func(t *testing.T) { CheckRollback(t, core.StringUntil(core.Rune('C')), inputs...) })
+	t.Run("StringWhileNot", func(t *testing.T) { CheckRollback(t, core.StringWhileNot(core.Rune('C')), inputs...) })
+	t.Run("Any", func(t *testing.T) { CheckRollback(t, core.Any(ab, core.String("ABC")), inputs...) })
+	t.Run("All", func(t *testing.T) { CheckRollback(t, core.All(ab, core.String("ABC")), inputs...) })
+	t.Run("Longest", func(t *testing.T) { CheckRollback(t, core.Longest(ab, core.String("ABAB")), inputs...) })
+	t.Run("Optional", func(t *testing.T) { CheckRollback(t, core.Optional(ab), inputs...) })
+	t.Run("Or", func(t *testing.T) { CheckRollback(t, core.Or(ab, core.Rune('C')), inputs...) })
+	t.Run("SequenceOf3", func(t *testing.T) { CheckRollback(t, core.SequenceOf3(ab, ab, core.Rune('C')), inputs...) })
+	t.Run("Times", func(t *testing.T) { CheckRollback(t, core.Times(2, ab), inputs...) })
+	t.Run("Between", func(t *testing.T) { CheckRollback(t, core.Between(2, 3, core.RuneIn("AB")), inputs...) })
+	t.Run("SkipBetween", func(t *testing.T) { CheckRollback(t, core.SkipBetween(2, 3, core.RuneIn("AB")), inputs...) })
+	t.Run("SepBy1", func(t *testing.T) { CheckRollback(t, core.SepBy1(ab, core.Rune(' ')), inputs...) })
+	t.Run("QuotedString", func(t *testing.T) { CheckRollback(t, core.QuotedString('A', 'B'), inputs...) })
+	t.Run("Until", func(t *testing.T) { CheckRollback(t, core.Until(core.RuneIn("AB"), core.Rune('C')), inputs...) })
+	t.Run("WhileNot", func(t *testing.T) { CheckRollback(t, core.WhileNot(core.RuneIn("AB"), core.Rune('C')), inputs...) })
+	t.Run("Whitespace", func(t *testing.T) { CheckRollback(t, core.SequenceOf2(core.Whitespace, ab), inputs...) })
+	t.Run("WithState", func(t *testing.T) {
+		CheckRollback(t, core.WithState(depth(1), core.SequenceOf2(ab, core.Rune('C'))), inputs...)
+	})
+}
+
+func TestLookahead(t *testing.T) {
+	CheckLookahead(t, core.EOF[string](), "", "A", "AB")
+}
@@ -15,8 +15,12 @@
 package core
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Rune matches a single rune.
@@ -27,34 +31,90 @@ func Rune(r rune) Parser[string] {
 }
 
 // RuneWhere matches a single rune when the predicate is true.
+// Bytes that are not valid UTF-8 are passed to the predicate one at a time as utf8.RuneError.
 func RuneWhere(predicate func(r rune) bool) Parser[string] {
+	generate := generateRune(predicate)
 	return func(in Input) (string, bool, error) {
-		match, ok := in.Peek(1)
-		if !ok {
+		extend(in, generate)
+		r, size := PeekRune(in)
+		if size == 0 || !predicate(r) {
 			return "", false, nil
 		}
-		if !predicate(rune(match[0])) {
-			return "", false, nil
-		}
-		res, _ := in.Take(1)
+		res, _ := in.Take(size)
 		return res, true, nil
 	}
 }
 
+// PeekRune decodes the next rune without consuming it, returning a size of zero at the end of the input.
+func PeekRune(in Input) (rune, int) {
+	if i, ok := in.(*input); ok {
+		if i.index >= len(i.s) {
+			return utf8.RuneError, 0
+		}
+		return utf8.DecodeRuneInString(i.s[i.index:])
+	}
+	// Peek fails when fewer bytes remain than requested so try progressively shorter lengths.
+	for n := utf8.UTFMax; n > 0; n-- {
+		if s, ok := in.Peek(n); ok {
+			return utf8.DecodeRuneInString(s)
+		}
+	}
+	return utf8.RuneError, 0
+}
+
+// Unexpected describes what was found at the current position instead of what was expected, for use as the reason
+// of a syntax error, e.g. expected digit, found 'x' or expected ']', found end of input.
+func Unexpected(in Input, expected string) string {
+	found := "end of input"
+	if r, size := PeekRune(in); size > 0 {
+		found = strconv.QuoteRune(r)
+	}
+	return fmt.Sprintf("expected %s, found %s", expected, found)
+}
+
 // RuneIn matches a single rune when the rune is in the given string.
 func RuneIn(s string) Parser[string] {
-	return RuneWhere(func(r rune) bool {
-		return strings.Contains(s, string(r))
-	})
+	set := newRuneSet(s)
+	return RuneWhere(set.contains)
 }
 
 // RuneNotIn matches a single rune when the rune is not in the given string.
 func RuneNotIn(s string) Parser[string] {
+	set := newRuneSet(s)
 	return RuneWhere(func(r rune) bool {
-		return !strings.Contains(s, string(r))
+		return !set.contains(r)
 	})
 }
 
+// runeSet is the set of runes in a string, with a bitmap of the ASCII runes so that most lookups are a single bit test.
+type runeSet struct {
+	ascii [2]uint64
+	other []rune
+}
+
+func newRuneSet(s string) *runeSet {
+	set := &runeSet{}
+	for i, r := range s {
+		if r == utf8.RuneError && !strings.HasPrefix(s[i:], string(utf8.RuneError)) {
+			// Invalid UTF-8 in the string doesn't match the utf8.RuneError passed for invalid input.
+			continue
+		}
+		if r < utf8.RuneSelf {
+			set.ascii[r/64] |= 1 << (r % 64)
+		} else {
+			set.other = append(set.other, r)
+		}
+	}
+	return set
+}
+
+func (set *runeSet) contains(r rune) bool {
+	if r >= 0 && r < utf8.RuneSelf {
+		return set.ascii[r/64]&(1<<(r%64)) != 0
+	}
+	return slices.Contains(set.other, r)
+}
+
 // RuneInRanges matches a single rune when the rune is in one of the given unicode ranges.
 func RuneInRanges(ranges ...*unicode.RangeTable) Parser[string] {
 	return RuneWhere(func(r rune) bool { return unicode.IsOneOf(ranges, r) })
@@ -20,6 +20,7 @@ import (
 
 	"github.com/liamawhite/parse/core"
 	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRuneWhere(t *testing.T) {
@@ -104,6 +105,45 @@ func TestRuneWhere(t *testing.T) {
 			ExpectedMatch: "a",
 			ExpectedOK:    true,
 		},
+		{
+			Name:           "Letter: multibyte match",
+			Input:          "éa",
+			Parser:         core.Letter,
+			ExpectedMatch:  "é",
+			ExpectedOK:     true,
+			RemainingInput: "a",
+		},
+		{
+			Name:           "RuneIn: multibyte match",
+			Input:          "–-",
+			Parser:         core.RuneIn("-–"),
+			ExpectedMatch:  "–",
+			ExpectedOK:     true,
+			RemainingInput: "-",
+		},
+		{
+			Name:           "RuneIn: no match on the first byte of a multibyte rune",
+			Input:          "é",
+			Parser:         core.RuneIn("\xc3"),
+			ExpectedOK:     false,
+			RemainingInput: "é",
+		},
+		{
+			Name:           "RuneInRanges: multibyte match",
+			Input:          "日本",
+			Parser:         core.RuneInRanges(unicode.Han),
+			ExpectedMatch:  "日",
+			ExpectedOK:     true,
+			RemainingInput: "本",
+		},
+		{
+			Name:           "AnyRune: invalid UTF-8",
+			Input:          "\xffa",
+			Parser:         core.AnyRune,
+			ExpectedMatch:  "\xff",
+			ExpectedOK:     true,
+			RemainingInput: "a",
+		},
 	}
 	RunTests(t, tests)
 }
@@ -137,3 +177,12 @@ func TestDigit(t *testing.T) {
 	}
 	RunTests(t, tests)
 }
+
+func TestUnexpected(t *testing.T) {
+	in := core.NewInput("ab😀")
+	assert.Equal(t, "expected digit, found 'a'", core.Unexpected(in, "digit"))
+	in.Take(2)
+	assert.Equal(t, "expected digit, found '😀'", core.Unexpected(in, "digit"))
+	in.Take(4)
+	assert.Equal(t, "expected digit, found end of input", core.Unexpected(in, "digit"))
+}
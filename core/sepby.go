@@ -0,0 +1,78 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+// SepBy matches the parser zero or more times, separated by the separator, e.g. the items of a comma separated list.
+// A trailing separator is not consumed.
+func SepBy[T, S any](parser Parser[T], separator Parser[S]) Parser[[]T] {
+	return sepBy(0, parser, separator)
+}
+
+// SepBy1 matches the parser one or more times, separated by the separator, or rolls back the input.
+// A trailing separator is not consumed.
+func SepBy1[T, S any](parser Parser[T], separator Parser[S]) Parser[[]T] {
+	return sepBy(1, parser, separator)
+}
+
+func sepBy[T, S any](min int, parser Parser[T], separator Parser[S]) Parser[[]T] {
+	return func(in Input) ([]T, bool, error) {
+		if err := enter(in); err != nil {
+			return nil, false, err
+		}
+		defer leave(in)
+		start := in.Checkpoint()
+		match := make([]T, 0)
+		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return nil, false, err
+			}
+			if len(match) >= min && stop(in) {
+				break
+			}
+			before := in.Checkpoint()
+			if len(match) > 0 {
+				_, ok, err := separator(in)
+				if err != nil {
+					in.Restore(start)
+					return nil, false, err
+				}
+				if !ok {
+					break
+				}
+			}
+			m, ok, err := parser(in)
+			if err != nil {
+				in.Restore(start)
+				return nil, false, err
+			}
+			if !ok {
+				in.Restore(before)
+				break
+			}
+			// A separator and match that consumed no input would match forever.
+			if len(match) > 0 && in.Checkpoint().index == before.index {
+				in.Restore(before)
+				break
+			}
+			match = append(match, m)
+		}
+		if len(match) < min {
+			in.Restore(start)
+			return nil, false, nil
+		}
+		return match, true, nil
+	}
+}
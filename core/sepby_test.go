@@ -0,0 +1,97 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+)
+
+func TestSepBy(t *testing.T) {
+	comma := core.Rune(',')
+	tests := []ParserTest[[]string]{
+		{
+			Name:           "SepBy: no match",
+			Input:          ",A",
+			Parser:         core.SepBy(core.Rune('A'), comma),
+			ExpectedMatch:  []string{},
+			ExpectedOK:     true,
+			RemainingInput: ",A",
+		},
+		{
+			Name:          "SepBy: single",
+			Input:         "A",
+			Parser:        core.SepBy(core.Rune('A'), comma),
+			ExpectedMatch: []string{"A"},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "SepBy: many",
+			Input:          "A,A,AB",
+			Parser:         core.SepBy(core.Rune('A'), comma),
+			ExpectedMatch:  []string{"A", "A", "A"},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "SepBy: trailing separator is not consumed",
+			Input:          "A,A,",
+			Parser:         core.SepBy(core.Rune('A'), comma),
+			ExpectedMatch:  []string{"A", "A"},
+			ExpectedOK:     true,
+			RemainingInput: ",",
+		},
+		{
+			Name:           "SepBy: separator that consumes input",
+			Input:          "A , A ,B",
+			Parser:         core.SepBy(core.Rune('A'), core.StringFrom(core.OptionalWhitespace, comma, core.OptionalWhitespace)),
+			ExpectedMatch:  []string{"A", "A"},
+			ExpectedOK:     true,
+			RemainingInput: " ,B",
+		},
+		{
+			Name:           "SepBy: zero width separator and match stop",
+			Input:          "AB",
+			Parser:         core.SepBy(core.OptionalWhitespace, core.OptionalWhitespace),
+			ExpectedMatch:  []string{""},
+			ExpectedOK:     true,
+			RemainingInput: "AB",
+		},
+		{
+			Name:           "SepBy1: no match",
+			Input:          "B",
+			Parser:         core.SepBy1(core.Rune('A'), comma),
+			ExpectedOK:     false,
+			RemainingInput: "B",
+		},
+		{
+			Name:          "SepBy1: many",
+			Input:         "A,A",
+			Parser:        core.SepBy1(core.Rune('A'), comma),
+			ExpectedMatch: []string{"A", "A"},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "SepBy: error rolls back",
+			Input:          "A,A,B",
+			Parser:         core.SepBy(core.Rune('A'), core.SequenceOf2(comma, core.WhileNot(core.OptionalWhitespace, core.Rune('X')))),
+			WantErr:        true,
+			RemainingInput: "A,A,B",
+		},
+	}
+	RunTests(t, tests)
+}
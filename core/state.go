@@ -0,0 +1,118 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core
+
+import "errors"
+
+// ErrStateUnsupported is returned by WithState for an input that cannot carry user state, see Input.
+var ErrStateUnsupported = errors.New("input does not support user state")
+
+// state is an immutable list of user state values keyed by their type.
+// Because it is never mutated in place a checkpoint only needs to hold a pointer to it,
+// which is what allows Restore to roll back state changes made by failed branches.
+type state struct {
+	key    any
+	value  any
+	parent *state
+}
+
+type stateKey[S any] struct{}
+
+func (s *state) get(key any) (any, bool) {
+	for n := s; n != nil; n = n.parent {
+		if n.key == key {
+			return n.value, true
+		}
+	}
+	return nil, false
+}
+
+// without returns a copy of the list with the given key removed.
+func (s *state) without(key any) *state {
+	if s == nil {
+		return nil
+	}
+	if s.key == key {
+		return s.parent
+	}
+	parent := s.parent.without(key)
+	if parent == s.parent {
+		return s
+	}
+	return &state{key: s.key, value: s.value, parent: parent}
+}
+
+// GetState returns the user state of type S attached to the input, if any.
+// Inputs that cannot carry user state never have any, see Input.
+func GetState[S any](in Input) (S, bool) {
+	var s S
+	i, ok := underlying(in)
+	if !ok {
+		return s, false
+	}
+	v, ok := i.state.get(stateKey[S]{})
+	if !ok {
+		return s, false
+	}
+	return v.(S), true
+}
+
+// SetState attaches user state of type S to the input, replacing any existing state of the same type.
+// The change is rolled back if the input is restored to a checkpoint taken before it was made.
+// It does nothing for inputs that cannot carry user state, see Input.
+func SetState[S any](in Input, s S) {
+	i, ok := underlying(in)
+	if !ok {
+		return
+	}
+	key := stateKey[S]{}
+	i.state = &state{key: key, value: s, parent: i.state.without(key)}
+}
+
+// ClearState removes the user state of type S from the input.
+// It does nothing for inputs that cannot carry user state, see Input.
+func ClearState[S any](in Input) {
+	i, ok := underlying(in)
+	if !ok {
+		return
+	}
+	i.state = i.state.without(stateKey[S]{})
+}
+
+// WithState runs the parser with the given user state attached to the input.
+// Once the parser completes the previous state of type S, if any, is reinstated.
+// It returns ErrStateUnsupported for inputs that cannot carry user state, see Input.
+func WithState[S, T any](s S, parser Parser[T]) Parser[T] {
+	return func(in Input) (T, bool, error) {
+		if _, ok := underlying(in); !ok {
+			var zero T
+			return zero, false, ErrStateUnsupported
+		}
+		start := in.Checkpoint()
+		previous, hadPrevious := GetState[S](in)
+		SetState(in, s)
+		match, ok, err := parser(in)
+		if err != nil || !ok {
+			in.Restore(start)
+			return match, false, err
+		}
+		if hadPrevious {
+			SetState(in, previous)
+		} else {
+			ClearState[S](in)
+		}
+		return match, true, nil
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package core_test
+
+import (
+	"testing"
+
+	. "github.com/liamawhite/parse/core"
+	"github.com/stretchr/testify/assert"
+)
+
+type headingLevel int
+
+// wrappedInput delegates to another input, such as one that records what is read would.
+type wrappedInput struct {
+	Input
+}
+
+func (w wrappedInput) Unwrap() Input {
+	return w.Input
+}
+
+// opaqueInput delegates to another input without exposing it.
+type opaqueInput struct {
+	Input
+}
+
+type tabWidth int
+
+// setLevel sets the heading level and then matches the parser.
+func setLevel(level headingLevel, parser Parser[string]) Parser[string] {
+	return func(in Input) (string, bool, error) {
+		SetState(in, level)
+		return parser(in)
+	}
+}
+
+func TestState(t *testing.T) {
+	t.Run("Get missing state", func(t *testing.T) {
+		in := NewInput("")
+		_, ok := GetState[headingLevel](in)
+		assert.False(t, ok)
+	})
+	t.Run("Set and get state", func(t *testing.T) {
+		in := NewInput("")
+		SetState(in, headingLevel(1))
+		SetState(in, tabWidth(4))
+		SetState(in, headingLevel(2))
+		level, ok := GetState[headingLevel](in)
+		assert.True(t, ok)
+		assert.Equal(t, headingLevel(2), level)
+		width, ok := GetState[tabWidth](in)
+		assert.True(t, ok)
+		assert.Equal(t, tabWidth(4), width)
+	})
+	t.Run("Clear state", func(t *testing.T) {
+		in := NewInput("")
+		SetState(in, headingLevel(1))
+		SetState(in, tabWidth(4))
+		ClearState[headingLevel](in)
+		_, ok := GetState[headingLevel](in)
+		assert.False(t, ok)
+		_, ok = GetState[tabWidth](in)
+		assert.True(t, ok)
+	})
+	t.Run("Restore rolls back state", func(t *testing.T) {
+		in := NewInput("ABC")
+		SetState(in, headingLevel(1))
+		start := in.Checkpoint()
+		SetState(in, headingLevel(2))
+		in.Restore(start)
+		level, _ := GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+	})
+	t.Run("Restore rolls back zero width state changes", func(t *testing.T) {
+		in := NewInput("ABC")
+		outer := in.Checkpoint()
+		SetState(in, headingLevel(1))
+		inner := in.Checkpoint()
+		SetState(in, headingLevel(2))
+		in.Restore(inner)
+		level, _ := GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+		in.Restore(outer)
+		_, ok := GetState[headingLevel](in)
+		assert.False(t, ok)
+	})
+	t.Run("Any does not leak state from failed branches", func(t *testing.T) {
+		in := NewInput("ABC")
+		parser := Any(setLevel(1, String("AC")), setLevel(2, String("AB")), setLevel(3, String("X")))
+		match, ok, err := parser(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "AB", match)
+		level, _ := GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(2), level)
+	})
+	t.Run("Optional does not leak state from failed parser", func(t *testing.T) {
+		in := NewInput("ABC")
+		_, ok, err := Optional(setLevel(1, String("X")))(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		_, ok = GetState[headingLevel](in)
+		assert.False(t, ok)
+	})
+	t.Run("StringFrom keeps state from successful parsers", func(t *testing.T) {
+		in := NewInput("ABC")
+		match, ok, err := StringFrom(setLevel(1, String("A")), String("B"))(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "AB", match)
+		level, _ := GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+	})
+	t.Run("WithState scopes state to the parser", func(t *testing.T) {
+		in := NewInput("ABC")
+		SetState(in, headingLevel(1))
+		var inner headingLevel
+		parser := WithState(headingLevel(2), func(in Input) (string, bool, error) {
+			inner, _ = GetState[headingLevel](in)
+			return String("A")(in)
+		})
+		match, ok, err := parser(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "A", match)
+		assert.Equal(t, headingLevel(2), inner)
+		level, _ := GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+	})
+	t.Run("WithState clears state that did not exist before", func(t *testing.T) {
+		in := NewInput("ABC")
+		_, ok, err := WithState(headingLevel(2), String("A"))(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		_, ok = GetState[headingLevel](in)
+		assert.False(t, ok)
+	})
+	t.Run("WithState rolls back on failure", func(t *testing.T) {
+		in := NewInput("ABC")
+		_, ok, err := WithState(headingLevel(2), String("X"))(in)
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		_, ok = GetState[headingLevel](in)
+		assert.False(t, ok)
+		assert.Equal(t, 0, in.Checkpoint().Position())
+	})
+	t.Run("Wrapping input shares state with the wrapped input", func(t *testing.T) {
+		inner := NewInput("ABC")
+		in := wrappedInput{inner}
+		SetState(in, headingLevel(1))
+		level, ok := GetState[headingLevel](inner)
+		assert.True(t, ok)
+		assert.Equal(t, headingLevel(1), level)
+		var scoped headingLevel
+		match, ok, err := WithState(headingLevel(2), func(in Input) (string, bool, error) {
+			scoped, _ = GetState[headingLevel](in)
+			return String("A")(in)
+		})(in)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, "A", match)
+		assert.Equal(t, headingLevel(2), scoped)
+		level, _ = GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+	})
+	t.Run("Input without state ignores it", func(t *testing.T) {
+		in := opaqueInput{NewInput("ABC")}
+		SetState(in, headingLevel(1))
+		_, ok := GetState[headingLevel](in)
+		assert.False(t, ok)
+	})
+	t.Run("WithState errors for input without state", func(t *testing.T) {
+		in := opaqueInput{NewInput("ABC")}
+		_, ok, err := WithState(headingLevel(2), String("A"))(in)
+		assert.ErrorIs(t, err, ErrStateUnsupported)
+		assert.False(t, ok)
+		assert.Equal(t, 0, in.Checkpoint().Position())
+	})
+	t.Run("Nested input starts with the state", func(t *testing.T) {
+		in := NewInput("ABC")
+		SetState(in, headingLevel(1))
+		nested := NewNestedInput(in, "DEF")
+		level, ok := GetState[headingLevel](nested)
+		assert.True(t, ok)
+		assert.Equal(t, headingLevel(1), level)
+		SetState(nested, headingLevel(2))
+		level, _ = GetState[headingLevel](in)
+		assert.Equal(t, headingLevel(1), level)
+	})
+}
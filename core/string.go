@@ -14,24 +14,79 @@
 
 package core
 
-import "strings"
+import (
+	"math/rand/v2"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
 
 // String matches the given string (case sensitive).
 func String(s string) Parser[string] {
-	return stringWhere(s, func(candidate string) bool {
+	generate := func(r *rand.Rand) string { return s }
+	return stringWhere(s, generate, func(candidate string) bool {
 		return s == candidate
 	})
 }
 
 // StringInsensitive matches the given string (case insensitive).
 func StringInsensitive(s string) Parser[string] {
-	return stringWhere(s, func(candidate string) bool {
-		return strings.EqualFold(s, candidate)
-	})
+	generate := func(r *rand.Rand) string {
+		return strings.Map(func(c rune) rune {
+			if r.IntN(2) == 0 {
+				return unicode.ToUpper(c)
+			}
+			return c
+		}, s)
+	}
+	// Compare rune by rune as case folding can change the length in bytes, e.g. ß and ẞ, so peek at as many bytes
+	// as the match could need and take only what matched.
+	maxLen := utf8.RuneCountInString(s) * utf8.UTFMax
+	return func(in Input) (string, bool, error) {
+		extend(in, generate)
+		next := peekUpTo(in, maxLen)
+		n := 0
+		for _, c := range s {
+			r, size := utf8.DecodeRuneInString(next[n:])
+			if size == 0 || !equalFold(c, r) {
+				return "", false, nil
+			}
+			n += size
+		}
+		res, _ := in.Take(n)
+		return res, true, nil
+	}
+}
+
+// peekUpTo returns the next n bytes of the input without consuming them, or the rest of the input if it is shorter.
+func peekUpTo(in Input, n int) string {
+	if i, ok := in.(*input); ok {
+		return i.s[i.index:min(i.index+n, len(i.s))]
+	}
+	for ; n > 0; n-- {
+		if s, ok := in.Peek(n); ok {
+			return s
+		}
+	}
+	return ""
 }
 
-func stringWhere(s string, predicate func(candidate string) bool) Parser[string] {
+// equalFold reports whether the runes are equal under simple Unicode case folding.
+func equalFold(a, b rune) bool {
+	if a == b {
+		return true
+	}
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+func stringWhere(s string, generate func(r *rand.Rand) string, predicate func(candidate string) bool) Parser[string] {
 	return func(in Input) (string, bool, error) {
+		extend(in, generate)
 		match, ok := in.Peek(len(s))
 		if !ok {
 			return "", false, nil
@@ -47,6 +102,10 @@ func stringWhere(s string, predicate func(candidate string) bool) Parser[string]
 // StringFrom returns the string range match by the given parsers.
 func StringFrom[T any](parsers ...Parser[T]) Parser[string] {
 	return func(in Input) (string, bool, error) {
+		if err := enter(in); err != nil {
+			return "", false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		for _, parser := range parsers {
 			_, ok, err := parser(in)
@@ -60,8 +119,7 @@ func StringFrom[T any](parsers ...Parser[T]) Parser[string] {
 		}
 
 		end := in.Checkpoint()
-		in.Restore(start)
-		res, ok := in.Take(int(end - start))
+		res, ok := in.Peek(start.index - end.index)
 		return res, ok, nil
 	}
 }
@@ -70,8 +128,17 @@ func StringFrom[T any](parsers ...Parser[T]) Parser[string] {
 // This differs from while as the delimiter is searched for AFTER the first match.
 func StringUntil[T any](delimiter Parser[T]) Parser[string] {
 	return func(in Input) (string, bool, error) {
+		if err := enter(in); err != nil {
+			return "", false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return "", false, err
+			}
+			extend(in, generateAnyRune)
 			_, chompOk := in.Take(1)
 			if !chompOk {
 				in.Restore(start)
@@ -79,7 +146,11 @@ func StringUntil[T any](delimiter Parser[T]) Parser[string] {
 			}
 
 			beforeDelimiter := in.Checkpoint()
-			_, ok, err := delimiter(in)
+			var ok bool
+			var err error
+			if !repeat(in) {
+				_, ok, err = delimiter(in)
+			}
 			if err != nil {
 				in.Restore(start)
 				return "", false, err
@@ -90,8 +161,7 @@ func StringUntil[T any](delimiter Parser[T]) Parser[string] {
 			}
 		}
 		end := in.Checkpoint()
-		in.Restore(start)
-		res, ok := in.Take(int(end - start))
+		res, ok := in.Peek(start.index - end.index)
 		return res, ok, nil
 	}
 }
@@ -100,19 +170,29 @@ func StringUntil[T any](delimiter Parser[T]) Parser[string] {
 // This requires at least one character to be matched before the end of the input.
 // See the tests for examples.
 func StringUntilEOF[T any](delimiter Parser[T]) Parser[string] {
-	return func(in Input) (string, bool, error) {
-		return StringUntil(Or(delimiter, EOF[string]()))(in)
-	}
+	return StringUntil(Or(delimiter, EOF[string]()))
 }
 
 // StringWhileNot matches while the delimiter does not match.
 // This differs from until as the delimiter is searched for BEFORE the first match.
 func StringWhileNot[T any](delimiter Parser[T]) Parser[string] {
 	return func(in Input) (string, bool, error) {
+		if err := enter(in); err != nil {
+			return "", false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return "", false, err
+			}
 			beforeDelimiter := in.Checkpoint()
-			_, ok, err := delimiter(in)
+			var ok bool
+			var err error
+			if !repeat(in) {
+				_, ok, err = delimiter(in)
+			}
 			if err != nil {
 				in.Restore(start)
 				return "", false, err
@@ -122,6 +202,7 @@ func StringWhileNot[T any](delimiter Parser[T]) Parser[string] {
 				break
 			}
 
+			extend(in, generateAnyRune)
 			_, chompOk := in.Take(1)
 			if !chompOk {
 				in.Restore(start)
@@ -129,8 +210,7 @@ func StringWhileNot[T any](delimiter Parser[T]) Parser[string] {
 			}
 		}
 		end := in.Checkpoint()
-		in.Restore(start)
-		res, ok := in.Take(int(end - start))
+		res, ok := in.Peek(start.index - end.index)
 		return res, ok, nil
 	}
 }
@@ -138,7 +218,76 @@ func StringWhileNot[T any](delimiter Parser[T]) Parser[string] {
 // StringWhileNotOrEOF matches while the delimiter does not match or the end of the input.
 // This does not require any characters to be matched before the end of the input.
 func StringWhileNotEOFOr[T any](delimiter Parser[T]) Parser[string] {
+	return StringWhileNot(Or(delimiter, EOF[string]()))
+}
+
+// QuotedString matches text between two quote runes, in which the escape rune escapes the rune that follows it,
+// e.g. QuotedString('"', '\\') matches "say \"hi\"". When the escape is the quote itself a quote is escaped by doubling it,
+// as in CSV ("say ""hi"""). It does not match if the closing quote is missing.
+// The text between the quotes is returned with the escapes left in, as what they mean depends on the format.
+func QuotedString(quote, escape rune) Parser[string] {
+	generate := generateQuoted(quote, escape)
 	return func(in Input) (string, bool, error) {
-		return StringWhileNot(Or(delimiter, EOF[string]()))(in)
+		if err := enter(in); err != nil {
+			return "", false, err
+		}
+		defer leave(in)
+		start := in.Checkpoint()
+		extend(in, generate)
+		if r, size := PeekRune(in); size == 0 || r != quote {
+			return "", false, nil
+		}
+		in.Take(utf8.RuneLen(quote))
+		content := in.Checkpoint()
+		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return "", false, err
+			}
+			r, size := PeekRune(in)
+			if size == 0 {
+				in.Restore(start)
+				return "", false, nil
+			}
+			in.Take(size)
+			if r != escape && r != quote {
+				continue
+			}
+			next, nextSize := PeekRune(in)
+			switch {
+			case r != quote:
+				// An escape, which needs something to escape.
+				if nextSize == 0 {
+					in.Restore(start)
+					return "", false, nil
+				}
+				in.Take(nextSize)
+			case r == escape && next == quote && nextSize > 0:
+				// A doubled quote.
+				in.Take(nextSize)
+			default:
+				res, _ := in.Peek(content.index - in.Checkpoint().index)
+				return res[:len(res)-size], true, nil
+			}
+		}
+	}
+}
+
+// generateQuoted returns a generator of the text matched by QuotedString.
+func generateQuoted(quote, escape rune) func(r *rand.Rand) string {
+	generate := generateRune(func(r rune) bool { return r != quote && r != escape })
+	return func(r *rand.Rand) string {
+		var s strings.Builder
+		s.WriteRune(quote)
+		for r.Float64() < repeatProbability {
+			if r.IntN(8) == 0 {
+				s.WriteRune(escape)
+				s.WriteRune(quote)
+				continue
+			}
+			s.WriteString(generate(r))
+		}
+		s.WriteRune(quote)
+		return s.String()
 	}
 }
@@ -55,6 +55,39 @@ func TestString(t *testing.T) {
 			ExpectedOK:     true,
 			RemainingInput: "DEF",
 		},
+		{
+			Name:           "matches insensitive with a different length",
+			Input:          "STRAẞE 5",
+			Parser:         core.StringInsensitive("straße"),
+			ExpectedMatch:  "STRAẞE",
+			ExpectedOK:     true,
+			RemainingInput: " 5",
+		},
+		{
+			Name:           "insensitive does not match a prefix",
+			Input:          "STRAẞ",
+			Parser:         core.StringInsensitive("straße"),
+			ExpectedOK:     false,
+			RemainingInput: "STRAẞ",
+		},
+		{
+			Name:  "case insensitive through another input",
+			Input: "STRAẞE",
+			Parser: func(in core.Input) (string, bool, error) {
+				return core.StringInsensitive("straße")(opaqueInput{in})
+			},
+			ExpectedMatch: "STRAẞE",
+			ExpectedOK:    true,
+		},
+		{
+			Name:  "case insensitive prefix through another input",
+			Input: "STRAẞ",
+			Parser: func(in core.Input) (string, bool, error) {
+				return core.StringInsensitive("straße")(opaqueInput{in})
+			},
+			ExpectedOK:     false,
+			RemainingInput: "STRAẞ",
+		},
 	}
 	RunTests(t, tests)
 }
@@ -183,3 +216,77 @@ func TestStringWhileNot(t *testing.T) {
 	}
 	RunTests(t, tests)
 }
+
+func TestQuotedString(t *testing.T) {
+	tests := []ParserTest[string]{
+		{
+			Name:           "no match",
+			Input:          "ABC",
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedOK:     false,
+			RemainingInput: "ABC",
+		},
+		{
+			Name:           "empty",
+			Input:          `""ABC`,
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedMatch:  "",
+			ExpectedOK:     true,
+			RemainingInput: "ABC",
+		},
+		{
+			Name:           "escapes are kept",
+			Input:          `"say \"hi\" \\" there`,
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedMatch:  `say \"hi\" \\`,
+			ExpectedOK:     true,
+			RemainingInput: " there",
+		},
+		{
+			Name:           "multibyte quote",
+			Input:          "«a\\«b«c",
+			Parser:         core.QuotedString('«', '\\'),
+			ExpectedMatch:  "a\\«b",
+			ExpectedOK:     true,
+			RemainingInput: "c",
+		},
+		{
+			Name:           "doubled quotes",
+			Input:          `'it''s' here`,
+			Parser:         core.QuotedString('\'', '\''),
+			ExpectedMatch:  "it''s",
+			ExpectedOK:     true,
+			RemainingInput: " here",
+		},
+		{
+			Name:           "doubled quotes at the end",
+			Input:          `"a"""`,
+			Parser:         core.QuotedString('"', '"'),
+			ExpectedMatch:  `a""`,
+			ExpectedOK:     true,
+			RemainingInput: "",
+		},
+		{
+			Name:           "unterminated",
+			Input:          `"abc`,
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedOK:     false,
+			RemainingInput: `"abc`,
+		},
+		{
+			Name:           "escaped closing quote is unterminated",
+			Input:          `"abc\"`,
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedOK:     false,
+			RemainingInput: `"abc\"`,
+		},
+		{
+			Name:           "trailing escape is unterminated",
+			Input:          `"abc\`,
+			Parser:         core.QuotedString('"', '\\'),
+			ExpectedOK:     false,
+			RemainingInput: `"abc\`,
+		},
+	}
+	RunTests(t, tests)
+}
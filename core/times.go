@@ -16,28 +16,67 @@ package core
 
 func times[T any](min int, max func(i int) bool, p Parser[T]) Parser[[]T] {
 	return func(in Input) ([]T, bool, error) {
-		start := in.Checkpoint()
 		match := make([]T, 0)
-		for i := 0; max(i); i++ {
-			m, ok, err := p(in)
-			if err != nil {
-				in.Restore(start)
-				return match, false, err
-			}
-			if !ok {
-				break
-			}
-			match = append(match, m)
+		_, ok, err := repetitions(in, min, max, p, func(m T) { match = append(match, m) })
+		if err != nil {
+			return match, false, err
 		}
-		ok := len(match) >= min && max(len(match)-1)
 		if !ok {
-			in.Restore(start)
 			return nil, false, nil
 		}
 		return match, true, nil
 	}
 }
 
+func skip[T any](min int, max func(i int) bool, p Parser[T]) Parser[int] {
+	return func(in Input) (int, bool, error) {
+		return repetitions(in, min, max, p, nil)
+	}
+}
+
+// repetitions matches the parser between min and max times, passing each match to keep unless it is nil,
+// and returns the number of matches.
+func repetitions[T any](in Input, min int, max func(i int) bool, p Parser[T], keep func(m T)) (int, bool, error) {
+	if err := enter(in); err != nil {
+		return 0, false, err
+	}
+	defer leave(in)
+	start := in.Checkpoint()
+	n := 0
+	for i := 0; max(i); i++ {
+		if err := step(in); err != nil {
+			in.Restore(start)
+			return 0, false, err
+		}
+		if n >= min && stop(in) {
+			break
+		}
+		before := in.Checkpoint()
+		m, ok, err := p(in)
+		if err != nil {
+			in.Restore(start)
+			return 0, false, err
+		}
+		if !ok {
+			break
+		}
+		// A match that consumed no input would match forever, so stop once we have the minimum.
+		if n >= min && in.Checkpoint().index == before.index {
+			in.Restore(before)
+			break
+		}
+		if keep != nil {
+			keep(m)
+		}
+		n++
+	}
+	if n < min || !max(n-1) {
+		in.Restore(start)
+		return 0, false, nil
+	}
+	return n, true, nil
+}
+
 // Matches the given parser exactly n times.
 func Times[T any](n int, p Parser[T]) Parser[[]T] {
 	return times(n, func(i int) bool { return i < n }, p)
@@ -67,3 +106,34 @@ func ZeroOrMore[T any](p Parser[T]) Parser[[]T] {
 func OneOrMore[T any](p Parser[T]) Parser[[]T] {
 	return times(1, func(i int) bool { return true }, p)
 }
+
+// Matches the given parser exactly n times, returning the number of matches rather than the matches themselves.
+// Unlike Times no slice is built, which makes it cheaper within StringFrom.
+func SkipTimes[T any](n int, p Parser[T]) Parser[int] {
+	return skip(n, func(i int) bool { return i < n }, p)
+}
+
+// Matches the given parser between min and max times, returning the number of matches.
+func SkipBetween[T any](min, max int, p Parser[T]) Parser[int] {
+	return skip(min, func(i int) bool { return i < max }, p)
+}
+
+// Matches the given parser at least n times, returning the number of matches.
+func SkipAtLeast[T any](n int, p Parser[T]) Parser[int] {
+	return skip(n, func(i int) bool { return true }, p)
+}
+
+// Matches the given parser at most n times, returning the number of matches.
+func SkipAtMost[T any](n int, p Parser[T]) Parser[int] {
+	return skip(0, func(i int) bool { return i < n }, p)
+}
+
+// Matches the given parser zero or more times, returning the number of matches.
+func SkipZeroOrMore[T any](p Parser[T]) Parser[int] {
+	return skip(0, func(i int) bool { return true }, p)
+}
+
+// Matches the given parser one or more times, returning the number of matches.
+func SkipOneOrMore[T any](p Parser[T]) Parser[int] {
+	return skip(1, func(i int) bool { return true }, p)
+}
@@ -160,3 +160,149 @@ func TestTime(t *testing.T) {
 	}
 	RunTests(t, tests)
 }
+
+func TestTimesZeroWidth(t *testing.T) {
+	optional := []ParserTest[[]core.Match[string]]{
+		{
+			Name:           "ZeroOrMore: optional stops when it stops consuming",
+			Input:          "AAB",
+			Parser:         core.ZeroOrMore(core.Optional(core.Rune('A'))),
+			ExpectedMatch:  []core.Match[string]{core.NewMatch("A", true), core.NewMatch("A", true)},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "ZeroOrMore: optional never consuming",
+			Input:          "B",
+			Parser:         core.ZeroOrMore(core.Optional(core.Rune('A'))),
+			ExpectedMatch:  []core.Match[string]{},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "AtLeast: optional fills the minimum without consuming",
+			Input:          "B",
+			Parser:         core.AtLeast(2, core.Optional(core.Rune('A'))),
+			ExpectedMatch:  []core.Match[string]{core.NewMatch("", false), core.NewMatch("", false)},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "Times: optional matches exactly n times without consuming",
+			Input:          "AB",
+			Parser:         core.Times(3, core.Optional(core.Rune('A'))),
+			ExpectedMatch:  []core.Match[string]{core.NewMatch("A", true), core.NewMatch("", false), core.NewMatch("", false)},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+	}
+	RunTests(t, optional)
+
+	tests := []ParserTest[[]string]{
+		{
+			Name:           "ZeroOrMore: optional whitespace",
+			Input:          "  A",
+			Parser:         core.ZeroOrMore(core.OptionalWhitespace),
+			ExpectedMatch:  []string{"  "},
+			ExpectedOK:     true,
+			RemainingInput: "A",
+		},
+		{
+			Name:          "ZeroOrMore: EOF",
+			Input:         "",
+			Parser:        core.ZeroOrMore(core.EOF[string]()),
+			ExpectedMatch: []string{},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "OneOrMore: EOF",
+			Input:         "",
+			Parser:        core.OneOrMore(core.EOF[string]()),
+			ExpectedMatch: []string{""},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "OneOrMore: EOF before the end",
+			Input:          "A",
+			Parser:         core.OneOrMore(core.EOF[string]()),
+			ExpectedOK:     false,
+			RemainingInput: "A",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestSkip(t *testing.T) {
+	tests := []ParserTest[int]{
+		{
+			Name:           "SkipTimes: no match",
+			Input:          "ABCDEF",
+			Parser:         core.SkipTimes(2, core.Rune('A')),
+			ExpectedOK:     false,
+			RemainingInput: "ABCDEF",
+		},
+		{
+			Name:           "SkipTimes: match",
+			Input:          "AAA",
+			Parser:         core.SkipTimes(2, core.Rune('A')),
+			ExpectedMatch:  2,
+			ExpectedOK:     true,
+			RemainingInput: "A",
+		},
+		{
+			Name:           "SkipBetween: match",
+			Input:          "AAAB",
+			Parser:         core.SkipBetween(2, 4, core.Rune('A')),
+			ExpectedMatch:  3,
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "SkipAtLeast: not enough",
+			Input:          "AB",
+			Parser:         core.SkipAtLeast(2, core.Rune('A')),
+			ExpectedOK:     false,
+			RemainingInput: "AB",
+		},
+		{
+			Name:           "SkipAtMost: stops at the maximum",
+			Input:          "AAA",
+			Parser:         core.SkipAtMost(2, core.Rune('A')),
+			ExpectedMatch:  2,
+			ExpectedOK:     true,
+			RemainingInput: "A",
+		},
+		{
+			Name:           "SkipZeroOrMore: no match",
+			Input:          "B",
+			Parser:         core.SkipZeroOrMore(core.Rune('A')),
+			ExpectedMatch:  0,
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "SkipOneOrMore: optional stops when it stops consuming",
+			Input:          "AAB",
+			Parser:         core.SkipOneOrMore(core.Optional(core.Rune('A'))),
+			ExpectedMatch:  2,
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "SkipOneOrMore: error rolls back",
+			Input:          "AB",
+			Parser:         core.SkipOneOrMore(core.WhileNot(core.OptionalWhitespace, core.Rune('B'))),
+			WantErr:        true,
+			ExpectedOK:     false,
+			RemainingInput: "AB",
+		},
+		{
+			Name:          "SkipOneOrMore: match",
+			Input:         "AAB",
+			Parser:        core.SkipOneOrMore(core.RuneIn("AB")),
+			ExpectedMatch: 3,
+			ExpectedOK:    true,
+		},
+	}
+	RunTests(t, tests)
+}
@@ -14,61 +14,51 @@
 
 package core
 
-type tuple2[A, B any] struct {
+// Tuple2 holds the matches of SequenceOf2.
+// The tuples are structs rather than interfaces so that returning them from a parser does not allocate.
+type Tuple2[A, B any] struct {
 	A A
 	B B
 }
 
-func NewTuple2[A, B any](a A, b B) tuple2[A, B] {
-	return tuple2[A, B]{A: a, B: b}
+func NewTuple2[A, B any](a A, b B) Tuple2[A, B] {
+	return Tuple2[A, B]{A: a, B: b}
 }
 
-type Tuple2[A, B any] interface {
-	Values() (A, B)
-}
-
-func (t tuple2[A, B]) Values() (A, B) {
+func (t Tuple2[A, B]) Values() (A, B) {
 	return t.A, t.B
 }
 
-type tuple3[A, B, C any] struct {
+type Tuple3[A, B, C any] struct {
 	A A
 	B B
 	C C
 }
 
-func (t tuple3[A, B, C]) Values() (A, B, C) {
+func (t Tuple3[A, B, C]) Values() (A, B, C) {
 	return t.A, t.B, t.C
 }
 
-func NewTuple3[A, B, C any](a A, b B, c C) tuple3[A, B, C] {
-	return tuple3[A, B, C]{A: a, B: b, C: c}
-}
-
-type Tuple3[A, B, C any] interface {
-	Values() (A, B, C)
+func NewTuple3[A, B, C any](a A, b B, c C) Tuple3[A, B, C] {
+	return Tuple3[A, B, C]{A: a, B: b, C: c}
 }
 
-type tuple4[A, B, C, D any] struct {
+type Tuple4[A, B, C, D any] struct {
 	A A
 	B B
 	C C
 	D D
 }
 
-func NewTuple4[A, B, C, D any](a A, b B, c C, d D) tuple4[A, B, C, D] {
-	return tuple4[A, B, C, D]{A: a, B: b, C: c, D: d}
+func NewTuple4[A, B, C, D any](a A, b B, c C, d D) Tuple4[A, B, C, D] {
+	return Tuple4[A, B, C, D]{A: a, B: b, C: c, D: d}
 }
 
-func (t tuple4[A, B, C, D]) Values() (A, B, C, D) {
+func (t Tuple4[A, B, C, D]) Values() (A, B, C, D) {
 	return t.A, t.B, t.C, t.D
 }
 
-type Tuple4[A, B, C, D any] interface {
-	Values() (A, B, C, D)
-}
-
-type tuple5[A, B, C, D, E any] struct {
+type Tuple5[A, B, C, D, E any] struct {
 	A A
 	B B
 	C C
@@ -76,19 +66,15 @@ type tuple5[A, B, C, D, E any] struct {
 	E E
 }
 
-func NewTuple5[A, B, C, D, E any](a A, b B, c C, d D, e E) tuple5[A, B, C, D, E] {
-	return tuple5[A, B, C, D, E]{A: a, B: b, C: c, D: d, E: e}
+func NewTuple5[A, B, C, D, E any](a A, b B, c C, d D, e E) Tuple5[A, B, C, D, E] {
+	return Tuple5[A, B, C, D, E]{A: a, B: b, C: c, D: d, E: e}
 }
 
-func (t tuple5[A, B, C, D, E]) Values() (A, B, C, D, E) {
+func (t Tuple5[A, B, C, D, E]) Values() (A, B, C, D, E) {
 	return t.A, t.B, t.C, t.D, t.E
 }
 
-type Tuple5[A, B, C, D, E any] interface {
-	Values() (A, B, C, D, E)
-}
-
-type tuple6[A, B, C, D, E, F any] struct {
+type Tuple6[A, B, C, D, E, F any] struct {
 	A A
 	B B
 	C C
@@ -97,19 +83,15 @@ type tuple6[A, B, C, D, E, F any] struct {
 	F F
 }
 
-func NewTuple6[A, B, C, D, E, F any](a A, b B, c C, d D, e E, f F) tuple6[A, B, C, D, E, F] {
-	return tuple6[A, B, C, D, E, F]{A: a, B: b, C: c, D: d, E: e, F: f}
+func NewTuple6[A, B, C, D, E, F any](a A, b B, c C, d D, e E, f F) Tuple6[A, B, C, D, E, F] {
+	return Tuple6[A, B, C, D, E, F]{A: a, B: b, C: c, D: d, E: e, F: f}
 }
 
-func (t tuple6[A, B, C, D, E, F]) Values() (A, B, C, D, E, F) {
+func (t Tuple6[A, B, C, D, E, F]) Values() (A, B, C, D, E, F) {
 	return t.A, t.B, t.C, t.D, t.E, t.F
 }
 
-type Tuple6[A, B, C, D, E, F any] interface {
-	Values() (A, B, C, D, E, F)
-}
-
-type tuple7[A, B, C, D, E, F, G any] struct {
+type Tuple7[A, B, C, D, E, F, G any] struct {
 	A A
 	B B
 	C C
@@ -119,19 +101,15 @@ type tuple7[A, B, C, D, E, F, G any] struct {
 	G G
 }
 
-func NewTuple7[A, B, C, D, E, F, G any](a A, b B, c C, d D, e E, f F, g G) tuple7[A, B, C, D, E, F, G] {
-	return tuple7[A, B, C, D, E, F, G]{A: a, B: b, C: c, D: d, E: e, F: f, G: g}
+func NewTuple7[A, B, C, D, E, F, G any](a A, b B, c C, d D, e E, f F, g G) Tuple7[A, B, C, D, E, F, G] {
+	return Tuple7[A, B, C, D, E, F, G]{A: a, B: b, C: c, D: d, E: e, F: f, G: g}
 }
 
-func (t tuple7[A, B, C, D, E, F, G]) Values() (A, B, C, D, E, F, G) {
+func (t Tuple7[A, B, C, D, E, F, G]) Values() (A, B, C, D, E, F, G) {
 	return t.A, t.B, t.C, t.D, t.E, t.F, t.G
 }
 
-type Tuple7[A, B, C, D, E, F, G any] interface {
-	Values() (A, B, C, D, E, F, G)
-}
-
-type tuple8[A, B, C, D, E, F, G, H any] struct {
+type Tuple8[A, B, C, D, E, F, G, H any] struct {
 	A A
 	B B
 	C C
@@ -142,19 +120,15 @@ type tuple8[A, B, C, D, E, F, G, H any] struct {
 	H H
 }
 
-func NewTuple8[A, B, C, D, E, F, G, H any](a A, b B, c C, d D, e E, f F, g G, h H) tuple8[A, B, C, D, E, F, G, H] {
-	return tuple8[A, B, C, D, E, F, G, H]{A: a, B: b, C: c, D: d, E: e, F: f, G: g, H: h}
+func NewTuple8[A, B, C, D, E, F, G, H any](a A, b B, c C, d D, e E, f F, g G, h H) Tuple8[A, B, C, D, E, F, G, H] {
+	return Tuple8[A, B, C, D, E, F, G, H]{A: a, B: b, C: c, D: d, E: e, F: f, G: g, H: h}
 }
 
-func (t tuple8[A, B, C, D, E, F, G, H]) Values() (A, B, C, D, E, F, G, H) {
+func (t Tuple8[A, B, C, D, E, F, G, H]) Values() (A, B, C, D, E, F, G, H) {
 	return t.A, t.B, t.C, t.D, t.E, t.F, t.G, t.H
 }
 
-type Tuple8[A, B, C, D, E, F, G, H any] interface {
-	Values() (A, B, C, D, E, F, G, H)
-}
-
-type tuple9[A, B, C, D, E, F, G, H, I any] struct {
+type Tuple9[A, B, C, D, E, F, G, H, I any] struct {
 	A A
 	B B
 	C C
@@ -166,14 +140,10 @@ type tuple9[A, B, C, D, E, F, G, H, I any] struct {
 	I I
 }
 
-func NewTuple9[A, B, C, D, E, F, G, H, I any](a A, b B, c C, d D, e E, f F, g G, h H, i I) tuple9[A, B, C, D, E, F, G, H, I] {
-	return tuple9[A, B, C, D, E, F, G, H, I]{A: a, B: b, C: c, D: d, E: e, F: f, G: g, H: h, I: i}
+func NewTuple9[A, B, C, D, E, F, G, H, I any](a A, b B, c C, d D, e E, f F, g G, h H, i I) Tuple9[A, B, C, D, E, F, G, H, I] {
+	return Tuple9[A, B, C, D, E, F, G, H, I]{A: a, B: b, C: c, D: d, E: e, F: f, G: g, H: h, I: i}
 }
 
-func (t tuple9[A, B, C, D, E, F, G, H, I]) Values() (A, B, C, D, E, F, G, H, I) {
+func (t Tuple9[A, B, C, D, E, F, G, H, I]) Values() (A, B, C, D, E, F, G, H, I) {
 	return t.A, t.B, t.C, t.D, t.E, t.F, t.G, t.H, t.I
 }
-
-type Tuple9[A, B, C, D, E, F, G, H, I any] interface {
-	Values() (A, B, C, D, E, F, G, H, I)
-}
@@ -18,9 +18,18 @@ package core
 // This differs from while as the delimiter is searched for AFTER the first match.
 func Until[T, D any](parser Parser[T], delimiter Parser[D]) Parser[[]T] {
 	return func(in Input) ([]T, bool, error) {
+		if err := enter(in); err != nil {
+			return nil, false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		match := make([]T, 0)
 		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return nil, false, err
+			}
+			beforeParser := in.Checkpoint()
 			m, ok, err := parser(in)
 			if err != nil {
 				in.Restore(start)
@@ -33,7 +42,10 @@ func Until[T, D any](parser Parser[T], delimiter Parser[D]) Parser[[]T] {
 			match = append(match, m)
 
 			beforeDelimiter := in.Checkpoint()
-			_, ok, err = delimiter(in)
+			ok = false
+			if !repeat(in) {
+				_, ok, err = delimiter(in)
+			}
 			if err != nil {
 				in.Restore(start)
 				return nil, false, err
@@ -42,6 +54,10 @@ func Until[T, D any](parser Parser[T], delimiter Parser[D]) Parser[[]T] {
 				in.Restore(beforeDelimiter)
 				return match, true, nil
 			}
+			if beforeDelimiter.index == beforeParser.index {
+				in.Restore(start)
+				return nil, false, &NoProgressError{Combinator: "Until", Parser: parserName(parser), Position: beforeParser.index}
+			}
 		}
 	}
 }
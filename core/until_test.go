@@ -67,6 +67,30 @@ func TestUntil(t *testing.T) {
 			Parser:     core.UntilEOF(core.AnyRune, core.Digit),
 			ExpectedOK: false, // needs at least one match before EOF
 		},
+		{
+			Name:           "Until: optional that stops consuming before the delimiter",
+			Input:          "AAC",
+			Parser:         core.Until(core.StringFrom(core.Optional(core.Rune('A'))), core.Rune('B')),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "AAC",
+		},
+		{
+			Name:           "Until: optional that stops consuming at the delimiter",
+			Input:          "AAB",
+			Parser:         core.Until(core.StringFrom(core.Optional(core.Rune('A'))), core.Rune('B')),
+			ExpectedMatch:  []string{"A", "A"},
+			ExpectedOK:     true,
+			RemainingInput: "B",
+		},
+		{
+			Name:           "Until: EOF before the delimiter",
+			Input:          "",
+			Parser:         core.Until(core.EOF[string](), core.Rune('B')),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "",
+		},
 	}
 	RunTests(t, tests)
 
@@ -18,11 +18,23 @@ package core
 // This differs from until as the delimiter is searched for BEFORE the first match.
 func WhileNot[T, D any](parser Parser[T], delimiter Parser[D]) Parser[[]T] {
 	return func(in Input) ([]T, bool, error) {
+		if err := enter(in); err != nil {
+			return nil, false, err
+		}
+		defer leave(in)
 		start := in.Checkpoint()
 		match := make([]T, 0)
 		for {
+			if err := step(in); err != nil {
+				in.Restore(start)
+				return nil, false, err
+			}
 			beforeDelimiter := in.Checkpoint()
-			_, ok, err := delimiter(in)
+			var ok bool
+			var err error
+			if !repeat(in) {
+				_, ok, err = delimiter(in)
+			}
 			if err != nil {
 				in.Restore(start)
 				return nil, false, err
@@ -41,6 +53,10 @@ func WhileNot[T, D any](parser Parser[T], delimiter Parser[D]) Parser[[]T] {
 				in.Restore(start)
 				return nil, false, nil
 			}
+			if in.Checkpoint().index == beforeDelimiter.index {
+				in.Restore(start)
+				return nil, false, &NoProgressError{Combinator: "WhileNot", Parser: parserName(parser), Position: beforeDelimiter.index}
+			}
 			match = append(match, m)
 		}
 	}
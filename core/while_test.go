@@ -19,6 +19,7 @@ import (
 
 	"github.com/liamawhite/parse/core"
 	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestWhileNot(t *testing.T) {
@@ -68,7 +69,32 @@ func TestWhileNot(t *testing.T) {
 			ExpectedMatch: []string{},
 			ExpectedOK:    true,
 		},
+		{
+			Name:           "WhileNot: optional that stops consuming",
+			Input:          "AAC",
+			Parser:         core.WhileNot(core.StringFrom(core.Optional(core.Rune('A'))), core.Rune('B')),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "AAC",
+		},
+		{
+			Name:           "WhileNot: EOF",
+			Input:          "",
+			Parser:         core.WhileNot(core.EOF[string](), core.Rune('B')),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "",
+		},
 	}
 	RunTests(t, tests)
 
 }
+
+func TestWhileNotNoProgressError(t *testing.T) {
+	_, _, err := core.WhileNot(core.OptionalWhitespace, core.Rune('B'))(core.NewInput("A"))
+	var noProgress *core.NoProgressError
+	assert.ErrorAs(t, err, &noProgress)
+	assert.Equal(t, "WhileNot", noProgress.Combinator)
+	assert.Contains(t, noProgress.Parser, "core.")
+	assert.Equal(t, 0, noProgress.Position)
+}
@@ -17,10 +17,10 @@ package core
 import "unicode"
 
 // Whitespace parses whitespace.
-var Whitespace Parser[string] = StringFrom(OneOrMore(RuneInRanges(unicode.White_Space)))
+var Whitespace Parser[string] = StringFrom(SkipOneOrMore(RuneInRanges(unicode.White_Space)))
 
 // InlineWhitespace parses inline whitespace (spaces and tabs).
-var InlineWhitespace = StringFrom(OneOrMore(RuneIn(" \t")))
+var InlineWhitespace = StringFrom(SkipOneOrMore(RuneIn(" \t")))
 
 // OptionalWhitespace parses optional whitespace.
 var OptionalWhitespace = func(in Input) (output string, ok bool, err error) {
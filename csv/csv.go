@@ -0,0 +1,210 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// CSV parses comma separated values, see Records.
+var CSV = Records(',')
+
+// TSV parses tab separated values, see Records.
+var TSV = Records('\t')
+
+// Records parses values separated by the delimiter as defined by RFC 4180. The delimiter must not be a quote or line break.
+//
+// Records are separated by CRLF or LF line breaks and the last may be followed by one. Blank lines are skipped,
+// as they are by encoding/csv. A field containing the delimiter, a quote or a line break must be quoted, with
+// any quotes within it doubled, e.g. "say ""hi""". Line breaks within a quoted field are kept as written.
+// Records may have different numbers of fields, use TableOf when they should all match a header.
+//
+// All of the input is consumed, so it always matches unless the input is invalid, which is a *SyntaxError.
+// Lines and columns are counted from where the parser started, usually the start of the input.
+func Records(delimiter rune) Parser[[]Record] {
+	records := recordsOf(delimiter)
+	return func(in Input) ([]Record, bool, error) {
+		start := in.Checkpoint()
+		m, _, err := records(in)
+		if err != nil {
+			locateError(in, start, err)
+			return nil, false, err
+		}
+		locate(in, start, m)
+		return m, true, nil
+	}
+}
+
+// CSVTable parses comma separated values with a header, see TableOf.
+var CSVTable = TableOf(',')
+
+// TSVTable parses tab separated values with a header, see TableOf.
+var TSVTable = TableOf('\t')
+
+// TableOf parses records like Records, the first of which is a header naming the columns.
+// Every other record must have the same number of fields as the header or a *SyntaxError is returned.
+// It does not match if there is no header.
+func TableOf(delimiter rune) Parser[Table] {
+	records := Records(delimiter)
+	return func(in Input) (Table, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := records(in)
+		if err != nil || !ok || len(m) == 0 {
+			in.Restore(start)
+			return Table{}, false, err
+		}
+		header := m[0]
+		for _, record := range m[1:] {
+			if len(record.Fields) != len(header.Fields) {
+				in.Restore(start)
+				first := record.Fields[0]
+				return Table{}, false, &SyntaxError{
+					Position: first.Span.Start,
+					Line:     first.Line,
+					Column:   first.Column,
+					Reason:   fmt.Sprintf("record has %d fields but the header has %d", len(record.Fields), len(header.Fields)),
+				}
+			}
+		}
+		return Table{Header: header, Records: m[1:]}, true, nil
+	}
+}
+
+var (
+	quote      = Rune('"')
+	quoted     = QuotedString('"', '"')
+	lineBreaks = SkipZeroOrMore(NewLine)
+)
+
+// recordsOf parses the records without their lines and columns, which are filled in by Records.
+func recordsOf(delimiter rune) Parser[[]Record] {
+	records := SequenceOf3(lineBreaks, SepBy(recordOf(delimiter), SkipOneOrMore(NewLine)), lineBreaks)
+	return func(in Input) ([]Record, bool, error) {
+		m, ok, err := records(in)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		_, r, _ := m.Values()
+		return r, true, nil
+	}
+}
+
+// recordOf parses a record, which does not match a blank line.
+func recordOf(delimiter rune) Parser[Record] {
+	fields := SepBy1(fieldOf(delimiter), Rune(delimiter))
+	return func(in Input) (Record, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := fields(in)
+		if err != nil || !ok {
+			return Record{}, false, err
+		}
+		if in.Checkpoint() == start {
+			return Record{}, false, nil
+		}
+		return Record{Fields: m, Span: RangeFrom(start, in)}, true, nil
+	}
+}
+
+// fieldOf parses a quoted or unquoted field, which may be empty.
+func fieldOf(delimiter rune) Parser[Field] {
+	separator := Rune(delimiter)
+	unquoted := StringWhileNotEOFOr(Any(separator, NewLine, quote))
+	end := Any(separator, NewLine, EOF[string]())
+	return WithGenerator(func(in Input) (Field, bool, error) {
+		start := in.Checkpoint()
+		raw, ok, err := quoted(in)
+		if err != nil {
+			return Field{}, false, err
+		}
+		if ok {
+			at := in.Checkpoint()
+			_, ok, err := end(in)
+			in.Restore(at)
+			if err != nil || !ok {
+				if err == nil {
+					err = &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "delimiter or line break after closing quote")}
+				}
+				in.Restore(start)
+				return Field{}, false, err
+			}
+			return Field{Value: strings.ReplaceAll(raw, `""`, `"`), Quoted: true, Span: RangeFrom(start, in)}, true, nil
+		}
+		if next, _ := in.Peek(1); next == `"` {
+			return Field{}, false, &SyntaxError{Position: start.Position(), Reason: "quoted field is not closed"}
+		}
+
+		value, _, err := unquoted(in)
+		if err != nil {
+			return Field{}, false, err
+		}
+		if next, _ := in.Peek(1); next == `"` {
+			err := &SyntaxError{Position: in.Checkpoint().Position(), Reason: "quote in unquoted field, quote the whole field and double the quotes within it"}
+			in.Restore(start)
+			return Field{}, false, err
+		}
+		return Field{Value: value, Span: RangeFrom(start, in)}, true, nil
+	}, generateField(delimiter))
+}
+
+// locate fills in the line and column of each field from the text the records were parsed from.
+func locate(in Input, start Checkpoint, records []Record) {
+	text, _ := in.Peek(start.Position() - in.Checkpoint().Position())
+	var lines LineCounter
+	for i := range records {
+		for j := range records[i].Fields {
+			field := &records[i].Fields[j]
+			field.Line, field.Column = lines.Find(text, field.Span.Start-start.Position())
+		}
+	}
+}
+
+// locateError fills in the line and column of a syntax error, the input having been restored to the start.
+func locateError(in Input, start Checkpoint, err error) {
+	var syntax *SyntaxError
+	if !errors.As(err, &syntax) {
+		return
+	}
+	text, _ := in.Peek(syntax.Position - start.Position())
+	syntax.Line, syntax.Column = LineColumn(text, len(text))
+}
+
+// Characters an unquoted generated field is made up of, quoted fields may also contain a quote or line break.
+var generateFieldParts = []string{"a", "Z", "0", " ", "-", "é", "日", "😀"}
+
+func generateField(delimiter rune) func(r *rand.Rand) string {
+	return func(r *rand.Rand) string {
+		var s strings.Builder
+		q := r.IntN(3) == 0
+		if q {
+			s.WriteByte('"')
+		}
+		for n := r.IntN(6); n > 0; n-- {
+			if q && r.IntN(4) == 0 {
+				s.WriteString([]string{`""`, "\n", "\r\n", string(delimiter)}[r.IntN(4)])
+				continue
+			}
+			s.WriteString(generateFieldParts[r.IntN(len(generateFieldParts))])
+		}
+		if q {
+			s.WriteByte('"')
+		}
+		return s.String()
+	}
+}
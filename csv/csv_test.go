@@ -0,0 +1,222 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv_test
+
+import (
+	stdcsv "encoding/csv"
+	"errors"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/csv"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// values compares records by their values alone.
+func values(a, b []Record) bool {
+	return assert.ObjectsAreEqual(recordValues(a), recordValues(b))
+}
+
+func recordValues(records []Record) [][]string {
+	v := make([][]string, len(records))
+	for i, record := range records {
+		v[i] = record.Values()
+	}
+	return v
+}
+
+func record(values ...string) Record {
+	r := Record{}
+	for _, v := range values {
+		r.Fields = append(r.Fields, Field{Value: v})
+	}
+	return r
+}
+
+func TestRecords(t *testing.T) {
+	tests := []ParserTest[[]Record]{
+		{Name: "empty", Input: "", Parser: CSV, ExpectedMatch: []Record{}, ExpectedOK: true},
+		{Name: "single field", Input: "a", Parser: CSV, ExpectedMatch: []Record{record("a")}, ExpectedOK: true},
+		{Name: "LF", Input: "a,b\nc,d\n", Parser: CSV, ExpectedMatch: []Record{record("a", "b"), record("c", "d")}, ExpectedOK: true},
+		{Name: "CRLF", Input: "a,b\r\nc,d\r\n", Parser: CSV, ExpectedMatch: []Record{record("a", "b"), record("c", "d")}, ExpectedOK: true},
+		{Name: "no final line break", Input: "a,b\r\nc,d", Parser: CSV, ExpectedMatch: []Record{record("a", "b"), record("c", "d")}, ExpectedOK: true},
+		{Name: "empty fields", Input: ",a,,\n", Parser: CSV, ExpectedMatch: []Record{record("", "a", "", "")}, ExpectedOK: true},
+		{Name: "blank lines are skipped", Input: "\na\n\r\n\nb\n\n", Parser: CSV, ExpectedMatch: []Record{record("a"), record("b")}, ExpectedOK: true},
+		{Name: "spaces are kept", Input: " a , b ", Parser: CSV, ExpectedMatch: []Record{record(" a ", " b ")}, ExpectedOK: true},
+		{Name: "quoted", Input: `"a","b,c"`, Parser: CSV, ExpectedMatch: []Record{record("a", "b,c")}, ExpectedOK: true},
+		{Name: "empty quoted", Input: `"",""`, Parser: CSV, ExpectedMatch: []Record{record("", "")}, ExpectedOK: true},
+		{Name: "doubled quotes", Input: `"say ""hi""",""""`, Parser: CSV, ExpectedMatch: []Record{record(`say "hi"`, `"`)}, ExpectedOK: true},
+		{Name: "embedded line breaks", Input: "\"a\nb\",\"c\r\nd\"\ne", Parser: CSV, ExpectedMatch: []Record{record("a\nb", "c\r\nd"), record("e")}, ExpectedOK: true},
+		{Name: "lone carriage return", Input: "a\rb", Parser: CSV, ExpectedMatch: []Record{record("a\rb")}, ExpectedOK: true},
+		{Name: "different numbers of fields", Input: "a\nb,c", Parser: CSV, ExpectedMatch: []Record{record("a"), record("b", "c")}, ExpectedOK: true},
+		{Name: "TSV", Input: "a\t\"b\tc\"\n1,2\t3", Parser: TSV, ExpectedMatch: []Record{record("a", "b\tc"), record("1,2", "3")}, ExpectedOK: true},
+		{Name: "custom delimiter", Input: "a;b,c;日", Parser: Records(';'), ExpectedMatch: []Record{record("a", "b,c", "日")}, ExpectedOK: true},
+		{Name: "multibyte delimiter", Input: "a→b", Parser: Records('→'), ExpectedMatch: []Record{record("a", "b")}, ExpectedOK: true},
+		{Name: "bare quote", Input: `a"b`, Parser: CSV, ExpectedOK: false, WantErr: true, RemainingInput: `a"b`},
+		{Name: "not closed", Input: "a\n\"b", Parser: CSV, ExpectedOK: false, WantErr: true, RemainingInput: "a\n\"b"},
+	}
+	for i := range tests {
+		tests[i].Equal = values
+	}
+	RunTests(t, tests)
+}
+
+func TestPositions(t *testing.T) {
+	records, ok, err := CSV(core.NewInput("name,due\r\n\"call\nmum\", 2024-02-01\n\ncafé,\"\"\n"))
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, []Record{
+		{Span: core.Range{Start: 0, End: 8}, Fields: []Field{
+			{Value: "name", Span: core.Range{Start: 0, End: 4}, Line: 1, Column: 1},
+			{Value: "due", Span: core.Range{Start: 5, End: 8}, Line: 1, Column: 6},
+		}},
+		{Span: core.Range{Start: 10, End: 32}, Fields: []Field{
+			{Value: "call\nmum", Quoted: true, Span: core.Range{Start: 10, End: 20}, Line: 2, Column: 1},
+			{Value: " 2024-02-01", Span: core.Range{Start: 21, End: 32}, Line: 3, Column: 6},
+		}},
+		{Span: core.Range{Start: 34, End: 42}, Fields: []Field{
+			{Value: "café", Span: core.Range{Start: 34, End: 39}, Line: 5, Column: 1},
+			{Value: "", Quoted: true, Span: core.Range{Start: 40, End: 42}, Line: 5, Column: 6},
+		}},
+	}, records)
+}
+
+func TestTable(t *testing.T) {
+	table, ok, err := CSVTable(core.NewInput("name,due\nwater the plants,2024-02-01\ncall mum,\n"))
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"name", "due"}, table.Header.Values())
+	assert.Equal(t, [][]string{{"water the plants", "2024-02-01"}, {"call mum", ""}}, recordValues(table.Records))
+	assert.Equal(t, 1, table.Column("due"))
+	assert.Equal(t, -1, table.Column("priority"))
+
+	due, ok := table.Field(table.Records[0], "due")
+	assert.True(t, ok)
+	assert.Equal(t, Field{Value: "2024-02-01", Span: core.Range{Start: 26, End: 36}, Line: 2, Column: 18}, due)
+	_, ok = table.Field(table.Records[0], "priority")
+	assert.False(t, ok)
+
+	_, ok, err = CSVTable(core.NewInput(""))
+	assert.False(t, ok)
+	assert.NoError(t, err)
+
+	table, ok, err = TSVTable(core.NewInput("name\tdue\n"))
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Empty(t, table.Records)
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		parser   core.Parser[Table]
+		position int
+		line     int
+		column   int
+		reason   string
+	}{
+		{"a,b\nc,\"d", CSVTable, 6, 2, 3, "quoted field is not closed"},
+		{"a,b\n\"c\"d", CSVTable, 7, 2, 4, "expected delimiter or line break after closing quote, found 'd'"},
+		{"a,b\n\"c\" ,d", CSVTable, 7, 2, 4, "expected delimiter or line break after closing quote, found ' '"},
+		{"a,b\ncafé \"latte\"", CSVTable, 10, 2, 6, "quote in unquoted field, quote the whole field and double the quotes within it"},
+		{"a,b\nc,d\ne\n", CSVTable, 8, 3, 1, "record has 1 fields but the header has 2"},
+		{"a\tb\nc\td\te", TSVTable, 4, 2, 1, "record has 3 fields but the header has 2"},
+		{"\"a\nb\",c\nd,\"e", CSVTable, 10, 3, 3, "quoted field is not closed"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			in := core.NewInput(test.input)
+			_, ok, err := test.parser(in)
+			assert.False(t, ok)
+			assert.Equal(t, 0, in.Checkpoint().Position())
+			var syntax *SyntaxError
+			if assert.True(t, errors.As(err, &syntax), "expected a syntax error, got %v", err) {
+				assert.Equal(t, test.position, syntax.Position)
+				assert.Equal(t, test.line, syntax.Line)
+				assert.Equal(t, test.column, syntax.Column)
+				assert.Equal(t, test.reason, syntax.Reason)
+			}
+		})
+	}
+}
+
+// Cases covering the corners of RFC 4180, compared against encoding/csv.
+func TestCompliance(t *testing.T) {
+	inputs := []string{
+		// Accepted.
+		"", "\n", "a", "a,b,c\n1,2,3\n", "a,b\r\n1,2\r\n", `"a","b"`, `"a""b"`, `""""`, `"",""`, ",,", "a,\n,b",
+		"\"a\nb\"\nc", "\"a\r\nb\"", "a\n\n\nb", " a ,b ", "a\rb", "日本,語", "\"a,b\",c", "a,\"\"\n",
+		// Rejected.
+		`"a`, `a"b`, `"a"b`, `"a" `, `a,"b`, "\"a\n", ` "a"`,
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			expected, expectedErr := readAll(input, ',')
+			actual, ok, err := CSV(core.NewInput(input))
+			if assert.Equal(t, expectedErr == nil, ok && err == nil, "encoding/csv err: %v, err: %v", expectedErr, err) && ok {
+				assert.Equal(t, expected, normalize(recordValues(actual)))
+			}
+		})
+	}
+}
+
+// readAll reads the input with encoding/csv, allowing records to have different numbers of fields.
+func readAll(s string, delimiter rune) ([][]string, error) {
+	r := stdcsv.NewReader(strings.NewReader(s))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if records == nil {
+		records = [][]string{}
+	}
+	return records, err
+}
+
+// normalize replaces CRLF line breaks within fields with LF, as encoding/csv does.
+func normalize(records [][]string) [][]string {
+	for _, record := range records {
+		for i := range record {
+			record[i] = strings.ReplaceAll(record[i], "\r\n", "\n")
+		}
+	}
+	return records
+}
+
+func TestRollback(t *testing.T) {
+	CheckRollback(t, CSV, "a,b\nc,d", `"a`, `a"b`, `"a"b`)
+	CheckRollback(t, CSVTable, "a,b\nc,d", "a,b\nc", `"a`, "")
+}
+
+// Generated records are read the same way by encoding/csv.
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for _, delimiter := range []rune{',', '\t'} {
+		parser := Records(delimiter)
+		for range 200 {
+			s, ok := Generate(parser, r)
+			if !assert.True(t, ok) {
+				continue
+			}
+			actual, _, _ := parser(core.NewInput(s))
+			expected, err := readAll(s, delimiter)
+			if assert.NoError(t, err, s) {
+				assert.Equal(t, expected, normalize(recordValues(actual)), s)
+			}
+		}
+	}
+}
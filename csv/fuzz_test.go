@@ -0,0 +1,53 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/csv"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var fuzzSeeds = []string{"a,b\r\n\"c\nd\",\"e\"\"f\"\n", `"a`, `a"b`, `"a"b`, "\n\na,\n", "a\rb"}
+
+func FuzzCSV(f *testing.F) {
+	FuzzParser(f, CSV, fuzzSeeds...)
+}
+
+func FuzzCSVTable(f *testing.F) {
+	FuzzParser(f, CSVTable, fuzzSeeds...)
+}
+
+// FuzzEncodingCSV checks that exactly the same inputs are valid as for encoding/csv and that they are read the same way.
+func FuzzEncodingCSV(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		// encoding/csv drops a carriage return at the end of the input.
+		if strings.HasSuffix(s, "\r") {
+			t.Skip()
+		}
+		expected, expectedErr := readAll(s, ',')
+		actual, ok, err := CSV(core.NewInput(s))
+		if assert.Equal(t, expectedErr == nil, ok && err == nil, "encoding/csv err: %v, err: %v", expectedErr, err) && ok {
+			assert.Equal(t, expected, normalize(recordValues(actual)))
+		}
+	})
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csv
+
+import (
+	"fmt"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Field is a single value within a record along with where it was written, so that errors found
+// when importing the value can point to the cell it came from.
+type Field struct {
+	// Value with the quotes removed and any doubled quotes within it undoubled.
+	Value string
+	// Quoted reports whether the field was written in quotes.
+	Quoted bool
+	// Span of the field in the input, including its quotes.
+	Span Range
+	// Line and Column of the start of the field, both counted from 1. Columns are counted in runes.
+	Line   int
+	Column int
+}
+
+// Record is a line of fields, which spans several lines of the input if a quoted field contains a line break.
+type Record struct {
+	Fields []Field
+	// Span of the record in the input, excluding its line break.
+	Span Range
+}
+
+// Values returns the value of each field in the record.
+func (r Record) Values() []string {
+	values := make([]string, len(r.Fields))
+	for i, field := range r.Fields {
+		values[i] = field.Value
+	}
+	return values
+}
+
+// Table is a header record naming each column followed by the records, which all have a field for each column.
+type Table struct {
+	Header  Record
+	Records []Record
+}
+
+// Column returns the index of the first column with the name, or -1 if there isn't one.
+func (t Table) Column(name string) int {
+	for i, field := range t.Header.Fields {
+		if field.Value == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Field returns the field of the record in the first column with the name.
+func (t Table) Field(record Record, name string) (Field, bool) {
+	i := t.Column(name)
+	if i < 0 || i >= len(record.Fields) {
+		return Field{}, false
+	}
+	return record.Fields[i], true
+}
+
+// SyntaxError is returned when the input is not valid CSV, e.g. a quoted field that isn't closed.
+type SyntaxError struct {
+	Position int
+	// Line and Column of the error, both counted from 1. Columns are counted in runes.
+	Line   int
+	Column int
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid CSV at line %d, column %d: %s", e.Line, e.Column, e.Reason)
+}
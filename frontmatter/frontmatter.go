@@ -0,0 +1,98 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Extract parses the frontmatter at the start of a document without parsing its YAML, e.g. to hand it to another
+// YAML library. Frontmatter starts with a line holding only --- and ends with the next such line, which may be
+// followed by trailing spaces and either line break. It does not match unless the input starts with frontmatter
+// that is closed, as a Markdown document may also start with a thematic break.
+var Extract Parser[Block] = func(in Input) (Block, bool, error) {
+	start := in.Checkpoint()
+	if _, ok, err := opening(in); err != nil || !ok {
+		in.Restore(start)
+		return Block{}, false, err
+	}
+	contentStart := in.Checkpoint()
+	_, ok, err := delimiter(in)
+	if err != nil {
+		in.Restore(start)
+		return Block{}, false, err
+	}
+	if !ok {
+		if _, ok, err := content(in); err != nil || !ok {
+			in.Restore(start)
+			return Block{}, false, err
+		}
+		// The line break before the closing delimiter is part of the text.
+		NewLine(in)
+		text, _ := in.Peek(contentStart.Position() - in.Checkpoint().Position())
+		contentSpan := RangeFrom(contentStart, in)
+		delimiter(in)
+		return Block{Text: text, Span: RangeFrom(start, in), Content: contentSpan}, true, nil
+	}
+	return Block{Span: RangeFrom(start, in), Content: Range{Start: contentStart.Position(), End: contentStart.Position()}}, true, nil
+}
+
+var (
+	delimiter = SequenceOf3(String("---"), OptionalInlineWhitespace, Any(NewLine, EOF[string]()))
+	opening   = SequenceOf3(String("---"), OptionalInlineWhitespace, NewLine)
+	content   = StringUntil(SequenceOf2(NewLine, delimiter))
+)
+
+// Frontmatter parses the frontmatter at the start of a document like Extract, along with its YAML, which must be a map.
+// Once the input starts with closed frontmatter, YAML that is invalid or not supported is a *SyntaxError, see YAML.
+// Lines and columns are counted from where the parser started, usually the start of the document.
+var Frontmatter Parser[Block] = WithGenerator(func(in Input) (Block, bool, error) {
+	start := in.Checkpoint()
+	block, ok, err := Extract(in)
+	if err != nil || !ok {
+		return Block{}, false, err
+	}
+	end := in.Checkpoint()
+	in.Restore(start)
+	opening(in)
+	root, err := document(in)
+	if err == nil && root.Kind != MapKind {
+		err = &SyntaxError{Position: root.Span.Start, Reason: fmt.Sprintf("frontmatter must be a map of keys to values, found a %s", root.Kind)}
+	}
+	if err != nil {
+		in.Restore(start)
+		locateError(in, start, err)
+		return Block{}, false, err
+	}
+	in.Restore(end)
+	block.Root = root
+	return block, true, nil
+}, func(r *rand.Rand) string {
+	return "---\n" + generateDocument(r) + "---\n"
+})
+
+// locateError fills in the line and column of a syntax error, the input having been restored to the start.
+func locateError(in Input, start Checkpoint, err error) {
+	var syntax *SyntaxError
+	if !errors.As(err, &syntax) {
+		return
+	}
+	text, _ := in.Peek(syntax.Position - start.Position())
+	syntax.Line, syntax.Column = LineColumn(text, len(text))
+}
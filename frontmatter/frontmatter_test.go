@@ -0,0 +1,258 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter_test
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/frontmatter"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestExtract(t *testing.T) {
+	block := func(text string, start, end int) Block {
+		return Block{Text: text, Span: core.Range{Start: 0, End: end}, Content: core.Range{Start: start, End: start + len(text)}}
+	}
+	tests := []ParserTest[Block]{
+		{Name: "LF", Input: "---\ntitle: Notes\n---\n# Notes", Parser: Extract, ExpectedMatch: block("title: Notes\n", 4, 21), ExpectedOK: true, RemainingInput: "# Notes"},
+		{Name: "CRLF", Input: "---\r\ntitle: Notes\r\n---\r\nbody", Parser: Extract, ExpectedMatch: block("title: Notes\r\n", 5, 24), ExpectedOK: true, RemainingInput: "body"},
+		{Name: "empty", Input: "---\n---\n", Parser: Extract, ExpectedMatch: block("", 4, 8), ExpectedOK: true},
+		{Name: "no body", Input: "---\na: 1\n---", Parser: Extract, ExpectedMatch: block("a: 1\n", 4, 12), ExpectedOK: true},
+		{Name: "trailing spaces", Input: "--- \na: 1\n---\t\nbody", Parser: Extract, ExpectedMatch: block("a: 1\n", 5, 15), ExpectedOK: true, RemainingInput: "body"},
+		{Name: "ends at the first delimiter", Input: "---\na: 1\n---\n---\n", Parser: Extract, ExpectedMatch: block("a: 1\n", 4, 13), ExpectedOK: true, RemainingInput: "---\n"},
+		{Name: "YAML is not parsed", Input: "---\n: [\n---\n", Parser: Extract, ExpectedMatch: block(": [\n", 4, 12), ExpectedOK: true},
+		{Name: "not closed", Input: "---\ntitle: Notes\n", Parser: Extract, ExpectedOK: false, RemainingInput: "---\ntitle: Notes\n"},
+		{Name: "not at the start", Input: "\n---\na: 1\n---\n", Parser: Extract, ExpectedOK: false, RemainingInput: "\n---\na: 1\n---\n"},
+		{Name: "longer rule", Input: "----\na: 1\n----\n", Parser: Extract, ExpectedOK: false, RemainingInput: "----\na: 1\n----\n"},
+		{Name: "no frontmatter", Input: "# Notes\n", Parser: Extract, ExpectedOK: false, RemainingInput: "# Notes\n"},
+	}
+	RunTests(t, tests)
+}
+
+func TestFrontmatter(t *testing.T) {
+	s := "---\ntitle: Notes # draft\ntags: [home, work]\nauthor:\n  name: Liam\n---\n# Notes\n"
+	in := core.NewInput(s)
+	block, ok, err := Frontmatter(in)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	rest, _ := in.Peek(len(s) - block.Span.End)
+	assert.Equal(t, "# Notes\n", rest)
+	assert.Equal(t, map[string]any{"title": "Notes", "tags": []any{"home", "work"}, "author": map[string]any{"name": "Liam"}}, block.Root.Value())
+
+	// An editor can rewrite a single value, leaving the rest of the document as it was.
+	title, ok := block.Root.Lookup("title")
+	assert.True(t, ok)
+	edited := s[:title.Span.Start] + `"Meeting notes"` + s[title.Span.End:]
+	assert.Equal(t, "---\ntitle: \"Meeting notes\" # draft\ntags: [home, work]\nauthor:\n  name: Liam\n---\n# Notes\n", edited)
+
+	name, ok := block.Root.Lookup("author", "name")
+	assert.True(t, ok)
+	assert.Equal(t, "Liam", s[name.Span.Start:name.Span.End])
+	_, ok = block.Root.Lookup("author", "email")
+	assert.False(t, ok)
+
+	tags, ok := block.Root.Entry("tags")
+	assert.True(t, ok)
+	assert.Equal(t, "tags: [home, work]", s[tags.Span.Start:tags.Span.End])
+	assert.Equal(t, FlowStyle, tags.Value.Style)
+
+	block, ok, err = Frontmatter(core.NewInput("---\n# nothing yet\n---\n"))
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{}, block.Root.Value())
+
+	_, ok, err = Frontmatter(core.NewInput("---\n\nSome text after a thematic break\n"))
+	assert.False(t, ok)
+	assert.NoError(t, err)
+}
+
+func TestYAML(t *testing.T) {
+	tests := []ParserTest[any]{
+		{Name: "empty", Input: "", ExpectedMatch: map[string]any{}},
+		{Name: "comments only", Input: "# a\n\n  # b\n", ExpectedMatch: map[string]any{}},
+		{Name: "plain scalars", Input: "a: notes\nb: water the plants\nc: http://example.com/a#b\nd: a # comment", ExpectedMatch: map[string]any{"a": "notes", "b": "water the plants", "c": "http://example.com/a#b", "d": "a"}},
+		{Name: "nulls", Input: "a:\nb: ~\nc: null\nd: NULL", ExpectedMatch: map[string]any{"a": nil, "b": nil, "c": nil, "d": nil}},
+		{Name: "bools", Input: "a: true\nb: False\nc: yes", ExpectedMatch: map[string]any{"a": true, "b": false, "c": "yes"}},
+		{Name: "numbers", Input: "a: 42\nb: -7\nc: 0o17\nd: 0x1F\ne: 1.5e3\nf: .5\ng: -.inf\nh: 1.2.3", ExpectedMatch: map[string]any{"a": 42, "b": -7, "c": 15, "d": 31, "e": 1500.0, "f": 0.5, "g": math.Inf(-1), "h": "1.2.3"}},
+		{Name: "dates are strings", Input: "due: 2024-02-01", ExpectedMatch: map[string]any{"due": "2024-02-01"}},
+		{Name: "single quoted", Input: `a: 'it''s # not a comment'`, ExpectedMatch: map[string]any{"a": "it's # not a comment"}},
+		{Name: "double quoted", Input: `a: "say \"hi\"\t\u00e9\x41\/"`, ExpectedMatch: map[string]any{"a": "say \"hi\"\té" + "A/"}},
+		{Name: "quoted scalars are strings", Input: `a: "42"` + "\nb: 'true'", ExpectedMatch: map[string]any{"a": "42", "b": "true"}},
+		{Name: "quoted keys", Input: `"a: b": 1` + "\n'c': 2", ExpectedMatch: map[string]any{"a: b": 1, "c": 2}},
+		{Name: "nested maps", Input: "a:\n  b:\n    c: 1\n  d: 2\ne: 3", ExpectedMatch: map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}, "d": 2}, "e": 3}},
+		{Name: "lists", Input: "a:\n  - 1\n  -   two\nb:\n- x\n-\n- - y\n  - z", ExpectedMatch: map[string]any{"a": []any{1, "two"}, "b": []any{"x", nil, []any{"y", "z"}}}},
+		{Name: "maps in lists", Input: "tasks:\n  - name: water the plants\n    due: monday\n  -\n    name: call mum", ExpectedMatch: map[string]any{"tasks": []any{map[string]any{"name": "water the plants", "due": "monday"}, map[string]any{"name": "call mum"}}}},
+		{Name: "scalar on the next line", Input: "a:\n  notes", ExpectedMatch: map[string]any{"a": "notes"}},
+		{Name: "flow", Input: "a: [1, 'two', [3], {b: c}]\nd: {e: [], f: }\ng: [\n  x,  # comment\n  y,\n]", ExpectedMatch: map[string]any{"a": []any{1, "two", []any{3}, map[string]any{"b": "c"}}, "d": map[string]any{"e": []any{}, "f": nil}, "g": []any{"x", "y"}}},
+		{Name: "literal", Input: "a: |\n  one\n   two\n\n  three\n\nb: 1", ExpectedMatch: map[string]any{"a": "one\n two\n\nthree\n", "b": 1}},
+		{Name: "folded", Input: "a: >\n  one\n  two\n\n  three\n    four\n  five\n", ExpectedMatch: map[string]any{"a": "one two\nthree\n  four\nfive\n"}},
+		{Name: "strip", Input: "a: |-\n  one\n\n", ExpectedMatch: map[string]any{"a": "one"}},
+		{Name: "keep", Input: "a: >+\n  one\n\n\nb: 1", ExpectedMatch: map[string]any{"a": "one\n\n\n", "b": 1}},
+		{Name: "indentation indicator", Input: "a: |2-\n    one\n  two", ExpectedMatch: map[string]any{"a": "  one\ntwo"}},
+		{Name: "in a list", Input: "- |\n  one\n- two", ExpectedMatch: []any{"one\n", "two"}},
+		{Name: "CRLF", Input: "a: 1\r\nb:\r\n  - x\r\nc: |\r\n  y\r\n", ExpectedMatch: map[string]any{"a": 1, "b": []any{"x"}, "c": "y\n"}},
+		{Name: "root list", Input: "- a\n- b", ExpectedMatch: []any{"a", "b"}},
+		{Name: "root scalar", Input: "notes", ExpectedMatch: "notes"},
+		{Name: "ends at a delimiter", Input: "a: 1\n---\nbody", ExpectedMatch: map[string]any{"a": 1}, RemainingInput: "---\nbody"},
+		{Name: "invalid", Input: "a: 1\n  b: 2", WantErr: true, RemainingInput: "a: 1\n  b: 2"},
+	}
+	for i := range tests {
+		tests[i].Parser = value
+		tests[i].ExpectedOK = !tests[i].WantErr
+	}
+	RunTests(t, tests)
+}
+
+// value parses YAML returning its value.
+func value(in core.Input) (any, bool, error) {
+	node, ok, err := YAML(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return node.Value(), true, nil
+}
+
+func TestSpans(t *testing.T) {
+	str := func(text string, style Style, start, end int) Node {
+		return Node{Style: style, Text: text, Span: core.Range{Start: start, End: end}}
+	}
+	node, ok, err := YAML(core.NewInput("a: 'x' # c\nb:\n  - |\n    y\n\n  - [z]\nc:\n"))
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, Node{Kind: MapKind, Span: core.Range{Start: 0, End: 37}, Entries: []Entry{
+		{Key: str("a", PlainStyle, 0, 1), Value: str("x", SingleQuotedStyle, 3, 6), Span: core.Range{Start: 0, End: 6}},
+		{
+			Key: str("b", PlainStyle, 11, 12),
+			Value: Node{Kind: ListKind, Span: core.Range{Start: 16, End: 34}, Items: []Node{
+				str("y\n", LiteralStyle, 18, 25),
+				{Kind: ListKind, Style: FlowStyle, Span: core.Range{Start: 31, End: 34}, Items: []Node{str("z", PlainStyle, 32, 33)}},
+			}},
+			Span: core.Range{Start: 11, End: 34},
+		},
+		{Key: str("c", PlainStyle, 35, 36), Value: str("", PlainStyle, 37, 37), Span: core.Range{Start: 35, End: 37}},
+	}}, node)
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		input  string
+		line   int
+		column int
+		reason string
+	}{
+		{"---\ntitle: Notes: draft\n---\n", 2, 13, "unexpected ':', quote values that contain ': '"},
+		{"---\ntitle: \"Notes\n---\n", 2, 8, "quoted scalar must be closed on the same line"},
+		{"---\ntitle: 'Notes' draft\n---\n", 2, 16, "expected line break, found 'd'"},
+		{"---\ntitle: \"caf\\é\"\n---\n", 2, 12, `invalid escape \é`},
+		{"---\ntitle: \"\\u00\"\n---\n", 2, 9, `\u must be followed by 4 hex digits`},
+		{"---\na: 1\na: 2\n---\n", 3, 1, `duplicate key "a"`},
+		{"---\na: {b: 1, b: 2}\n---\n", 2, 11, `duplicate key "b"`},
+		{"---\na: 1\n  b: 2\n---\n", 3, 3, "unexpected indentation"},
+		{"---\na:\n  b: 1\n c: 2\n---\n", 4, 2, "unexpected indentation"},
+		{"---\na: 1\n- b\n---\n", 3, 1, "expected key, found '-'"},
+		{"---\na:\n  - b\n  c: 1\n---\n", 4, 3, "unexpected indentation"},
+		{"---\n\ta: 1\n---\n", 2, 1, "tabs are not allowed in indentation"},
+		{"---\na: &anchor 1\n---\n", 2, 4, "anchors, aliases and tags are not supported"},
+		{"---\na: !!str 1\n---\n", 2, 4, "anchors, aliases and tags are not supported"},
+		{"---\na: [1, 2}\n---\n", 2, 9, "expected ',' or ']', found '}'"},
+		{"---\na: [1,\n---\n", 2, 4, "flow list is not closed"},
+		{"---\na: {b: [1]\n---\n", 2, 4, "flow map is not closed"},
+		{"---\na: {b}\n---\n", 2, 6, "expected ':', found '}'"},
+		{"---\na: @b\n---\n", 2, 4, "expected value, found '@'"},
+		{"---\n? a\n---\n", 2, 1, "expected value, found '?'"},
+		{"---\n日本: |x\n---\n", 2, 6, "expected line break, found 'x'"},
+		{"---\n- a\n---\n", 2, 1, "frontmatter must be a map of keys to values, found a list"},
+		{"---\nnotes\n---\n", 2, 1, "frontmatter must be a map of keys to values, found a scalar"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			in := core.NewInput(test.input)
+			_, ok, err := Frontmatter(in)
+			assert.False(t, ok)
+			assert.Equal(t, 0, in.Checkpoint().Position())
+			var syntax *SyntaxError
+			if assert.True(t, errors.As(err, &syntax), "expected a syntax error, got %v", err) {
+				assert.Equal(t, test.line, syntax.Line)
+				assert.Equal(t, test.column, syntax.Column)
+				assert.Equal(t, test.reason, syntax.Reason)
+			}
+		})
+	}
+}
+
+// Frontmatter from Markdown notes along with corners of YAML, compared against gopkg.in/yaml.v3.
+func TestCompliance(t *testing.T) {
+	inputs := []string{
+		"title: Notes\ntags:\n  - home\n  - work\ndraft: false\nweight: 10\n",
+		"title: \"Notes: 2024\"\naliases: [/notes, '/old notes']\nsummary: >-\n  A long summary\n  over two lines.\n",
+		"author:\n  name: Liam\n  links:\n    - https://example.com\n    - {name: GitHub, url: 'https://github.com'}\n",
+		"tasks:\n- name: water the plants\n  done: true\n- name: call mum\n  due: null\n",
+		"a: |\n  literal\n    indented\n\n  text\nb: >\n  folded\n  text\n\n  paragraph\n    indented\n  end\n",
+		"a: |+\n  keep\n\nb: |-\n  strip\n\nc: >2\n    indented\n",
+		"a: 'single ''quoted'''\nb: \"\\ttab \\\"quote\\\" \\\\ \\u263A \\x41\"\nc: plain # comment\n",
+		"a: ~\nb:\nc: NULL\nd: .inf\ne: -.Inf\nf: 0o14\ng: 0xFF\nh: +12\ni: 1e3\nj: 3.\n",
+		"a: [1, [2, [3]], {b: {c: d}}]\ne: {}\nf: []\n",
+		"- a\n- - b\n  - c\n- d: e\n  f: g\n",
+		"a:\n  b:\n    c:\n      d: deep\n",
+		"# comment\n\n   # indented comment\na: 1 # trailing\n\n",
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			var expected any
+			expectedErr := yaml.Unmarshal([]byte(input), &expected)
+			actual, ok, err := value(core.NewInput(input))
+			if expectedErr != nil {
+				assert.False(t, ok && err == nil, "expected an error as for yaml.v3: %v", expectedErr)
+				return
+			}
+			if assert.True(t, ok, "yaml.v3 accepted the input: %v", err) {
+				assert.Equal(t, expected, actual)
+			}
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	CheckRollback(t, Frontmatter, "---\na: [1]\n---\nbody", "---\na: 1\n", "---\na: [\n---\n", "---\n- a\n---\n", "# Notes")
+	CheckRollback(t, Extract, "---\na: 1\n---\n", "---\na: 1\n", "---")
+	CheckRollback(t, YAML, "a:\n  - b: |\n      c\n", "a: 1\n a: 2", `a: "\x"`)
+}
+
+// Generated frontmatter is read the same way by gopkg.in/yaml.v3.
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	styles := map[Style]bool{}
+	for range 200 {
+		s, ok := Generate(Frontmatter, r)
+		if !assert.True(t, ok) {
+			continue
+		}
+		block, _, _ := Frontmatter(core.NewInput(s))
+		var expected any
+		if assert.NoError(t, yaml.Unmarshal([]byte(block.Text), &expected), s) {
+			assert.Equal(t, expected, block.Root.Value(), s)
+		}
+		for _, entry := range block.Root.Entries {
+			styles[entry.Value.Style] = true
+		}
+	}
+	assert.Len(t, styles, 6, "expected every style of value to be generated")
+}
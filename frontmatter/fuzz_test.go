@@ -0,0 +1,40 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter_test
+
+import (
+	"testing"
+
+	. "github.com/liamawhite/parse/frontmatter"
+	. "github.com/liamawhite/parse/test"
+)
+
+var fuzzSeeds = []string{
+	"---\ntitle: \"Notes\\n\"\ntags: [home, {a: 1}]\nbody: |-\n  text\n---\n# Notes",
+	"---\r\na:\r\n- b: 'c'\r\n---\r\n",
+	"---\na: 1\n  b: 2\n---\n",
+	"---\na: [1,\n---\n",
+	"---\na: \"\\u00\"\n---\n",
+	"---\na: >+\n\n  b\n\n---",
+	"---\n",
+}
+
+func FuzzFrontmatter(f *testing.F) {
+	FuzzParser(f, Frontmatter, fuzzSeeds...)
+}
+
+func FuzzYAML(f *testing.F) {
+	FuzzParser(f, YAML, fuzzSeeds...)
+}
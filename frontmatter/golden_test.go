@@ -0,0 +1,54 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter_test
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/frontmatter"
+	. "github.com/liamawhite/parse/test"
+)
+
+// Run go test -update to write the golden files after an intended change.
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	RunGolden(t, GoldenTest[Block]{Dir: "testdata", Parser: Frontmatter, Format: formatBlock})
+}
+
+// formatBlock writes the span of the block followed by its nodes one per line along with their spans.
+func formatBlock(block Block) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "frontmatter %d-%d, content %d-%d\n", block.Span.Start, block.Span.End, block.Content.Start, block.Content.End)
+	var write func(node Node, depth int, prefix string)
+	write = func(node Node, depth int, prefix string) {
+		fmt.Fprintf(&b, "%s%s%s %s %d-%d", strings.Repeat("  ", depth), prefix, node.Style, node.Kind, node.Span.Start, node.Span.End)
+		if node.Kind == ScalarKind {
+			fmt.Fprintf(&b, " %q", node.Text)
+		}
+		b.WriteString("\n")
+		for _, item := range node.Items {
+			write(item, depth+1, "")
+		}
+		for _, entry := range node.Entries {
+			write(entry.Value, depth+1, fmt.Sprintf("%q %d-%d: ", entry.Key.Text, entry.Key.Span.Start, entry.Key.Span.End))
+		}
+	}
+	write(block.Root, 0, "")
+	return b.String()
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Block is the frontmatter at the start of a document, see Frontmatter.
+type Block struct {
+	// Text of the YAML between the delimiters, including its final line break.
+	Text string
+	// Span of the block from the opening delimiter up to and including the line break after the closing delimiter,
+	// so the document's body starts at Span.End.
+	Span Range
+	// Content is the span of Text in the input.
+	Content Range
+	// Root is the parsed YAML, which is always a map. It is left empty by Extract.
+	Root Node
+}
+
+// Kind is the type of a YAML node.
+type Kind int
+
+const (
+	ScalarKind Kind = iota
+	ListKind
+	MapKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case ScalarKind:
+		return "scalar"
+	case ListKind:
+		return "list"
+	case MapKind:
+		return "map"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Style is how a node was written, which an editor can keep when rewriting its value.
+type Style int
+
+const (
+	// PlainStyle is an unquoted scalar, or a list or map written one item per line.
+	PlainStyle Style = iota
+	SingleQuotedStyle
+	DoubleQuotedStyle
+	// LiteralStyle is a block scalar introduced by |, which keeps its line breaks.
+	LiteralStyle
+	// FoldedStyle is a block scalar introduced by >, which folds its line breaks into spaces.
+	FoldedStyle
+	// FlowStyle is a list or map written in brackets, e.g. [a, b].
+	FlowStyle
+)
+
+func (s Style) String() string {
+	switch s {
+	case PlainStyle:
+		return "plain"
+	case SingleQuotedStyle:
+		return "single quoted"
+	case DoubleQuotedStyle:
+		return "double quoted"
+	case LiteralStyle:
+		return "literal"
+	case FoldedStyle:
+		return "folded"
+	case FlowStyle:
+		return "flow"
+	}
+	return fmt.Sprintf("Style(%d)", int(s))
+}
+
+// Node is a YAML value along with the part of the input it was parsed from, see YAML.
+type Node struct {
+	Kind  Kind
+	Style Style
+	// Span of the value in the input, excluding any comment or line break after it. The span of a quoted scalar includes
+	// its quotes and that of a block scalar starts at its | or > header. A missing value, e.g. in "due:", is an empty
+	// plain scalar with an empty span just after the colon, which is where an editor would insert one.
+	Span Range
+	// Text is the value of a scalar with its quotes removed, escapes decoded and, for a block scalar, indentation removed.
+	Text string
+	// Items of a list.
+	Items []Node
+	// Entries of a map in the order they were written. Keys are unique.
+	Entries []Entry
+}
+
+// Entry is a key and value within a map.
+type Entry struct {
+	Key   Node
+	Value Node
+	// Span of the entry from the start of its key to the end of its value, excluding any comment or line break after it.
+	Span Range
+}
+
+// Entry returns the entry of a map with the key.
+func (n Node) Entry(key string) (Entry, bool) {
+	for _, entry := range n.Entries {
+		if entry.Key.Text == key {
+			return entry, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Lookup returns the value at the path of keys through nested maps, e.g. Lookup("author", "name").
+func (n Node) Lookup(path ...string) (Node, bool) {
+	for _, key := range path {
+		entry, ok := n.Entry(key)
+		if !ok {
+			return Node{}, false
+		}
+		n = entry.Value
+	}
+	return n, true
+}
+
+// Value returns the node as a Go value: nil, bool, int, float64, string, []any or map[string]any.
+// Quoted and block scalars are strings while plain scalars are resolved as in the YAML 1.2 core schema,
+// e.g. ~ and an empty value are nil, true is a bool, 0x1F is an int and .inf is a float64.
+// Anything else, including dates, is a string.
+func (n Node) Value() any {
+	switch n.Kind {
+	case ListKind:
+		values := make([]any, len(n.Items))
+		for i, item := range n.Items {
+			values[i] = item.Value()
+		}
+		return values
+	case MapKind:
+		values := make(map[string]any, len(n.Entries))
+		for _, entry := range n.Entries {
+			values[entry.Key.Text] = entry.Value.Value()
+		}
+		return values
+	}
+	if n.Style != PlainStyle {
+		return n.Text
+	}
+	return resolve(n.Text)
+}
+
+var (
+	integer = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	float   = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+	octal   = regexp.MustCompile(`^0o[0-7]+$`)
+	hex     = regexp.MustCompile(`^0x[0-9a-fA-F]+$`)
+)
+
+// resolve returns the value of a plain scalar as in the YAML 1.2 core schema.
+func resolve(text string) any {
+	switch text {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+	switch {
+	case integer.MatchString(text):
+		if i, err := strconv.ParseInt(text, 10, 0); err == nil {
+			return int(i)
+		}
+		// Too large for an int.
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	case octal.MatchString(text):
+		if i, err := strconv.ParseInt(text[2:], 8, 0); err == nil {
+			return int(i)
+		}
+	case hex.MatchString(text):
+		if i, err := strconv.ParseInt(text[2:], 16, 0); err == nil {
+			return int(i)
+		}
+	case float.MatchString(text):
+		if f, err := strconv.ParseFloat(text, 64); err == nil {
+			return f
+		}
+	}
+	return text
+}
+
+// SyntaxError is returned when the frontmatter is not valid YAML, or uses YAML the parser does not support.
+type SyntaxError struct {
+	Position int
+	// Line and Column of the error, both counted from 1. Columns are counted in runes.
+	Line   int
+	Column int
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("invalid YAML at line %d, column %d: %s", e.Line, e.Column, e.Reason)
+}
@@ -0,0 +1,389 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// scalar parses a plain or quoted scalar at the start of the rest of the line.
+// Within a flow collection a plain scalar also ends at a comma or bracket.
+func scalar(in Input, l string, flow bool) (Node, error) {
+	start := in.Checkpoint()
+	switch {
+	case l == "":
+		return Node{}, &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "value")}
+	case l[0] == '"' || l[0] == '\'':
+		n, ok := scanQuoted(l)
+		if !ok {
+			return Node{}, &SyntaxError{Position: start.Position(), Reason: "quoted scalar must be closed on the same line"}
+		}
+		node := Node{Style: SingleQuotedStyle, Text: strings.ReplaceAll(l[1:n-1], "''", "'")}
+		if l[0] == '"' {
+			text, err := unescape(l[1:n-1], start.Position()+1)
+			if err != nil {
+				return Node{}, err
+			}
+			node = Node{Style: DoubleQuotedStyle, Text: text}
+		}
+		in.Take(n)
+		node.Span = RangeFrom(start, in)
+		return node, nil
+	case strings.IndexByte("&*!", l[0]) >= 0:
+		return Node{}, &SyntaxError{Position: start.Position(), Reason: "anchors, aliases and tags are not supported"}
+	}
+	n := scanPlain(l, flow)
+	if n == 0 {
+		return Node{}, &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "value")}
+	}
+	in.Take(n)
+	return Node{Text: l[:n], Span: RangeFrom(start, in)}, nil
+}
+
+// Characters that cannot start a plain scalar, apart from - ? and : when they are followed by something other than a space.
+const indicators = "-?:,[]{}#&*!|>'\"%@`"
+
+const flowIndicators = ",[]{}"
+
+// scanPlain returns the length of the plain scalar at the start of the line, excluding any trailing whitespace, or 0 if
+// there isn't one. It ends at a colon followed by a space, as in a key, or at a space followed by a comment.
+func scanPlain(l string, flow bool) int {
+	if l == "" {
+		return 0
+	}
+	if strings.IndexByte(indicators, l[0]) >= 0 {
+		if strings.IndexByte("-?:", l[0]) < 0 || len(l) == 1 || isBlank(l[1]) || flow && strings.IndexByte(flowIndicators, l[1]) >= 0 {
+			return 0
+		}
+	}
+	end := 0
+	for i := 0; i < len(l); i++ {
+		c := l[i]
+		switch {
+		case c == ':' && (i+1 == len(l) || isBlank(l[i+1]) || flow && strings.IndexByte(flowIndicators, l[i+1]) >= 0):
+			return end
+		case c == '#' && isBlank(l[i-1]):
+			return end
+		case flow && strings.IndexByte(flowIndicators, c) >= 0:
+			return end
+		case !isBlank(c):
+			// Every byte of a multibyte rune is not blank, so the scalar never ends part way through one.
+			end = i + 1
+		}
+	}
+	return end
+}
+
+// scanQuoted returns the length of the quoted scalar at the start of the line including its quotes, reporting
+// whether it is closed. Single quotes are escaped by doubling them and double quotes with a backslash.
+func scanQuoted(l string) (int, bool) {
+	quote := l[0]
+	for i := 1; i < len(l); i++ {
+		switch {
+		case quote == '"' && l[i] == '\\':
+			i++
+		case l[i] == quote && quote == '\'' && i+1 < len(l) && l[i+1] == '\'':
+			i++
+		case l[i] == quote:
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// Runes of the single character escapes of a double quoted scalar, e.g. \n.
+var escapes = map[byte]rune{
+	'0': 0, 'a': '\a', 'b': '\b', 't': '\t', '\t': '\t', 'n': '\n', 'v': '\v', 'f': '\f', 'r': '\r', 'e': 0x1b,
+	' ': ' ', '"': '"', '/': '/', '\\': '\\', 'N': 0x85, '_': 0xa0, 'L': 0x2028, 'P': 0x2029,
+}
+
+// Number of hex digits that follow each of the escapes of a character code, e.g. é.
+var hexEscapes = map[byte]int{'x': 2, 'u': 4, 'U': 8}
+
+// unescape decodes the escapes in the text between the quotes of a double quoted scalar.
+// Offset is the position of the text in the input.
+func unescape(raw string, offset int) (string, error) {
+	if !strings.Contains(raw, `\`) {
+		return raw, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '\\' {
+			b.WriteByte(raw[i])
+			continue
+		}
+		// scanQuoted only closes a scalar after a complete escape.
+		c := raw[i+1]
+		if r, ok := escapes[c]; ok {
+			b.WriteRune(r)
+			i++
+			continue
+		}
+		n, ok := hexEscapes[c]
+		if !ok {
+			r, _ := utf8.DecodeRuneInString(raw[i+1:])
+			return "", &SyntaxError{Position: offset + i, Reason: fmt.Sprintf("invalid escape \\%c", r)}
+		}
+		if i+2+n > len(raw) {
+			return "", &SyntaxError{Position: offset + i, Reason: fmt.Sprintf("\\%c must be followed by %d hex digits", c, n)}
+		}
+		code, err := strconv.ParseUint(raw[i+2:i+2+n], 16, 32)
+		if err != nil {
+			return "", &SyntaxError{Position: offset + i, Reason: fmt.Sprintf("\\%c must be followed by %d hex digits", c, n)}
+		}
+		b.WriteRune(rune(code))
+		i += 1 + n
+	}
+	return b.String(), nil
+}
+
+// flow parses a flow list or map, e.g. [a, b] or {a: 1}, which may span several lines and end with a comma.
+func flow(in Input) (Node, error) {
+	start := in.Checkpoint()
+	node, closing := Node{Kind: ListKind, Style: FlowStyle}, "]"
+	if open, _ := in.Peek(1); open == "{" {
+		node.Kind, closing = MapKind, "}"
+	}
+	in.Take(1)
+	for {
+		if err := flowSpace(in, start, node.Kind); err != nil {
+			return Node{}, err
+		}
+		if next, _ := in.Peek(1); next == closing {
+			break
+		}
+		if node.Kind == ListKind {
+			item, err := flowNode(in)
+			if err != nil {
+				return Node{}, err
+			}
+			node.Items = append(node.Items, item)
+		} else {
+			entry, err := flowEntry(in, start)
+			if err != nil {
+				return Node{}, err
+			}
+			if _, ok := node.Entry(entry.Key.Text); ok {
+				return Node{}, &SyntaxError{Position: entry.Key.Span.Start, Reason: fmt.Sprintf("duplicate key %q", entry.Key.Text)}
+			}
+			node.Entries = append(node.Entries, entry)
+		}
+		if err := flowSpace(in, start, node.Kind); err != nil {
+			return Node{}, err
+		}
+		next, _ := in.Peek(1)
+		if next == closing {
+			break
+		}
+		if next != "," {
+			return Node{}, &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, fmt.Sprintf("',' or '%s'", closing))}
+		}
+		in.Take(1)
+	}
+	in.Take(1)
+	node.Span = RangeFrom(start, in)
+	return node, nil
+}
+
+// flowNode parses a scalar or nested flow collection within a flow collection.
+func flowNode(in Input) (Node, error) {
+	l, err := line(in)
+	if err != nil {
+		return Node{}, err
+	}
+	if strings.HasPrefix(l, "[") || strings.HasPrefix(l, "{") {
+		return flow(in)
+	}
+	return scalar(in, l, true)
+}
+
+// flowEntry parses a key and value within the flow map that starts at the checkpoint.
+// A missing value, e.g. in {a: }, is an empty scalar.
+func flowEntry(in Input, start Checkpoint) (Entry, error) {
+	l, err := line(in)
+	if err != nil {
+		return Entry{}, err
+	}
+	key, err := scalar(in, l, true)
+	if err != nil {
+		return Entry{}, err
+	}
+	OptionalInlineWhitespace(in)
+	if next, _ := in.Peek(1); next != ":" {
+		return Entry{}, &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "':'")}
+	}
+	in.Take(1)
+	if err := flowSpace(in, start, MapKind); err != nil {
+		return Entry{}, err
+	}
+	var v Node
+	if next, _ := in.Peek(1); next == "," || next == "}" {
+		at := in.Checkpoint().Position()
+		v = Node{Span: Range{Start: at, End: at}}
+	} else if v, err = flowNode(in); err != nil {
+		return Entry{}, err
+	}
+	return Entry{Key: key, Value: v, Span: Range{Start: key.Span.Start, End: v.Span.End}}, nil
+}
+
+// flowSpace skips whitespace, line breaks and comments within the flow collection that starts at the checkpoint,
+// returning an error if the document ends first.
+func flowSpace(in Input, start Checkpoint, kind Kind) error {
+	for lineStart := false; ; lineStart = true {
+		l, err := line(in)
+		if err != nil {
+			return err
+		}
+		if lineStart && strings.TrimRight(l, " \t") == "---" {
+			break
+		}
+		rest := strings.TrimLeft(l, " \t")
+		if rest != "" && rest[0] != '#' {
+			in.Take(len(l) - len(rest))
+			return nil
+		}
+		if _, ok := in.Peek(len(l) + 1); !ok {
+			break
+		}
+		if err := skipLine(in, l); err != nil {
+			return err
+		}
+	}
+	return &SyntaxError{Position: start.Position(), Reason: fmt.Sprintf("flow %s is not closed", kind)}
+}
+
+// blockScalar parses a literal or folded block scalar, whose header is at the start of the rest of the line and whose
+// content is on the lines that follow, indented further than its parent.
+func blockScalar(in Input, l string, parent int) (Node, error) {
+	start := in.Checkpoint()
+	node := Node{Style: LiteralStyle}
+	if l[0] == '>' {
+		node.Style = FoldedStyle
+	}
+	// The chomping and indentation indicators may be written in either order.
+	chomp, indent := byte(0), -1
+	i := 1
+	for ; i < len(l) && i < 3; i++ {
+		c := l[i]
+		if (c == '-' || c == '+') && chomp == 0 {
+			chomp = c
+		} else if c >= '1' && c <= '9' && indent < 0 {
+			indent = max(parent, 0) + int(c-'0')
+		} else {
+			break
+		}
+	}
+	in.Take(i)
+	end := in.Checkpoint().Position()
+	if err := lineEnd(in); err != nil {
+		return Node{}, err
+	}
+
+	// Blank lines are empty strings, as no line of content is empty once its indentation is removed.
+	var lines []string
+	for {
+		if _, ok := in.Peek(1); !ok {
+			break
+		}
+		l, err := line(in)
+		if err != nil {
+			return Node{}, err
+		}
+		if strings.TrimRight(l, " \t") == "---" {
+			break
+		}
+		spaces := len(l) - len(strings.TrimLeft(l, " "))
+		if spaces == len(l) {
+			if indent >= 0 && spaces > indent {
+				lines = append(lines, l[indent:])
+			} else {
+				lines = append(lines, "")
+			}
+			if err := skipLine(in, l); err != nil {
+				return Node{}, err
+			}
+			continue
+		}
+		if indent < 0 {
+			if spaces <= parent {
+				break
+			}
+			indent = spaces
+		}
+		if spaces < indent {
+			break
+		}
+		lines = append(lines, l[indent:])
+		in.Take(len(l))
+		end = in.Checkpoint().Position()
+		if _, _, err := NewLine(in); err != nil {
+			return Node{}, err
+		}
+	}
+
+	content := len(lines)
+	for content > 0 && lines[content-1] == "" {
+		content--
+	}
+	if node.Style == LiteralStyle {
+		node.Text = strings.Join(lines[:content], "\n")
+	} else {
+		node.Text = fold(lines[:content])
+	}
+	switch {
+	case chomp == '+':
+		node.Text += strings.Repeat("\n", len(lines)-content)
+		if content > 0 {
+			node.Text += "\n"
+		}
+	case chomp == 0 && content > 0:
+		node.Text += "\n"
+	}
+	node.Span = Range{Start: start.Position(), End: end}
+	return node, nil
+}
+
+// fold joins the lines of a folded block scalar, which ends with a line of content. Each line break between two lines
+// of content becomes a space unless either is indented further than the rest, while a run of blank lines between them
+// becomes one less line break.
+func fold(lines []string) string {
+	var b strings.Builder
+	blank, previous := 0, ""
+	for _, l := range lines {
+		if l == "" {
+			blank++
+			continue
+		}
+		switch {
+		case previous == "":
+			b.WriteString(strings.Repeat("\n", blank))
+		case !isBlank(previous[0]) && !isBlank(l[0]):
+			if blank == 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(strings.Repeat("\n", blank))
+		default:
+			b.WriteString(strings.Repeat("\n", blank+1))
+		}
+		b.WriteString(l)
+		blank, previous = 0, l
+	}
+	return b.String()
+}
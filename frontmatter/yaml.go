@@ -0,0 +1,428 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package frontmatter
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// YAML parses the subset of YAML used in frontmatter, returning its syntax tree along with the span of each node.
+// It consumes the input up to its end or a line holding only ---, which closes frontmatter. It always matches,
+// returning an empty map if there is nothing but blank lines and comments, unless the YAML is invalid or uses
+// YAML that is not supported, which is a *SyntaxError.
+//
+// The subset is made up of:
+//   - maps of one key per line, e.g. title: Notes, and lists of one item per line, e.g. - home
+//   - plain, single quoted and double quoted scalars, each written on a single line
+//   - literal (|) and folded (>) block scalars, with an optional chomping indicator (- or +) and indentation indicator
+//   - flow lists and maps, e.g. [home, work] and {x: 1, y: 2}, which may span several lines
+//   - comments
+//
+// Anchors, aliases, tags, complex keys, directives and multiple documents are not supported,
+// nor are plain and quoted scalars that continue onto the next line.
+var YAML Parser[Node] = WithGenerator(func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	node, err := document(in)
+	if err != nil {
+		in.Restore(start)
+		locateError(in, start, err)
+		return Node{}, false, err
+	}
+	return node, true, nil
+}, generateDocument)
+
+// document parses the node at the root of a document, up to the end of the input or a --- line.
+func document(in Input) (Node, error) {
+	indent, err := next(in)
+	if err != nil {
+		return Node{}, err
+	}
+	if indent < 0 {
+		at := in.Checkpoint().Position()
+		return Node{Kind: MapKind, Span: Range{Start: at, End: at}}, nil
+	}
+	root, err := block(in, indent, -1)
+	if err != nil {
+		return Node{}, err
+	}
+	after, err := next(in)
+	if err != nil {
+		return Node{}, err
+	}
+	if after >= 0 {
+		return Node{}, misplaced(in, after, indent, "end of document")
+	}
+	return root, nil
+}
+
+// restOfLine parses the rest of the current line, excluding its line break.
+var restOfLine = StringWhileNotEOFOr(NewLine)
+
+// line returns the rest of the current line without consuming it.
+func line(in Input) (string, error) {
+	start := in.Checkpoint()
+	s, _, err := restOfLine(in)
+	in.Restore(start)
+	return s, err
+}
+
+// skipLine consumes the rest of the current line along with its line break, if there is one.
+func skipLine(in Input, l string) error {
+	in.Take(len(l))
+	_, _, err := NewLine(in)
+	return err
+}
+
+// next skips blank lines and lines holding only a comment, stopping at the start of the next line with a node on it.
+// It returns the indentation of that line, or -1 at the end of the document.
+func next(in Input) (int, error) {
+	for {
+		if _, ok := in.Peek(1); !ok {
+			return -1, nil
+		}
+		l, err := line(in)
+		if err != nil {
+			return 0, err
+		}
+		if strings.TrimRight(l, " \t") == "---" {
+			return -1, nil
+		}
+		trimmed := strings.TrimLeft(l, " ")
+		indent := len(l) - len(trimmed)
+		if rest := strings.TrimLeft(trimmed, " \t"); rest != "" && rest[0] != '#' {
+			if trimmed[0] == '\t' {
+				return 0, &SyntaxError{Position: in.Checkpoint().Position() + indent, Reason: "tabs are not allowed in indentation"}
+			}
+			return indent, nil
+		}
+		if err := skipLine(in, l); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// misplaced returns the error for a line whose indentation does not fit where it is, the input being at its start.
+func misplaced(in Input, indent, column int, expected string) error {
+	in.Take(indent)
+	if indent > column {
+		return &SyntaxError{Position: in.Checkpoint().Position(), Reason: "unexpected indentation"}
+	}
+	return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, expected)}
+}
+
+// lineEnd parses the end of a line after a node, which may be followed by whitespace and a comment.
+func lineEnd(in Input) error {
+	l, err := line(in)
+	if err != nil {
+		return err
+	}
+	rest := strings.TrimLeft(l, " \t")
+	if rest != "" && (rest[0] != '#' || len(rest) == len(l)) {
+		in.Take(len(l) - len(rest))
+		if rest[0] == ':' {
+			return &SyntaxError{Position: in.Checkpoint().Position(), Reason: "unexpected ':', quote values that contain ': '"}
+		}
+		return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "line break")}
+	}
+	return skipLine(in, l)
+}
+
+// block parses a list, map or scalar at the start of a line with the indentation, within a node at the parent's.
+func block(in Input, indent, parent int) (Node, error) {
+	in.Take(indent)
+	return blockAt(in, indent, parent)
+}
+
+// blockAt parses a list, map or scalar starting at the column of the current line.
+func blockAt(in Input, column, parent int) (Node, error) {
+	l, err := line(in)
+	if err != nil {
+		return Node{}, err
+	}
+	switch {
+	case isItem(l):
+		return list(in, column)
+	case isKey(l):
+		return mapping(in, column)
+	}
+	return inline(in, parent)
+}
+
+// isItem reports whether the line starts with a list item.
+func isItem(l string) bool {
+	return strings.HasPrefix(l, "-") && (len(l) == 1 || isBlank(l[1]))
+}
+
+// isKey reports whether the line starts with a key followed by a colon.
+func isKey(l string) bool {
+	n := scanPlain(l, false)
+	if l != "" && (l[0] == '"' || l[0] == '\'') {
+		n, _ = scanQuoted(l)
+	}
+	if n == 0 {
+		return false
+	}
+	rest := strings.TrimLeft(l[n:], " \t")
+	return strings.HasPrefix(rest, ":") && (len(rest) == 1 || isBlank(rest[1]))
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// list parses the items of a list starting at the column, each of which starts with a dash.
+func list(in Input, column int) (Node, error) {
+	start := in.Checkpoint()
+	node := Node{Kind: ListKind}
+	for {
+		in.Take(1)
+		l, err := line(in)
+		if err != nil {
+			return Node{}, err
+		}
+		var item Node
+		if rest := strings.TrimLeft(l, " \t"); rest != "" && rest[0] != '#' {
+			in.Take(len(l) - len(rest))
+			// A list or map may start on the same line as the dash, e.g. - name: value, its column being after the dash.
+			item, err = blockAt(in, column+1+len(l)-len(rest), column)
+		} else {
+			item, err = below(in, column, false)
+		}
+		if err != nil {
+			return Node{}, err
+		}
+		node.Items = append(node.Items, item)
+		node.Span = Range{Start: start.Position(), End: item.Span.End}
+
+		indent, err := next(in)
+		if err != nil {
+			return Node{}, err
+		}
+		if indent < column {
+			return node, nil
+		}
+		if l, err = line(in); err != nil {
+			return Node{}, err
+		}
+		if indent == column && !isItem(l[indent:]) {
+			// A key of the map the list is the value of.
+			return node, nil
+		}
+		if indent > column {
+			return Node{}, misplaced(in, indent, column, "list item")
+		}
+		in.Take(indent)
+	}
+}
+
+// mapping parses the entries of a map starting at the column, each of which starts with a key.
+func mapping(in Input, column int) (Node, error) {
+	start := in.Checkpoint()
+	node := Node{Kind: MapKind}
+	for {
+		entry, err := entry(in, column)
+		if err != nil {
+			return Node{}, err
+		}
+		if _, ok := node.Entry(entry.Key.Text); ok {
+			return Node{}, &SyntaxError{Position: entry.Key.Span.Start, Reason: fmt.Sprintf("duplicate key %q", entry.Key.Text)}
+		}
+		node.Entries = append(node.Entries, entry)
+		node.Span = Range{Start: start.Position(), End: entry.Span.End}
+
+		indent, err := next(in)
+		if err != nil {
+			return Node{}, err
+		}
+		if indent < column {
+			return node, nil
+		}
+		l, err := line(in)
+		if err != nil {
+			return Node{}, err
+		}
+		if indent != column || !isKey(l[indent:]) {
+			return Node{}, misplaced(in, indent, column, "key")
+		}
+		in.Take(indent)
+	}
+}
+
+// entry parses a key and its value within a map at the column.
+func entry(in Input, column int) (Entry, error) {
+	l, err := line(in)
+	if err != nil {
+		return Entry{}, err
+	}
+	key, err := scalar(in, l, false)
+	if err != nil {
+		return Entry{}, err
+	}
+	OptionalInlineWhitespace(in)
+	in.Take(1)
+	if l, err = line(in); err != nil {
+		return Entry{}, err
+	}
+	var v Node
+	if rest := strings.TrimLeft(l, " \t"); rest != "" && rest[0] != '#' {
+		in.Take(len(l) - len(rest))
+		v, err = inline(in, column)
+	} else {
+		v, err = below(in, column, true)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	return Entry{Key: key, Value: v, Span: Range{Start: key.Span.Start, End: v.Span.End}}, nil
+}
+
+// below parses a value that is not on the same line as its key or dash, but indented on the lines that follow.
+// The value of a key may also be a list that is not indented, with its dashes lined up with the key.
+// If there is no value an empty scalar is returned, at the end of the line the value was expected on.
+func below(in Input, parent int, key bool) (Node, error) {
+	at := in.Checkpoint().Position()
+	if err := lineEnd(in); err != nil {
+		return Node{}, err
+	}
+	indent, err := next(in)
+	if err != nil {
+		return Node{}, err
+	}
+	if indent > parent {
+		return block(in, indent, parent)
+	}
+	if indent == parent && key {
+		l, err := line(in)
+		if err != nil {
+			return Node{}, err
+		}
+		if isItem(l[indent:]) {
+			in.Take(indent)
+			return list(in, indent)
+		}
+	}
+	return Node{Span: Range{Start: at, End: at}}, nil
+}
+
+// inline parses a scalar or flow collection on the current line, or a block scalar starting on it.
+func inline(in Input, parent int) (Node, error) {
+	l, err := line(in)
+	if err != nil {
+		return Node{}, err
+	}
+	var node Node
+	switch l[0] {
+	case '|', '>':
+		return blockScalar(in, l, parent)
+	case '[', '{':
+		node, err = flow(in)
+	default:
+		node, err = scalar(in, l, false)
+	}
+	if err != nil {
+		return Node{}, err
+	}
+	return node, lineEnd(in)
+}
+
+// Keys and plain scalars a generated document is made up of.
+var (
+	generateKeys  = []string{"title", "date", "tags", "author", "draft", "weight", "summary", "aliases", "due", "日本", "café"}
+	generateWords = []string{"notes", "water the plants", "42", "-1.5", "0x1F", "true", "null", "~", "café", "日本語", "a-b_c", "1 Feb 2024", "http://example.com/a?b=c#d"}
+	// Parts of the text of quoted scalars, including escapes.
+	generateDoubleQuoted = []string{"a", "Z", " ", "é", "日", "😀", "#", ": ", "'", `\"`, `\\`, `\n`, `\t`, `\u00e9`, `\x41`}
+	generateSingleQuoted = []string{"a", "Z", " ", "é", "日", "😀", "#", ": ", "''", `"`, `\`}
+)
+
+func generateDocument(r *rand.Rand) string {
+	var b strings.Builder
+	generateMap(r, &b, 0, 0)
+	return b.String()
+}
+
+// generateMap writes a map with its keys at the indentation, which is nested within depth others.
+func generateMap(r *rand.Rand, b *strings.Builder, indent, depth int) {
+	keys := append([]string(nil), generateKeys...)
+	r.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	for _, key := range keys[:1+r.IntN(4)] {
+		b.WriteString(strings.Repeat(" ", indent) + key + ":")
+		generateValue(r, b, indent, depth)
+	}
+}
+
+// generateValue writes the value following a key or dash at the indentation, starting with the space or line break
+// that separates them.
+func generateValue(r *rand.Rand, b *strings.Builder, indent, depth int) {
+	choice := r.IntN(8)
+	if depth >= 2 {
+		choice = r.IntN(6)
+	}
+	switch choice {
+	case 0:
+		b.WriteString(" " + generateWords[r.IntN(len(generateWords))])
+		if r.IntN(4) == 0 {
+			b.WriteString(" # comment")
+		}
+		b.WriteString("\n")
+	case 1:
+		b.WriteString(` "` + generateParts(r, generateDoubleQuoted) + "\"\n")
+	case 2:
+		b.WriteString(" '" + generateParts(r, generateSingleQuoted) + "'\n")
+	case 3:
+		words := make([]string, r.IntN(4))
+		for i := range words {
+			// Leave out the URL, as unlike YAML 1.2 some parsers don't allow a colon in a plain scalar within brackets.
+			words[i] = generateWords[r.IntN(len(generateWords)-1)]
+		}
+		b.WriteString(" [" + strings.Join(words, ", ") + "]\n")
+	case 4:
+		b.WriteString(" " + []string{"|", ">"}[r.IntN(2)] + []string{"", "-", "+"}[r.IntN(3)] + "\n")
+		// The first line of content sets the indentation of the rest, so it is never indented further.
+		b.WriteString(strings.Repeat(" ", indent+2) + generateWords[r.IntN(len(generateWords))] + "\n")
+		for n := r.IntN(4); n > 0; n-- {
+			switch r.IntN(4) {
+			case 0:
+				b.WriteString("\n")
+			case 1:
+				b.WriteString(strings.Repeat(" ", indent+4) + generateWords[r.IntN(len(generateWords))] + "\n")
+			default:
+				b.WriteString(strings.Repeat(" ", indent+2) + generateWords[r.IntN(len(generateWords))] + "\n")
+			}
+		}
+	case 5:
+		b.WriteString("\n")
+	case 6:
+		b.WriteString("\n")
+		generateMap(r, b, indent+2, depth+1)
+	case 7:
+		b.WriteString("\n")
+		for n := 1 + r.IntN(3); n > 0; n-- {
+			b.WriteString(strings.Repeat(" ", indent+2) + "-")
+			generateValue(r, b, indent+2, depth+1)
+		}
+	}
+}
+
+func generateParts(r *rand.Rand, parts []string) string {
+	var s strings.Builder
+	for n := r.IntN(6); n > 0; n-- {
+		s.WriteString(parts[r.IntN(len(parts))])
+	}
+	return s.String()
+}
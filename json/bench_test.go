@@ -0,0 +1,58 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json_test
+
+import (
+	stdjson "encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/json"
+	. "github.com/liamawhite/parse/test"
+)
+
+// A list of tasks like those in testdata, around 20 kilobytes.
+var benchDocument = "[" + strings.Repeat(benchTasks()+",", 99) + benchTasks() + "]"
+
+func benchTasks() string {
+	s, err := os.ReadFile("testdata/tasks.json")
+	if err != nil {
+		panic(err)
+	}
+	return string(s)
+}
+
+func BenchmarkValue(b *testing.B) {
+	var expected any
+	if err := stdjson.Unmarshal([]byte(benchDocument), &expected); err != nil {
+		b.Fatal(err)
+	}
+	BenchTests(b, []ParserTest[any]{
+		{Name: "Document", Input: benchDocument, Parser: Value, ExpectedMatch: expected, ExpectedOK: true},
+	})
+}
+
+// BenchmarkEncodingJSON is the same document decoded by encoding/json for comparison.
+func BenchmarkEncodingJSON(b *testing.B) {
+	data := []byte(benchDocument)
+	b.SetBytes(int64(len(data)))
+	for range b.N {
+		var v any
+		if err := stdjson.Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
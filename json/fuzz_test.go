@@ -0,0 +1,58 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json_test
+
+import (
+	stdjson "encoding/json"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/json"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var fuzzSeeds = []string{`{"a": [1, -2.5e3, true, null, "x\né"]}`, `[1,]`, `{"a" 1}`, `"😀"`, `01`, `1e400`, `[[[]]]`}
+
+func FuzzValue(f *testing.F) {
+	FuzzParser(f, Value, fuzzSeeds...)
+}
+
+// FuzzEncodingJSON checks that exactly the same texts are valid as for encoding/json and that they decode to the same value.
+func FuzzEncodingJSON(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		// encoding/json accepts invalid UTF-8 within strings, replacing it, where RFC 8259 requires UTF-8.
+		if !utf8.ValidString(s) {
+			t.Skip()
+		}
+		in := core.NewInput(s)
+		node, ok, err := ValueNode(in)
+		_, more := in.Peek(1)
+		valid := ok && err == nil && !more
+		assert.Equal(t, stdjson.Valid([]byte(s)), valid, "err: %v", err)
+
+		var expected any
+		if !valid || stdjson.Unmarshal([]byte(s), &expected) != nil {
+			return
+		}
+		actual, err := node.Value()
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	})
+}
@@ -0,0 +1,58 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json_test
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/json"
+	. "github.com/liamawhite/parse/test"
+)
+
+// Run go test -update to write the golden files after an intended change.
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	RunGolden(t, GoldenTest[Node]{Dir: "testdata", Parser: ValueNode, Format: formatTree})
+}
+
+// formatTree writes a node and its children one per line along with their spans.
+func formatTree(node Node) string {
+	var b strings.Builder
+	var write func(node Node, depth int, prefix string)
+	write = func(node Node, depth int, prefix string) {
+		fmt.Fprintf(&b, "%s%s%s %d-%d", strings.Repeat("  ", depth), prefix, node.Kind, node.Span.Start, node.Span.End)
+		switch node.Kind {
+		case BoolKind:
+			fmt.Fprintf(&b, " %v", node.Bool)
+		case NumberKind:
+			fmt.Fprintf(&b, " %s", node.Text)
+		case StringKind:
+			fmt.Fprintf(&b, " %q", node.Text)
+		}
+		b.WriteString("\n")
+		for _, element := range node.Elements {
+			write(element, depth+1, "")
+		}
+		for _, property := range node.Properties {
+			write(property.Value, depth+1, fmt.Sprintf("%q %d-%d: ", property.Name.Text, property.Name.Span.Start, property.Name.Span.End))
+		}
+	}
+	write(node, 0, "")
+	return b.String()
+}
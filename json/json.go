@@ -0,0 +1,309 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Value parses a JSON text as defined by RFC 8259, a value with optional whitespace either side, returning the value
+// as encoding/json would decode it into an any, see Node.Value. Input following the text is not consumed.
+var Value = func(in Input) (any, bool, error) {
+	start := in.Checkpoint()
+	node, ok, err := ValueNode(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	value, err := node.Value()
+	if err != nil {
+		in.Restore(start)
+		locateError(in, start, err)
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// ValueNode parses a JSON text like Value, returning its syntax tree along with the span of each value.
+// It does not match unless the input starts with a value, after which invalid JSON is a *SyntaxError.
+// Lines and columns are counted from where the parser started, usually the start of the document.
+// Nesting is not limited, so use Parse with Limits.MaxDepth for untrusted input.
+var ValueNode = func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	node, ok, err := element(in)
+	if err != nil {
+		locateError(in, start, err)
+	}
+	return node, ok, err
+}
+
+// value parses any JSON value. It is assigned in init as arrays and objects are made up of values.
+var value Parser[Node]
+
+func init() {
+	value = Any(object, array, stringValue, number, literal)
+}
+
+// element is a value with optional whitespace either side, which makes up arrays, objects and the JSON text itself.
+func element(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	whitespace(in)
+	node, ok, err := value(in)
+	if err != nil || !ok {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	whitespace(in)
+	return node, true, nil
+}
+
+// Unlike core.Whitespace only spaces, tabs, line feeds and carriage returns are allowed.
+var whitespace = StringFrom(SkipZeroOrMore(RuneIn(" \t\n\r")))
+
+var (
+	openObject  = Rune('{')
+	closeObject = Rune('}')
+	openArray   = Rune('[')
+	closeArray  = Rune(']')
+	colon       = Rune(':')
+	comma       = Rune(',')
+)
+
+var object = func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	if _, ok, err := openObject(in); err != nil || !ok {
+		return Node{}, false, err
+	}
+	m, _, err := properties(in)
+	if err != nil {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	whitespace(in)
+	if _, ok, err := closeObject(in); err != nil || !ok {
+		if err == nil {
+			err = unclosed(in, len(m) == 0, "string", "'}'")
+		}
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	return Node{Kind: ObjectKind, Span: RangeFrom(start, in), Properties: m}, true, nil
+}
+
+var properties = SepBy(property, comma)
+
+var property = func(in Input) (Property, bool, error) {
+	start := in.Checkpoint()
+	whitespace(in)
+	name, ok, err := stringValue(in)
+	if err != nil || !ok {
+		in.Restore(start)
+		return Property{}, false, err
+	}
+	whitespace(in)
+	if _, ok, err := colon(in); err != nil || !ok {
+		if err == nil {
+			err = &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "':'")}
+		}
+		in.Restore(start)
+		return Property{}, false, err
+	}
+	v, ok, err := element(in)
+	if err != nil || !ok {
+		if err == nil {
+			whitespace(in)
+			err = &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "value")}
+		}
+		in.Restore(start)
+		return Property{}, false, err
+	}
+	return Property{Name: name, Value: v}, true, nil
+}
+
+var array = func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	if _, ok, err := openArray(in); err != nil || !ok {
+		return Node{}, false, err
+	}
+	e, _, err := elements(in)
+	if err != nil {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	whitespace(in)
+	if _, ok, err := closeArray(in); err != nil || !ok {
+		if err == nil {
+			err = unclosed(in, len(e) == 0, "value", "']'")
+		}
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	return Node{Kind: ArrayKind, Span: RangeFrom(start, in), Elements: e}, true, nil
+}
+
+var elements = SepBy(element, comma)
+
+// unclosed returns the error for an array or object that is not followed by its closing bracket.
+// Either the last item is followed by a comma but no item, or by something other than a comma or the bracket.
+func unclosed(in Input, empty bool, item, bracket string) error {
+	if empty {
+		return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, item+" or "+bracket)}
+	}
+	if _, ok, _ := comma(in); ok {
+		whitespace(in)
+		return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, item)}
+	}
+	return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "',' or "+bracket)}
+}
+
+var quoted = QuotedString('"', '\\')
+
+var stringValue = WithGenerator(func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	raw, ok, err := quoted(in)
+	if err != nil {
+		return Node{}, false, err
+	}
+	if !ok {
+		if next, _ := in.Peek(1); next == `"` {
+			return Node{}, false, &SyntaxError{Position: start.Position(), Reason: "string is not closed"}
+		}
+		return Node{}, false, nil
+	}
+	text, err := unescape(raw, start.Position()+1)
+	if err != nil {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	return Node{Kind: StringKind, Span: RangeFrom(start, in), Text: text}, true, nil
+}, generateString)
+
+var (
+	minus    = Optional(Rune('-'))
+	digits   = StringFrom(SkipOneOrMore(RuneIn("0123456789")))
+	point    = Rune('.')
+	exponent = SequenceOf2(RuneIn("eE"), Optional(RuneIn("+-")))
+)
+
+// number parses a number, which unlike strconv.ParseFloat does not allow a leading plus or zero, hex, infinity or
+// a point without digits either side. A minus sign that isn't followed by a digit does not match.
+var number = WithGenerator(func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	sign, _, err := minus(in)
+	if err != nil {
+		return Node{}, false, err
+	}
+	integer, ok, err := digits(in)
+	if err != nil || !ok {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	if len(integer) > 1 && integer[0] == '0' {
+		in.Restore(start)
+		position := start.Position()
+		if sign.Ok() {
+			position++
+		}
+		return Node{}, false, &SyntaxError{Position: position, Reason: "number must not have leading zeros"}
+	}
+	if _, ok, err := point(in); err != nil || ok {
+		if err := digitsAfter(in, err); err != nil {
+			in.Restore(start)
+			return Node{}, false, err
+		}
+	}
+	if _, ok, err := exponent(in); err != nil || ok {
+		if err := digitsAfter(in, err); err != nil {
+			in.Restore(start)
+			return Node{}, false, err
+		}
+	}
+	text, _ := in.Peek(start.Position() - in.Checkpoint().Position())
+	return Node{Kind: NumberKind, Span: RangeFrom(start, in), Text: text}, true, nil
+}, generateNumber)
+
+// digitsAfter parses the digits that must follow the point or exponent of a number, unless parsing it failed with an error.
+func digitsAfter(in Input, err error) error {
+	if err != nil {
+		return err
+	}
+	_, ok, err := digits(in)
+	if err != nil || ok {
+		return err
+	}
+	return &SyntaxError{Position: in.Checkpoint().Position(), Reason: Unexpected(in, "digit")}
+}
+
+var literals = Any(String("true"), String("false"), String("null"))
+
+var literal = func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	text, ok, err := literals(in)
+	if err != nil || !ok {
+		return Node{}, false, err
+	}
+	node := Node{Kind: BoolKind, Span: RangeFrom(start, in), Bool: text == "true"}
+	if text == "null" {
+		node.Kind = NullKind
+	}
+	return node, true, nil
+}
+
+// locateError fills in the line and column of a syntax error, the input having been restored to the start.
+func locateError(in Input, start Checkpoint, err error) {
+	var syntax *SyntaxError
+	if !errors.As(err, &syntax) {
+		return
+	}
+	text, _ := in.Peek(syntax.Position - start.Position())
+	syntax.Line, syntax.Column = LineColumn(text, len(text))
+}
+
+// Characters and escapes a generated string is made up of.
+var generateStringParts = []string{"a", "Z", "0", " ", "é", "日", "😀", `\"`, `\\`, `\/`, `\b`, `\n`, `\t`, `\u00e9`, `\ud83d\ude00`}
+
+func generateString(r *rand.Rand) string {
+	var s strings.Builder
+	s.WriteByte('"')
+	for n := r.IntN(8); n > 0; n-- {
+		s.WriteString(generateStringParts[r.IntN(len(generateStringParts))])
+	}
+	s.WriteByte('"')
+	return s.String()
+}
+
+func generateNumber(r *rand.Rand) string {
+	var s strings.Builder
+	if r.IntN(4) == 0 {
+		s.WriteByte('-')
+	}
+	if r.IntN(4) == 0 {
+		s.WriteByte('0')
+	} else {
+		s.WriteString(strconv.Itoa(1 + r.IntN(9999)))
+	}
+	if r.IntN(3) == 0 {
+		fmt.Fprintf(&s, ".%d", r.IntN(1000))
+	}
+	if r.IntN(4) == 0 {
+		fmt.Fprintf(&s, "%c%s%d", "eE"[r.IntN(2)], []string{"", "+", "-"}[r.IntN(3)], r.IntN(300))
+	}
+	return s.String()
+}
@@ -0,0 +1,202 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json_test
+
+import (
+	stdjson "encoding/json"
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/json"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValue(t *testing.T) {
+	tests := []ParserTest[any]{
+		{Name: "null", Input: "null", Parser: Value, ExpectedOK: true},
+		{Name: "true", Input: "true", Parser: Value, ExpectedMatch: true, ExpectedOK: true},
+		{Name: "false", Input: "false", Parser: Value, ExpectedMatch: false, ExpectedOK: true},
+		{Name: "integer", Input: "42", Parser: Value, ExpectedMatch: 42.0, ExpectedOK: true},
+		{Name: "negative zero", Input: "-0", Parser: Value, ExpectedMatch: 0.0, ExpectedOK: true},
+		{Name: "fraction and exponent", Input: "-12.5e-1", Parser: Value, ExpectedMatch: -1.25, ExpectedOK: true},
+		{Name: "exponent with plus", Input: "1E+2", Parser: Value, ExpectedMatch: 100.0, ExpectedOK: true},
+		{Name: "string", Input: `"hello"`, Parser: Value, ExpectedMatch: "hello", ExpectedOK: true},
+		{Name: "escapes", Input: `"\"\\\/\b\f\n\r\t"`, Parser: Value, ExpectedMatch: "\"\\/\b\f\n\r\t", ExpectedOK: true},
+		{Name: "unicode escape", Input: `"caf\u00e9 \u65E5"`, Parser: Value, ExpectedMatch: "café 日", ExpectedOK: true},
+		{Name: "surrogate pair", Input: `"\ud83d\ude00"`, Parser: Value, ExpectedMatch: "😀", ExpectedOK: true},
+		{Name: "lone surrogate", Input: `"\ud83d!"`, Parser: Value, ExpectedMatch: "\ufffd!", ExpectedOK: true},
+		{Name: "empty array", Input: "[ ]", Parser: Value, ExpectedMatch: []any{}, ExpectedOK: true},
+		{Name: "empty object", Input: "{ }", Parser: Value, ExpectedMatch: map[string]any{}, ExpectedOK: true},
+		{
+			Name:          "nested",
+			Input:         `{"tasks": [{"name": "water the plants", "done": false, "due": null}, {"name": "call mum", "tags": ["home"]}]}`,
+			Parser:        Value,
+			ExpectedMatch: map[string]any{"tasks": []any{map[string]any{"name": "water the plants", "done": false, "due": nil}, map[string]any{"name": "call mum", "tags": []any{"home"}}}},
+			ExpectedOK:    true,
+		},
+		{Name: "duplicate names keep the last value", Input: `{"a": 1, "a": 2}`, Parser: Value, ExpectedMatch: map[string]any{"a": 2.0}, ExpectedOK: true},
+		{Name: "surrounding whitespace", Input: " \t\r\n[1,2] \n", Parser: Value, ExpectedMatch: []any{1.0, 2.0}, ExpectedOK: true},
+		{Name: "following text is not consumed", Input: "[1]```", Parser: Value, ExpectedMatch: []any{1.0}, ExpectedOK: true, RemainingInput: "```"},
+		{Name: "literals are case sensitive", Input: "True", Parser: Value, ExpectedOK: false, RemainingInput: "True"},
+		{Name: "empty", Input: " ", Parser: Value, ExpectedOK: false, RemainingInput: " "},
+		{Name: "not JSON", Input: "- [ ] task", Parser: Value, ExpectedOK: false, RemainingInput: "- [ ] task"},
+		{Name: "number too large", Input: "[1e400]", Parser: Value, ExpectedOK: false, WantErr: true, RemainingInput: "[1e400]"},
+		{Name: "invalid", Input: "[1,]", Parser: Value, ExpectedOK: false, WantErr: true, RemainingInput: "[1,]"},
+	}
+	RunTests(t, tests)
+}
+
+func TestValueNode(t *testing.T) {
+	str := func(text string, start, end int) Node {
+		return Node{Kind: StringKind, Span: core.Range{Start: start, End: end}, Text: text}
+	}
+	tests := []ParserTest[Node]{
+		{
+			Name:   "spans exclude whitespace",
+			Input:  ` { "a" : [ 1.50 , true ] } `,
+			Parser: ValueNode,
+			ExpectedMatch: Node{Kind: ObjectKind, Span: core.Range{Start: 1, End: 26}, Properties: []Property{{
+				Name: str("a", 3, 6),
+				Value: Node{Kind: ArrayKind, Span: core.Range{Start: 9, End: 24}, Elements: []Node{
+					{Kind: NumberKind, Span: core.Range{Start: 11, End: 15}, Text: "1.50"},
+					{Kind: BoolKind, Span: core.Range{Start: 18, End: 22}, Bool: true},
+				}},
+			}}},
+			ExpectedOK: true,
+		},
+		{
+			Name:          "string spans include quotes and escapes",
+			Input:         `"\u00e9"`,
+			Parser:        ValueNode,
+			ExpectedMatch: str("é", 0, 8),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "numbers too large for a float are kept as written",
+			Input:         `1e400`,
+			Parser:        ValueNode,
+			ExpectedMatch: Node{Kind: NumberKind, Span: core.Range{Start: 0, End: 5}, Text: "1e400"},
+			ExpectedOK:    true,
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestSyntaxError(t *testing.T) {
+	tests := []struct {
+		input    string
+		position int
+		reason   string
+	}{
+		{`[1,]`, 3, "expected value, found ']'"},
+		{`[1 2]`, 3, "expected ',' or ']', found '2'"},
+		{`[,1]`, 1, "expected value or ']', found ','"},
+		{"[1,\n  ", 6, "expected value, found end of input"},
+		{`{"a" 1}`, 5, "expected ':', found '1'"},
+		{`{"a": }`, 6, "expected value, found '}'"},
+		{`{"a": 1,}`, 8, "expected string, found '}'"},
+		{`{1: 2}`, 1, "expected string or '}', found '1'"},
+		{`{"a": 1 "b": 2}`, 8, "expected ',' or '}', found '\"'"},
+		{`["abc`, 1, "string is not closed"},
+		{`"a\"`, 0, "string is not closed"},
+		{"\"a\tb\"", 2, "control character U+0009 must be escaped"},
+		{`"a\x"`, 2, `invalid escape \x`},
+		{`"\u12"`, 1, `\u must be followed by 4 hex digits`},
+		{"\"\xff\"", 1, "invalid UTF-8"},
+		{`[01]`, 1, "number must not have leading zeros"},
+		{`-012`, 1, "number must not have leading zeros"},
+		{`1.`, 2, "expected digit, found end of input"},
+		{`1.e5`, 2, "expected digit, found 'e'"},
+		{`[1e+]`, 4, "expected digit, found ']'"},
+		{`[1e400]`, 1, "number 1e400 is out of range"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			_, ok, err := Value(core.NewInput(test.input))
+			assert.False(t, ok)
+			var syntax *SyntaxError
+			if assert.True(t, errors.As(err, &syntax), "expected a syntax error, got %v", err) {
+				assert.Equal(t, test.position, syntax.Position)
+				assert.Equal(t, test.reason, syntax.Reason)
+				line, column := core.LineColumn(test.input, test.position)
+				assert.Equal(t, line, syntax.Line)
+				assert.Equal(t, column, syntax.Column)
+			}
+		})
+	}
+	t.Run("line and column", func(t *testing.T) {
+		_, _, err := Value(core.NewInput("{\n  \"é\": [1,\n    ]}"))
+		var syntax *SyntaxError
+		if assert.True(t, errors.As(err, &syntax)) {
+			assert.Equal(t, 3, syntax.Line)
+			assert.Equal(t, 5, syntax.Column)
+			assert.EqualError(t, err, "invalid JSON at line 3, column 5: expected value, found ']'")
+		}
+	})
+	t.Run("Node.Value without the text", func(t *testing.T) {
+		_, err := Node{Kind: NumberKind, Text: "1e400", Span: core.Range{Start: 3, End: 8}}.Value()
+		assert.EqualError(t, err, "invalid JSON at position 3: number 1e400 is out of range")
+	})
+}
+
+// Cases from the JSON test suites, covering the corners of RFC 8259, compared against encoding/json.
+func TestCompliance(t *testing.T) {
+	inputs := []string{
+		// Accepted.
+		`[]`, `{}`, `""`, `0`, `-0`, `-0.0e-0`, `1E22`, `123e-10000000`, `[[[[[[[[[[]]]]]]]]]]`,
+		`{"":0}`, `{"a":[],"a":{}}`, `["\u0000"]`, `["\uD834\uDd1e"]`, `["\uDFAA"]`, `["\u20ac𝄞"]`, `[","]`,
+		" [1] ", "\r\n\t[\r\n\t1\r\n\t]\r\n\t", `[1,[2,[3]],{"a":{"b":null}}]`, `"\/"`,
+		// Rejected.
+		``, ` `, `[`, `]`, `[1,]`, `[,]`, `[1,,2]`, `{"a":1,}`, `{,}`, `{"a"}`, `{"a" "b"}`, `{a:1}`, `{'a':1}`,
+		`['a']`, `[+1]`, `[.5]`, `[1.]`, `[01]`, `[0x1]`, `[1e]`, `[1e+]`, `[-]`, `[- 1]`, `[Infinity]`, `[NaN]`,
+		`[True]`, `[nul]`, `["\a"]`, `["\u00G0"]`, "[\"\t\"]", "[\"\n\"]", `["abc]`, `[1]]`, `{"a":1}}`,
+		"\u00a0[]", "[\u2028]", "\ufeff[]", `[1;2]`, `[/*c*/]`, `{"a":1 "b":2}`,
+	}
+	for _, input := range inputs {
+		t.Run(input, func(t *testing.T) {
+			in := core.NewInput(input)
+			_, ok, err := ValueNode(in)
+			_, more := in.Peek(1)
+			assert.Equal(t, stdjson.Valid([]byte(input)), ok && err == nil && !more, "err: %v", err)
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	CheckRollback(t, Value, `{"a": [1, true, null, "x"]}`, `[1, 2,]`, `{"a" 1}`, `"\u12"`, `-x`, `01`, `1e400`)
+}
+
+// Generated JSON is accepted by encoding/json and decodes to the same value.
+func TestGenerate(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	kinds := map[string]bool{}
+	for range 200 {
+		s, ok := Generate(Value, r)
+		if !assert.True(t, ok) {
+			continue
+		}
+		var expected any
+		if assert.NoError(t, stdjson.Unmarshal([]byte(s), &expected), s) {
+			actual, _, _ := Value(core.NewInput(s))
+			assert.Equal(t, expected, actual, s)
+		}
+		node, _, _ := ValueNode(core.NewInput(s))
+		kinds[node.Kind.String()] = true
+	}
+	assert.Len(t, kinds, 6, "expected every kind of value to be generated")
+}
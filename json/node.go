@@ -0,0 +1,128 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"strconv"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Kind is the type of a JSON value.
+type Kind int
+
+const (
+	NullKind Kind = iota
+	BoolKind
+	NumberKind
+	StringKind
+	ArrayKind
+	ObjectKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case NullKind:
+		return "null"
+	case BoolKind:
+		return "bool"
+	case NumberKind:
+		return "number"
+	case StringKind:
+		return "string"
+	case ArrayKind:
+		return "array"
+	case ObjectKind:
+		return "object"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Node is a JSON value along with the part of the input it was parsed from, see ValueNode.
+type Node struct {
+	Kind Kind
+	// Span of the value in the input, excluding any surrounding whitespace. The span of a string includes its quotes.
+	Span Range
+	// Bool is the value of a bool.
+	Bool bool
+	// Text is the value of a string with its escapes decoded, or the text of a number as written, e.g. -1.5e3.
+	Text string
+	// Elements of an array.
+	Elements []Node
+	// Properties of an object in the order they were written, including any duplicate names.
+	Properties []Property
+}
+
+// Property is a name and value within an object.
+type Property struct {
+	Name  Node
+	Value Node
+}
+
+// Value returns the node as encoding/json would decode it into an any: nil, bool, float64, string, []any or map[string]any.
+// When an object has duplicate names the last value wins. An error is returned if a number is too large for a float64.
+func (n Node) Value() (any, error) {
+	switch n.Kind {
+	case BoolKind:
+		return n.Bool, nil
+	case NumberKind:
+		f, err := strconv.ParseFloat(n.Text, 64)
+		if err != nil {
+			return nil, &SyntaxError{Position: n.Span.Start, Reason: fmt.Sprintf("number %s is out of range", n.Text)}
+		}
+		return f, nil
+	case StringKind:
+		return n.Text, nil
+	case ArrayKind:
+		values := make([]any, len(n.Elements))
+		for i, element := range n.Elements {
+			v, err := element.Value()
+			if err != nil {
+				return nil, err
+			}
+			values[i] = v
+		}
+		return values, nil
+	case ObjectKind:
+		values := make(map[string]any, len(n.Properties))
+		for _, property := range n.Properties {
+			v, err := property.Value.Value()
+			if err != nil {
+				return nil, err
+			}
+			values[property.Name.Text] = v
+		}
+		return values, nil
+	}
+	return nil, nil
+}
+
+// SyntaxError is returned when the input starts out as JSON but is not valid, e.g. a string that isn't closed.
+type SyntaxError struct {
+	Position int
+	// Line and Column of the error, both counted from 1. Columns are counted in runes.
+	// They are zero for errors returned by Node.Value, which does not have the text.
+	Line   int
+	Column int
+	Reason string
+}
+
+func (e *SyntaxError) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("invalid JSON at position %d: %s", e.Position, e.Reason)
+	}
+	return fmt.Sprintf("invalid JSON at line %d, column %d: %s", e.Line, e.Column, e.Reason)
+}
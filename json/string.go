@@ -0,0 +1,103 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package json
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Runes of the single character escapes, e.g. \n.
+var escapes = map[byte]rune{'"': '"', '\\': '\\', '/': '/', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t'}
+
+// unescape decodes the escapes in the text between the quotes of a string, returning an error if the text contains a
+// control character, invalid UTF-8 or an invalid escape. Offset is the position of the text in the input.
+// As in encoding/json, a \u escape of half a surrogate pair that isn't part of a pair decodes to utf8.RuneError.
+func unescape(raw string, offset int) (string, error) {
+	// Most strings have no escapes, in which case the text is returned as is.
+	var b strings.Builder
+	written := 0
+	for i := 0; i < len(raw); {
+		c := raw[i]
+		switch {
+		case c < 0x20:
+			return "", &SyntaxError{Position: offset + i, Reason: fmt.Sprintf("control character %U must be escaped", c)}
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(raw[i:])
+			if r == utf8.RuneError && size == 1 {
+				return "", &SyntaxError{Position: offset + i, Reason: "invalid UTF-8"}
+			}
+			i += size
+		case c == '\\':
+			r, size, err := decodeEscape(raw[i:], offset+i)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(raw[written:i])
+			b.WriteRune(r)
+			i += size
+			written = i
+		default:
+			i++
+		}
+	}
+	if written == 0 {
+		return raw, nil
+	}
+	b.WriteString(raw[written:])
+	return b.String(), nil
+}
+
+// decodeEscape decodes the escape at the start of s, returning the rune and the length of the escape.
+func decodeEscape(s string, position int) (rune, int, error) {
+	// QuotedString only matches when every escape is followed by something.
+	if r, ok := escapes[s[1]]; ok {
+		return r, 2, nil
+	}
+	if s[1] != 'u' {
+		r, _ := utf8.DecodeRuneInString(s[1:])
+		return 0, 0, &SyntaxError{Position: position, Reason: fmt.Sprintf("invalid escape \\%c", r)}
+	}
+	r, ok := decodeHex(s)
+	if !ok {
+		return 0, 0, &SyntaxError{Position: position, Reason: "\\u must be followed by 4 hex digits"}
+	}
+	if !utf16.IsSurrogate(r) {
+		return r, 6, nil
+	}
+	if low, ok := decodeHex(s[6:]); ok {
+		if pair := utf16.DecodeRune(r, low); pair != utf8.RuneError {
+			return pair, 12, nil
+		}
+	}
+	return utf8.RuneError, 6, nil
+}
+
+// decodeHex decodes a \u escape at the start of s.
+func decodeHex(s string) (rune, bool) {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return 0, false
+	}
+	for _, c := range s[2:6] {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", c) {
+			return 0, false
+		}
+	}
+	n, err := strconv.ParseUint(s[2:6], 16, 32)
+	return rune(n), err == nil
+}
@@ -0,0 +1,768 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"sort"
+	"strings"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// The block structure is parsed a line at a time following the strategy described in the appendix of the CommonMark
+// spec. Each line first continues as many of the open blocks as it can, then may start new blocks, and whatever is left
+// is added to the innermost block as text. Blocks that are not continued are closed.
+
+const codeIndent = 4
+
+var (
+	spaceOrTab = RuneIn(" \t")
+	// The rest of the line is blank.
+	blankRest         = SequenceOf2(SkipZeroOrMore(spaceOrTab), EOF[int]())
+	atxHeadingMarker  = StringFrom(SequenceOf2(SkipBetween(1, 6, Rune('#')), Any(SkipOneOrMore(spaceOrTab), EOF[int]())))
+	atxClosingOnly    = SequenceOf3(SkipZeroOrMore(spaceOrTab), SkipOneOrMore(Rune('#')), blankRest)
+	codeFence         = StringFrom(Any(SkipAtLeast(3, Rune('`')), SkipAtLeast(3, Rune('~'))))
+	closingCodeFence  = SequenceOf2(codeFence, blankRest)
+	setextHeadingLine = SequenceOf2(Any(SkipOneOrMore(Rune('=')), SkipOneOrMore(Rune('-'))), blankRest)
+	thematicBreak     = SequenceOf2(Any(thematicBreakOf('*'), thematicBreakOf('_'), thematicBreakOf('-')), EOF[int]())
+	bulletListMarker  = RuneIn("*+-")
+	orderedListMarker = SequenceOf2(StringFrom(SkipBetween(1, 9, RuneIn("0123456789"))), RuneIn(".)"))
+)
+
+// thematicBreakOf matches three or more of the character, each of which may be followed by spaces or tabs.
+func thematicBreakOf(c rune) Parser[int] {
+	return SkipAtLeast(3, SequenceOf2(Rune(c), SkipZeroOrMore(spaceOrTab)))
+}
+
+// match runs the parser over the text, which is taken from the line, returning what it matched. Any error, such as
+// exceeding the caller's Limits, is kept to be returned once the line is parsed and the text is treated as not matching.
+func match[T any](p *blockParser, parser Parser[T], text string) (T, bool) {
+	m, ok, err := parser(NewNestedInput(p.in, text))
+	if err != nil {
+		if p.err == nil {
+			p.err = err
+		}
+		var zero T
+		return zero, false
+	}
+	return m, ok
+}
+
+// block is a block that is open, i.e. may still take more lines, or closed.
+type block struct {
+	kind     Kind
+	parent   *block
+	children []*block
+	open     bool
+	// Span of the block in the input.
+	start, end int
+	// Text of a paragraph, heading or code block.
+	content content
+	// Whether the last line of the block was blank, used to tell tight lists from loose ones.
+	lastLineBlank   bool
+	lastLineChecked bool
+	// Heading level.
+	level int
+	// Fenced code blocks.
+	fenced      bool
+	fenceChar   byte
+	fenceLength int
+	fenceOffset int
+	info        string
+	literal     string
+	// Lists and list items.
+	list listData
+	// Inlines of a paragraph or heading.
+	inlines *inline
+}
+
+type listData struct {
+	ordered      bool
+	bullet       byte
+	delimiter    byte
+	start        int
+	tight        bool
+	markerOffset int
+	padding      int
+	markerEnd    int
+}
+
+func (b *block) lastChild() *block {
+	if len(b.children) == 0 {
+		return nil
+	}
+	return b.children[len(b.children)-1]
+}
+
+func (b *block) removeChild(child *block) {
+	for i, c := range b.children {
+		if c == child {
+			b.children = append(b.children[:i], b.children[i+1:]...)
+			return
+		}
+	}
+}
+
+// content is the text of a block, which is gathered from lines that may be interrupted by the markers of the blocks
+// containing it, along with where each piece of it came from so that the spans of its inlines can be found.
+type content struct {
+	text     string
+	builder  strings.Builder
+	segments []segment
+}
+
+// segment records that the content from an offset onwards came from a position in the input.
+type segment struct {
+	offset   int
+	position int
+	// The position in the input after the segment, which for a line is after its line break.
+	end int
+}
+
+func (c *content) add(s string, position, end int) {
+	c.segments = append(c.segments, segment{offset: len(c.text), position: position, end: end})
+	c.builder.WriteString(s)
+	c.text = c.builder.String()
+}
+
+// segment returns the segment that an offset in the content is in.
+func (c *content) segment(offset int) segment {
+	i := sort.Search(len(c.segments), func(i int) bool { return c.segments[i].offset > offset }) - 1
+	if i < 0 {
+		return segment{}
+	}
+	return c.segments[i]
+}
+
+// position returns the position in the input of an offset in the content.
+func (c *content) position(offset int) int {
+	s := c.segment(offset)
+	return s.position + offset - s.offset
+}
+
+// end returns the position in the input after the content up to an offset, which is after the line break when the
+// content up to there ends with one, as the break may be CRLF.
+func (c *content) end(offset int) int {
+	if offset > 0 && c.text[offset-1] == '\n' {
+		return c.segment(offset - 1).end
+	}
+	return c.position(offset-1) + 1
+}
+
+// trimStart drops the first n bytes of the content.
+func (c *content) trimStart(n int) {
+	start := c.position(n)
+	c.text = c.text[n:]
+	var segments []segment
+	for i, s := range c.segments {
+		if i+1 < len(c.segments) && c.segments[i+1].offset <= n {
+			continue
+		}
+		if s.offset <= n {
+			s = segment{offset: 0, position: start, end: s.end}
+		} else {
+			s.offset -= n
+		}
+		segments = append(segments, s)
+	}
+	c.segments = segments
+	c.builder.Reset()
+	c.builder.WriteString(c.text)
+}
+
+// blockParser holds the state of the line being parsed.
+type blockParser struct {
+	doc, tip, oldTip, lastMatched *block
+	line                          string
+	lineStart, nextLineStart      int
+	// Offset into the line and the column it is at, which differ when the line contains tabs.
+	offset, column int
+	// The next character that isn't a space or tab, and how far it is indented from the current column.
+	nextNonspace, nextNonspaceColumn, indent int
+	indented, blank                          bool
+	// A tab is partially consumed when only some of the columns it stands for belong to a block marker.
+	partiallyConsumedTab bool
+	allClosed            bool
+	// The end of the last line that was parsed.
+	lastLineEnd int
+	refs        map[string]reference
+	// Input the document is parsed from, whose limits and state apply to parsing the parts of each line.
+	in Input
+	// The first error found while parsing a line.
+	err error
+}
+
+func newBlockParser(in Input) *blockParser {
+	start := in.Checkpoint().Position()
+	doc := &block{kind: DocumentKind, open: true, start: start}
+	return &blockParser{doc: doc, tip: doc, oldTip: doc, lastMatched: doc, lastLineEnd: start, refs: map[string]reference{}, in: in}
+}
+
+// peek returns the byte at an offset in the line, or -1 past its end.
+func (p *blockParser) peek(offset int) int {
+	if offset < len(p.line) {
+		return int(p.line[offset])
+	}
+	return -1
+}
+
+func isSpaceOrTab(c int) bool {
+	return c == ' ' || c == '\t'
+}
+
+func (p *blockParser) findNextNonspace() {
+	i, cols := p.offset, p.column
+	for i < len(p.line) {
+		if c := p.line[i]; c == ' ' {
+			i++
+			cols++
+		} else if c == '\t' {
+			i++
+			cols += 4 - cols%4
+		} else {
+			break
+		}
+	}
+	p.blank = i == len(p.line)
+	p.nextNonspace = i
+	p.nextNonspaceColumn = cols
+	p.indent = cols - p.column
+	p.indented = p.indent >= codeIndent
+}
+
+func (p *blockParser) advanceNextNonspace() {
+	p.offset = p.nextNonspace
+	p.column = p.nextNonspaceColumn
+	p.partiallyConsumedTab = false
+}
+
+// advanceOffset moves along the line by count bytes or, when columns is set, count columns, partially consuming a tab
+// if only some of its columns are needed.
+func (p *blockParser) advanceOffset(count int, columns bool) {
+	for count > 0 && p.offset < len(p.line) {
+		if p.line[p.offset] != '\t' {
+			p.partiallyConsumedTab = false
+			p.offset++
+			p.column++
+			count--
+			continue
+		}
+		charsToTab := 4 - p.column%4
+		if !columns {
+			p.partiallyConsumedTab = false
+			p.column += charsToTab
+			p.offset++
+			count--
+			continue
+		}
+		p.partiallyConsumedTab = charsToTab > count
+		advance := min(charsToTab, count)
+		p.column += advance
+		if !p.partiallyConsumedTab {
+			p.offset++
+		}
+		count -= advance
+	}
+}
+
+// addLine adds the rest of the line to the content of the innermost block.
+func (p *blockParser) addLine() {
+	if p.partiallyConsumedTab {
+		// The remaining columns of the tab are content.
+		p.offset++
+		p.tip.content.add(strings.Repeat(" ", 4-p.column%4), p.lineStart+p.offset-1, p.lineStart+p.offset)
+	}
+	p.tip.content.add(p.line[p.offset:]+"\n", p.lineStart+p.offset, p.nextLineStart)
+}
+
+// addChild adds a new block at an offset in the line, closing any blocks that cannot contain it.
+func (p *blockParser) addChild(kind Kind, offset int) *block {
+	for !canContain(p.tip.kind, kind) {
+		p.finalize(p.tip)
+	}
+	b := &block{kind: kind, parent: p.tip, open: true, start: p.lineStart + offset}
+	p.tip.children = append(p.tip.children, b)
+	p.tip = b
+	return b
+}
+
+func canContain(parent, child Kind) bool {
+	switch parent {
+	case DocumentKind, BlockQuoteKind, ListItemKind:
+		return child != ListItemKind
+	case ListKind:
+		return child == ListItemKind
+	}
+	return false
+}
+
+func acceptsLines(kind Kind) bool {
+	return kind == ParagraphKind || kind == CodeBlockKind
+}
+
+// closeUnmatchedBlocks closes the blocks that were not continued by the current line.
+func (p *blockParser) closeUnmatchedBlocks() {
+	if p.allClosed {
+		return
+	}
+	for p.oldTip != p.lastMatched {
+		parent := p.oldTip.parent
+		p.finalize(p.oldTip)
+		p.oldTip = parent
+	}
+	p.allClosed = true
+}
+
+// incorporateLine parses a line, which does not include its line break, given where it and the next line start.
+func (p *blockParser) incorporateLine(line string, lineStart, nextLineStart int) {
+	p.line, p.lineStart, p.nextLineStart = line, lineStart, nextLineStart
+	p.offset, p.column = 0, 0
+	p.blank, p.partiallyConsumedTab = false, false
+	p.oldTip = p.tip
+
+	// Continue the open blocks that the line matches.
+	container := p.doc
+	for {
+		last := container.lastChild()
+		if last == nil || !last.open {
+			break
+		}
+		container = last
+		p.findNextNonspace()
+		switch p.continues(container) {
+		case continued:
+			continue
+		case consumed:
+			// The line closed a fenced code block.
+			return
+		}
+		container = container.parent
+		break
+	}
+	p.allClosed = container == p.oldTip
+	p.lastMatched = container
+
+	// Start new blocks until reaching a block that takes the rest of the line as text.
+	matchedLeaf := container.kind != ParagraphKind && acceptsLines(container.kind)
+	for !matchedLeaf {
+		p.findNextNonspace()
+		started := notStarted
+		for _, start := range blockStarts {
+			if started = start(p, container); started != notStarted {
+				break
+			}
+		}
+		if started == notStarted {
+			p.advanceNextNonspace()
+			break
+		}
+		container = p.tip
+		matchedLeaf = started == startedLeaf
+	}
+
+	// The rest of the line is text, either a lazy continuation of a paragraph or content of the innermost block.
+	if !p.allClosed && !p.blank && p.tip.kind == ParagraphKind {
+		p.addLine()
+	} else {
+		p.closeUnmatchedBlocks()
+		if p.blank && container.lastChild() != nil {
+			container.lastChild().lastLineBlank = true
+		}
+		// Blank lines in block quotes and fenced code don't make a list loose, nor does one after an empty item's marker.
+		container.lastLineBlank = p.blank && !(container.kind == BlockQuoteKind || container.kind == HeadingKind ||
+			container.kind == ThematicBreakKind || (container.kind == CodeBlockKind && container.fenced) ||
+			(container.kind == ListItemKind && len(container.children) == 0 && container.start >= lineStart))
+		for b := container.parent; b != nil; b = b.parent {
+			b.lastLineBlank = false
+		}
+		if acceptsLines(container.kind) {
+			p.addLine()
+		} else if p.offset < len(line) && !p.blank {
+			p.addChild(ParagraphKind, p.nextNonspace)
+			p.advanceNextNonspace()
+			p.addLine()
+		}
+	}
+	p.lastLineEnd = lineStart + len(line)
+}
+
+const (
+	continued = iota
+	notContinued
+	consumed
+)
+
+// continues reports whether the line continues an open block, consuming its markers.
+func (p *blockParser) continues(b *block) int {
+	switch b.kind {
+	case DocumentKind, ListKind:
+		return continued
+	case BlockQuoteKind:
+		if p.indented || p.peek(p.nextNonspace) != '>' {
+			return notContinued
+		}
+		p.advanceNextNonspace()
+		p.advanceOffset(1, false)
+		if isSpaceOrTab(p.peek(p.offset)) {
+			p.advanceOffset(1, true)
+		}
+		return continued
+	case ListItemKind:
+		switch {
+		case p.blank:
+			if len(b.children) == 0 {
+				// An item can begin with at most one blank line.
+				return notContinued
+			}
+			p.advanceNextNonspace()
+		case p.indent >= b.list.markerOffset+b.list.padding:
+			p.advanceOffset(b.list.markerOffset+b.list.padding, true)
+		default:
+			return notContinued
+		}
+		return continued
+	case CodeBlockKind:
+		if b.fenced {
+			rest := p.line[p.nextNonspace:]
+			if p.indent <= 3 && p.peek(p.nextNonspace) == int(b.fenceChar) && matches(p, closingCodeFence, rest) &&
+				fenceLength(rest) >= b.fenceLength {
+				p.lastLineEnd = p.lineStart + len(strings.TrimRight(p.line, " \t"))
+				p.finalize(b)
+				return consumed
+			}
+			// Skip the indentation of the opening fence.
+			for i := b.fenceOffset; i > 0 && isSpaceOrTab(p.peek(p.offset)); i-- {
+				p.advanceOffset(1, true)
+			}
+			return continued
+		}
+		switch {
+		case p.indent >= codeIndent:
+			p.advanceOffset(codeIndent, true)
+		case p.blank:
+			p.advanceNextNonspace()
+		default:
+			return notContinued
+		}
+		return continued
+	case ParagraphKind:
+		if p.blank {
+			return notContinued
+		}
+		return continued
+	}
+	// Headings and thematic breaks are a single line.
+	return notContinued
+}
+
+// matches reports whether the parser matches the text, see match.
+func matches[T any](p *blockParser, parser Parser[T], text string) bool {
+	_, ok := match(p, parser, text)
+	return ok
+}
+
+// trimClosingSequence removes the optional closing sequence of an ATX heading, #s preceded by a space or tab and
+// followed by nothing but spaces and tabs.
+func trimClosingSequence(text string) string {
+	trimmed := strings.TrimRight(text, " \t")
+	withoutHashes := strings.TrimRight(trimmed, "#")
+	if len(withoutHashes) == len(trimmed) || withoutHashes == "" || !isSpaceOrTab(int(withoutHashes[len(withoutHashes)-1])) {
+		return text
+	}
+	return strings.TrimRight(withoutHashes, " \t")
+}
+
+// trimTrailingBlankLines removes the line breaks at the end of the text and any spaces and tabs between them.
+func trimTrailingBlankLines(text string) string {
+	trimmed := strings.TrimRight(text, " \t\n")
+	if i := strings.IndexByte(text[len(trimmed):], '\n'); i >= 0 {
+		return text[:len(trimmed)+i]
+	}
+	return text
+}
+
+func fenceLength(s string) int {
+	n := 0
+	for n < len(s) && s[n] == s[0] {
+		n++
+	}
+	return n
+}
+
+// finalize closes a block, making its parent the innermost open block.
+func (p *blockParser) finalize(b *block) {
+	b.open = false
+	b.end = p.lastLineEnd
+	switch b.kind {
+	case ParagraphKind:
+		// Link reference definitions at the start of a paragraph are not part of it.
+		if n := p.parseReferences(&b.content); n > 0 {
+			b.content.trimStart(n)
+			b.start = b.content.position(0)
+			if strings.Trim(b.content.text, " \t\n") == "" {
+				b.parent.removeChild(b)
+			}
+		}
+	case CodeBlockKind:
+		if b.fenced {
+			info, rest, _ := strings.Cut(b.content.text, "\n")
+			b.info = unescape(strings.Trim(info, " \t"))
+			b.literal = rest
+			break
+		}
+		// Blank lines at the end of an indented code block are not part of it.
+		b.literal = trimTrailingBlankLines(b.content.text) + "\n"
+		b.end = b.content.position(len(b.literal) - 1)
+	case ListKind:
+		b.list.tight = true
+	items:
+		for i, item := range b.children {
+			last := i == len(b.children)-1
+			if item.lastLineBlank && !last {
+				b.list.tight = false
+				break
+			}
+			// A blank line between any of the blocks of an item makes the list loose too.
+			for j, child := range item.children {
+				if (!last || j < len(item.children)-1) && endsWithBlankLine(child) {
+					b.list.tight = false
+					break items
+				}
+			}
+		}
+		b.end = b.lastChild().end
+	case ListItemKind:
+		if last := b.lastChild(); last != nil {
+			b.end = last.end
+		} else {
+			b.end = b.list.markerEnd
+		}
+	}
+	p.tip = b.parent
+}
+
+// endsWithBlankLine reports whether a block, or the last of its trailing lists and items, ends with a blank line.
+func endsWithBlankLine(b *block) bool {
+	for b != nil {
+		if b.lastLineChecked {
+			return false
+		}
+		b.lastLineChecked = true
+		if b.lastLineBlank {
+			return true
+		}
+		if b.kind != ListKind && b.kind != ListItemKind {
+			return false
+		}
+		b = b.lastChild()
+	}
+	return false
+}
+
+const (
+	notStarted = iota
+	startedContainer
+	startedLeaf
+)
+
+// blockStarts try to start a block at the current position, in order of precedence.
+var blockStarts = []func(p *blockParser, container *block) int{
+	startBlockQuote,
+	startATXHeading,
+	startFencedCode,
+	startSetextHeading,
+	startThematicBreak,
+	startListItem,
+	startIndentedCode,
+}
+
+func startBlockQuote(p *blockParser, container *block) int {
+	if p.indented || p.peek(p.nextNonspace) != '>' {
+		return notStarted
+	}
+	p.advanceNextNonspace()
+	p.advanceOffset(1, false)
+	if isSpaceOrTab(p.peek(p.offset)) {
+		p.advanceOffset(1, true)
+	}
+	p.closeUnmatchedBlocks()
+	p.addChild(BlockQuoteKind, p.nextNonspace)
+	return startedContainer
+}
+
+func startATXHeading(p *blockParser, container *block) int {
+	if p.indented {
+		return notStarted
+	}
+	marker, ok := match(p, atxHeadingMarker, p.line[p.nextNonspace:])
+	if !ok {
+		return notStarted
+	}
+	p.advanceNextNonspace()
+	p.advanceOffset(len(marker), false)
+	p.closeUnmatchedBlocks()
+	heading := p.addChild(HeadingKind, p.nextNonspace)
+	heading.level = len(strings.TrimRight(marker, " \t"))
+	text := p.line[p.offset:]
+	if matches(p, atxClosingOnly, text) {
+		text = ""
+	}
+	text = trimClosingSequence(text)
+	heading.content.add(text, p.lineStart+p.offset, p.lineStart+p.offset+len(text))
+	p.advanceOffset(len(p.line)-p.offset, false)
+	return startedLeaf
+}
+
+func startFencedCode(p *blockParser, container *block) int {
+	if p.indented {
+		return notStarted
+	}
+	rest := p.line[p.nextNonspace:]
+	fence, ok := match(p, codeFence, rest)
+	// The info string of a backtick fence cannot contain backticks.
+	if !ok || (fence[0] == '`' && strings.Contains(rest[len(fence):], "`")) {
+		return notStarted
+	}
+	p.closeUnmatchedBlocks()
+	code := p.addChild(CodeBlockKind, p.nextNonspace)
+	code.fenced = true
+	code.fenceChar = fence[0]
+	code.fenceLength = len(fence)
+	code.fenceOffset = p.indent
+	p.advanceNextNonspace()
+	p.advanceOffset(len(fence), false)
+	return startedLeaf
+}
+
+func startSetextHeading(p *blockParser, container *block) int {
+	if p.indented || container.kind != ParagraphKind || !matches(p, setextHeadingLine, p.line[p.nextNonspace:]) {
+		return notStarted
+	}
+	p.closeUnmatchedBlocks()
+	// Link reference definitions at the start of the paragraph are not part of the heading.
+	if n := p.parseReferences(&container.content); n > 0 {
+		container.content.trimStart(n)
+		container.start = container.content.position(0)
+	}
+	if container.content.text == "" {
+		return notStarted
+	}
+	container.kind = HeadingKind
+	container.level = 2
+	if p.line[p.nextNonspace] == '=' {
+		container.level = 1
+	}
+	p.advanceOffset(len(p.line)-p.offset, false)
+	return startedLeaf
+}
+
+func startThematicBreak(p *blockParser, container *block) int {
+	if p.indented || !matches(p, thematicBreak, p.line[p.nextNonspace:]) {
+		return notStarted
+	}
+	p.closeUnmatchedBlocks()
+	p.addChild(ThematicBreakKind, p.nextNonspace)
+	p.advanceOffset(len(p.line)-p.offset, false)
+	return startedLeaf
+}
+
+func startListItem(p *blockParser, container *block) int {
+	if p.indented && container.kind != ListKind {
+		return notStarted
+	}
+	data, ok := p.parseListMarker(container)
+	if !ok {
+		return notStarted
+	}
+	p.closeUnmatchedBlocks()
+	if p.tip.kind != ListKind || !listsMatch(p.tip.list, data) {
+		list := p.addChild(ListKind, p.nextNonspace)
+		list.list = data
+	}
+	item := p.addChild(ListItemKind, p.nextNonspace)
+	item.list = data
+	return startedContainer
+}
+
+// parseListMarker parses a bullet or number followed by a space, leaving the offset at the item's content.
+func (p *blockParser) parseListMarker(container *block) (listData, bool) {
+	if p.indent >= codeIndent {
+		return listData{}, false
+	}
+	rest := p.line[p.nextNonspace:]
+	data := listData{tight: true, markerOffset: p.indent}
+	var marker string
+	if m, ok := match(p, bulletListMarker, rest); ok {
+		marker = m
+		data.bullet = m[0]
+	} else if m, ok := match(p, orderedListMarker, rest); ok && (container.kind != ParagraphKind || m.A == "1") {
+		// Only a list starting at 1 can interrupt a paragraph.
+		number, delimiter := m.Values()
+		marker = number + delimiter
+		data.ordered = true
+		for _, d := range number {
+			data.start = data.start*10 + int(d-'0')
+		}
+		data.delimiter = delimiter[0]
+	} else {
+		return listData{}, false
+	}
+	next := p.peek(p.nextNonspace + len(marker))
+	if next != -1 && !isSpaceOrTab(next) {
+		return listData{}, false
+	}
+	// An empty item cannot interrupt a paragraph.
+	if container.kind == ParagraphKind && strings.Trim(p.line[p.nextNonspace+len(marker):], " \t") == "" {
+		return listData{}, false
+	}
+	p.advanceNextNonspace()
+	p.advanceOffset(len(marker), true)
+	data.markerEnd = p.lineStart + p.offset
+	spacesStartColumn, spacesStartOffset := p.column, p.offset
+	for {
+		p.advanceOffset(1, true)
+		if p.column-spacesStartColumn >= 5 || !isSpaceOrTab(p.peek(p.offset)) {
+			break
+		}
+	}
+	blankItem := p.peek(p.offset) == -1
+	spaces := p.column - spacesStartColumn
+	if spaces >= 5 || spaces < 1 || blankItem {
+		// The content is indented code, or there is none, so it starts one space after the marker.
+		data.padding = len(marker) + 1
+		p.column, p.offset = spacesStartColumn, spacesStartOffset
+		p.partiallyConsumedTab = false
+		if isSpaceOrTab(p.peek(p.offset)) {
+			p.advanceOffset(1, true)
+		}
+	} else {
+		data.padding = len(marker) + spaces
+	}
+	return data, true
+}
+
+func listsMatch(a, b listData) bool {
+	return a.ordered == b.ordered && a.delimiter == b.delimiter && a.bullet == b.bullet
+}
+
+func startIndentedCode(p *blockParser, container *block) int {
+	if !p.indented || p.tip.kind == ParagraphKind || p.blank {
+		return notStarted
+	}
+	p.advanceOffset(codeIndent, true)
+	p.closeUnmatchedBlocks()
+	p.addChild(CodeBlockKind, p.offset)
+	return startedLeaf
+}
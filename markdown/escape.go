@@ -0,0 +1,88 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var escapeOrEntity = regexp.MustCompile(`\\[!-/:-@\[-` + "`" + `{-~]|&(?:#[xX][0-9a-fA-F]{1,6}|#[0-9]{1,7}|[a-zA-Z][a-zA-Z0-9]{1,31});`)
+
+// unescape decodes the backslash escapes and entities in a destination, title or info string.
+func unescape(s string) string {
+	if !strings.ContainsAny(s, "\\&") {
+		return s
+	}
+	return escapeOrEntity.ReplaceAllStringFunc(s, func(m string) string {
+		if m[0] == '\\' {
+			return m[1:]
+		}
+		decoded, _ := decodeEntity(m)
+		return decoded
+	})
+}
+
+// decodeEntity decodes an entity such as &amp; or &#35;, reporting whether it is one that HTML defines.
+// Numeric references to code points that are not allowed decode to the replacement character.
+func decodeEntity(s string) (string, bool) {
+	if s[1] == '#' {
+		var code int64
+		if s[2] == 'x' || s[2] == 'X' {
+			code, _ = strconv.ParseInt(s[3:len(s)-1], 16, 32)
+		} else {
+			code, _ = strconv.ParseInt(s[2:len(s)-1], 10, 32)
+		}
+		r := rune(code)
+		if r == 0 || !utf8.ValidRune(r) {
+			r = utf8.RuneError
+		}
+		return string(r), true
+	}
+	decoded := html.UnescapeString(s)
+	// html also decodes a prefix of an unknown name that is an entity without its semicolon, e.g. &ampx; to &x;,
+	// which Markdown does not.
+	if decoded == s || (strings.HasSuffix(decoded, ";") && decoded != ";") {
+		return s, false
+	}
+	return decoded, true
+}
+
+// normalizeURI percent encodes the characters of a destination that are not allowed in a URI, leaving any that are
+// already encoded.
+func normalizeURI(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 2
+		case c < utf8.RuneSelf && (isASCIIAlphanumeric(rune(c)) || strings.IndexByte(";/?:@&=+$,-_.!~*'()#", c) >= 0):
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
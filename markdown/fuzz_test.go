@@ -0,0 +1,55 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown_test
+
+import (
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/markdown"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var fuzzSeeds = []string{"# Notes\n\n- [ ] *task*\n  > `code`\n", "1) a\r\n\r\n2) b", "```go\nx\n", "[a]: /b\n[a] ![c](<d> 'e')", "\t- \ta\\\n b", "**a *b** c*"}
+
+func FuzzDocument(f *testing.F) {
+	FuzzParser(f, Document, fuzzSeeds...)
+}
+
+// FuzzSpans checks that the span of every node is within its parent's and after its previous sibling's.
+func FuzzSpans(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		node, ok, err := Document(core.NewInput(s))
+		assert.True(t, ok)
+		assert.NoError(t, err)
+		assert.Equal(t, core.Range{Start: 0, End: len(s)}, node.Span)
+		var check func(node Node)
+		check = func(node Node) {
+			end := node.Span.Start
+			for _, child := range node.Children {
+				assert.LessOrEqual(t, end, child.Span.Start, "%s starts before the end of its sibling in %q", child.Kind, s)
+				assert.LessOrEqual(t, child.Span.Start, child.Span.End, "%s ends before it starts in %q", child.Kind, s)
+				assert.LessOrEqual(t, child.Span.End, node.Span.End, "%s ends after its parent %s in %q", child.Kind, node.Kind, s)
+				end = child.Span.End
+				check(child)
+			}
+		}
+		check(node)
+	})
+}
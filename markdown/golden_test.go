@@ -0,0 +1,59 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown_test
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/markdown"
+	. "github.com/liamawhite/parse/test"
+)
+
+// Run go test -update to write the golden files after an intended change.
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	RunGolden(t, GoldenTest[Node]{Dir: "testdata", Parser: Document, Format: formatTree})
+}
+
+// formatTree writes a node and its children one per line along with their spans.
+func formatTree(node Node) string {
+	var b strings.Builder
+	var write func(node Node, depth int)
+	write = func(node Node, depth int) {
+		fmt.Fprintf(&b, "%s%s %d-%d", strings.Repeat("  ", depth), node.Kind, node.Span.Start, node.Span.End)
+		switch node.Kind {
+		case HeadingKind:
+			fmt.Fprintf(&b, " level=%d", node.Level)
+		case CodeBlockKind:
+			fmt.Fprintf(&b, " fenced=%v info=%q %q", node.Fenced, node.Info, node.Text)
+		case ListKind:
+			fmt.Fprintf(&b, " ordered=%v start=%d marker=%q tight=%v", node.Ordered, node.Start, node.Marker, node.Tight)
+		case TextKind, CodeSpanKind:
+			fmt.Fprintf(&b, " %q", node.Text)
+		case LinkKind, ImageKind:
+			fmt.Fprintf(&b, " %q %q", node.Destination, node.Title)
+		}
+		b.WriteString("\n")
+		for _, child := range node.Children {
+			write(child, depth+1)
+		}
+	}
+	write(node, 0)
+	return b.String()
+}
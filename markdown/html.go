@@ -0,0 +1,160 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strconv"
+	"strings"
+)
+
+// HTML renders a node and its children as HTML in the same form as the examples in the CommonMark spec.
+func HTML(n Node) string {
+	r := renderer{}
+	r.render(n, false)
+	return r.String()
+}
+
+type renderer struct {
+	strings.Builder
+	// Images are rendered with their text as the alt attribute, within which tags are left out.
+	inImage int
+}
+
+var escaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", `"`, "&quot;")
+
+func (r *renderer) tag(s string) {
+	if r.inImage == 0 {
+		r.WriteString(s)
+	}
+}
+
+// cr starts a new line unless already at the start of one.
+func (r *renderer) cr() {
+	if s := r.String(); s != "" && !strings.HasSuffix(s, "\n") {
+		r.WriteByte('\n')
+	}
+}
+
+func (r *renderer) children(n Node, tight bool) {
+	for _, child := range n.Children {
+		r.render(child, tight)
+	}
+}
+
+// render writes a node, where tight is set for the children of the items of a tight list.
+func (r *renderer) render(n Node, tight bool) {
+	switch n.Kind {
+	case DocumentKind:
+		r.children(n, false)
+	case ParagraphKind:
+		if tight {
+			r.children(n, false)
+			return
+		}
+		r.cr()
+		r.tag("<p>")
+		r.children(n, false)
+		r.tag("</p>")
+		r.cr()
+	case HeadingKind:
+		level := strconv.Itoa(n.Level)
+		r.cr()
+		r.tag("<h" + level + ">")
+		r.children(n, false)
+		r.tag("</h" + level + ">")
+		r.cr()
+	case ThematicBreakKind:
+		r.cr()
+		r.tag("<hr />")
+		r.cr()
+	case CodeBlockKind:
+		r.cr()
+		r.tag("<pre><code")
+		if words := strings.Fields(n.Info); len(words) > 0 {
+			r.tag(` class="language-` + escaper.Replace(words[0]) + `"`)
+		}
+		r.tag(">")
+		r.WriteString(escaper.Replace(n.Text))
+		r.tag("</code></pre>")
+		r.cr()
+	case BlockQuoteKind:
+		r.cr()
+		r.tag("<blockquote>")
+		r.cr()
+		r.children(n, false)
+		r.cr()
+		r.tag("</blockquote>")
+		r.cr()
+	case ListKind:
+		r.cr()
+		switch {
+		case !n.Ordered:
+			r.tag("<ul>")
+		case n.Start != 1:
+			r.tag(`<ol start="` + strconv.Itoa(n.Start) + `">`)
+		default:
+			r.tag("<ol>")
+		}
+		r.cr()
+		for _, item := range n.Children {
+			r.tag("<li>")
+			r.children(item, n.Tight)
+			r.tag("</li>")
+			r.cr()
+		}
+		if n.Ordered {
+			r.tag("</ol>")
+		} else {
+			r.tag("</ul>")
+		}
+		r.cr()
+	case TextKind:
+		r.WriteString(escaper.Replace(n.Text))
+	case SoftBreakKind:
+		r.WriteByte('\n')
+	case HardBreakKind:
+		r.tag("<br />")
+		r.cr()
+	case CodeSpanKind:
+		r.tag("<code>")
+		r.WriteString(escaper.Replace(n.Text))
+		r.tag("</code>")
+	case EmphasisKind:
+		r.tag("<em>")
+		r.children(n, false)
+		r.tag("</em>")
+	case StrongKind:
+		r.tag("<strong>")
+		r.children(n, false)
+		r.tag("</strong>")
+	case LinkKind:
+		r.tag(`<a href="` + escaper.Replace(n.Destination) + `"`)
+		if n.Title != "" {
+			r.tag(` title="` + escaper.Replace(n.Title) + `"`)
+		}
+		r.tag(">")
+		r.children(n, false)
+		r.tag("</a>")
+	case ImageKind:
+		r.tag(`<img src="` + escaper.Replace(n.Destination) + `" alt="`)
+		r.inImage++
+		r.children(n, false)
+		r.inImage--
+		if n.Title != "" {
+			r.tag(`" title="` + escaper.Replace(n.Title))
+		}
+		r.tag(`" />`)
+	}
+}
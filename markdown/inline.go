@@ -0,0 +1,788 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Inlines are parsed from the text of a paragraph or heading once all of the blocks, and so all of the link reference
+// definitions, are known. Emphasis and links cannot be parsed left to right, as whether a * or [ opens anything depends
+// on what follows, so they are kept on stacks of delimiters and brackets following the algorithm in the appendix of the
+// CommonMark spec.
+
+// inline is a node in the tree of inlines, which is rearranged as emphasis and links are found.
+type inline struct {
+	kind Kind
+	text string
+	// Span of the inline in the text it was parsed from.
+	start, end         int
+	destination, title string
+	parent             *inline
+	first, last        *inline
+	prev, next         *inline
+}
+
+func (n *inline) appendChild(child *inline) {
+	child.unlink()
+	child.parent = n
+	if n.last != nil {
+		n.last.next = child
+		child.prev = n.last
+	} else {
+		n.first = child
+	}
+	n.last = child
+}
+
+func (n *inline) insertAfter(sibling *inline) {
+	sibling.unlink()
+	sibling.next = n.next
+	if sibling.next != nil {
+		sibling.next.prev = sibling
+	} else if n.parent != nil {
+		n.parent.last = sibling
+	}
+	sibling.prev = n
+	n.next = sibling
+	sibling.parent = n.parent
+}
+
+func (n *inline) unlink() {
+	if n.prev != nil {
+		n.prev.next = n.next
+	} else if n.parent != nil {
+		n.parent.first = n.next
+	}
+	if n.next != nil {
+		n.next.prev = n.prev
+	} else if n.parent != nil {
+		n.parent.last = n.prev
+	}
+	n.parent, n.prev, n.next = nil, nil, nil
+}
+
+// delimiter is a run of * or _ that may open or close emphasis.
+type delimiter struct {
+	char byte
+	// count is how many of the run are left to use, original is how long it was.
+	count, original   int
+	node              *inline
+	previous, next    *delimiter
+	canOpen, canClose bool
+}
+
+// bracket is a [ or ![ that may open a link or image.
+type bracket struct {
+	node              *inline
+	previous          *bracket
+	previousDelimiter *delimiter
+	// index of the [ in the text.
+	index int
+	image bool
+	// active is unset once the bracket can no longer open a link, as links cannot contain links.
+	active bool
+	// bracketAfter is set once another bracket follows, so the text can no longer be a shortcut reference.
+	bracketAfter bool
+}
+
+// reference is the destination and title of a link reference definition.
+type reference struct {
+	destination, title string
+}
+
+type inlineParser struct {
+	in         Input
+	begin      Checkpoint
+	subject    string
+	delimiters *delimiter
+	brackets   *bracket
+	refs       map[string]reference
+	// The first error found, such as exceeding the Limits of the input the document is parsed from.
+	err error
+}
+
+// newInlineParser creates a parser for text taken from the input, which shares its limits and state.
+func newInlineParser(parent Input, subject string, refs map[string]reference) *inlineParser {
+	in := NewNestedInput(parent, subject)
+	return &inlineParser{in: in, begin: in.Checkpoint(), subject: subject, refs: refs}
+}
+
+// run runs the parser at the current position, keeping the first error to be returned once parsing stops.
+func run[T any](p *inlineParser, parser Parser[T]) (T, bool) {
+	m, ok, err := parser(p.in)
+	if err != nil && p.err == nil {
+		p.err = err
+	}
+	return m, ok
+}
+
+func (p *inlineParser) pos() int {
+	return p.in.Checkpoint().Position() - p.begin.Position()
+}
+
+func (p *inlineParser) seek(pos int) {
+	p.in.Restore(p.begin)
+	p.in.Take(pos)
+}
+
+// peek returns the next byte, or -1 at the end of the text.
+func (p *inlineParser) peek() int {
+	if s, ok := p.in.Peek(1); ok {
+		return int(s[0])
+	}
+	return -1
+}
+
+var (
+	textRun    = StringFrom(SkipOneOrMore(RuneNotIn("\n`[]\\!<&*_")))
+	backticks  = StringFrom(SkipOneOrMore(Rune('`')))
+	spaces     = SkipZeroOrMore(RuneIn(" \t"))
+	spnl       = SequenceOf2(spaces, Optional(SequenceOf2(LF, spaces)))
+	lineEnd    = SequenceOf2(spaces, Any(LF, EOF[string]()))
+	linkTitle  = Any(QuotedString('"', '\\'), QuotedString('\'', '\\'), parenthesizedTitle)
+	entity     = StringFrom(SequenceOf3(Rune('&'), Any(hexEntity, decimalEntity, namedEntity), Rune(';')))
+	uriScheme  = SequenceOf3(RuneWhere(isASCIILetter), SkipBetween(1, 31, RuneWhere(isSchemeRune)), Rune(':'))
+	uri        = SequenceOf4(Rune('<'), uriScheme, SkipZeroOrMore(RuneWhere(isURIRune)), Rune('>'))
+	email      = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+	whitespace = regexp.MustCompile(`[ \t\r\n]+`)
+
+	hexEntity     = StringFrom(SequenceOf3(Rune('#'), RuneIn("xX"), SkipBetween(1, 6, RuneIn("0123456789abcdefABCDEF"))))
+	decimalEntity = StringFrom(SequenceOf2(Rune('#'), SkipBetween(1, 7, RuneIn("0123456789"))))
+	namedEntity   = StringFrom(SequenceOf2(RuneWhere(isASCIILetter), SkipBetween(1, 31, RuneWhere(isASCIIAlphanumeric))))
+)
+
+func isASCIILetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isASCIIAlphanumeric(r rune) bool {
+	return isASCIILetter(r) || (r >= '0' && r <= '9')
+}
+
+func isSchemeRune(r rune) bool {
+	return isASCIIAlphanumeric(r) || r == '.' || r == '+' || r == '-'
+}
+
+func isURIRune(r rune) bool {
+	return r > ' ' && r != '<' && r != '>'
+}
+
+func isASCIIPunctuation(c int) bool {
+	return c >= '!' && c <= '/' || c >= ':' && c <= '@' || c >= '[' && c <= '`' || c >= '{' && c <= '~'
+}
+
+func isUnicodeWhitespace(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\f' || r == '\r' || unicode.Is(unicode.Zs, r)
+}
+
+func isUnicodePunctuation(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+// parenthesizedTitle is a link title in parentheses, which may only contain parentheses that are escaped.
+func parenthesizedTitle(in Input) (string, bool, error) {
+	start := in.Checkpoint()
+	if s, ok := in.Peek(1); !ok || s != "(" {
+		return "", false, nil
+	}
+	in.Take(1)
+	content := in.Checkpoint()
+	for {
+		s, ok := in.Take(1)
+		switch {
+		case !ok || s == "(":
+			in.Restore(start)
+			return "", false, nil
+		case s == "\\":
+			if _, ok := in.Take(1); !ok {
+				in.Restore(start)
+				return "", false, nil
+			}
+		case s == ")":
+			title, _ := in.Peek(content.Position() - in.Checkpoint().Position())
+			return title[:len(title)-1], true, nil
+		}
+	}
+}
+
+// parseInlines parses the text of a paragraph or heading into the children of root, stopping at the first error.
+func (p *inlineParser) parseInlines(root *inline) error {
+	for p.err == nil && p.parseInline(root) {
+	}
+	p.processEmphasis(nil)
+	return p.err
+}
+
+func (p *inlineParser) parseInline(parent *inline) bool {
+	c := p.peek()
+	if c == -1 {
+		return false
+	}
+	var ok bool
+	switch c {
+	case '\n':
+		ok = p.parseNewline(parent)
+	case '\\':
+		ok = p.parseBackslash(parent)
+	case '`':
+		ok = p.parseBackticks(parent)
+	case '*', '_':
+		ok = p.handleDelimiter(parent, byte(c))
+	case '[':
+		ok = p.parseOpenBracket(parent)
+	case '!':
+		ok = p.parseBang(parent)
+	case ']':
+		ok = p.parseCloseBracket(parent)
+	case '<':
+		ok = p.parseAutolink(parent)
+	case '&':
+		ok = p.parseEntity(parent)
+	default:
+		ok = p.parseText(parent)
+	}
+	if !ok {
+		// Anything else is a single character of text.
+		start := p.pos()
+		_, size := utf8.DecodeRuneInString(p.subject[start:])
+		p.in.Take(size)
+		p.appendText(parent, p.subject[start:p.pos()], start)
+	}
+	return true
+}
+
+func (p *inlineParser) appendText(parent *inline, text string, start int) *inline {
+	n := &inline{kind: TextKind, text: text, start: start, end: p.pos()}
+	parent.appendChild(n)
+	return n
+}
+
+func (p *inlineParser) parseText(parent *inline) bool {
+	start := p.pos()
+	text, ok := run(p, textRun)
+	if ok {
+		p.appendText(parent, text, start)
+	}
+	return ok
+}
+
+// parseNewline parses a line break, which is hard if it follows two or more spaces.
+func (p *inlineParser) parseNewline(parent *inline) bool {
+	start := p.pos()
+	p.in.Take(1)
+	kind := SoftBreakKind
+	if last := parent.last; last != nil && last.kind == TextKind && strings.HasSuffix(last.text, " ") {
+		if strings.HasSuffix(last.text, "  ") {
+			kind = HardBreakKind
+		}
+		trimmed := strings.TrimRight(last.text, " ")
+		last.end -= len(last.text) - len(trimmed)
+		last.text = trimmed
+		start = last.end
+	}
+	parent.appendChild(&inline{kind: kind, start: start, end: p.pos()})
+	// Spaces at the start of the next line are not part of the text.
+	run(p, spaces)
+	return true
+}
+
+// parseBackslash parses a backslash, which is a hard line break before a line break and escapes punctuation.
+func (p *inlineParser) parseBackslash(parent *inline) bool {
+	start := p.pos()
+	p.in.Take(1)
+	switch c := p.peek(); {
+	case c == '\n':
+		p.in.Take(1)
+		parent.appendChild(&inline{kind: HardBreakKind, start: start, end: p.pos()})
+	case isASCIIPunctuation(c):
+		s, _ := p.in.Take(1)
+		p.appendText(parent, s, start)
+	default:
+		p.appendText(parent, "\\", start)
+	}
+	return true
+}
+
+// parseBackticks parses a code span, or the backticks as text if there is no closing run of the same length.
+func (p *inlineParser) parseBackticks(parent *inline) bool {
+	start := p.pos()
+	ticks, _ := run(p, backticks)
+	afterOpening := p.pos()
+	for {
+		i := strings.IndexByte(p.subject[p.pos():], '`')
+		if i < 0 {
+			break
+		}
+		p.in.Take(i)
+		closing, _ := run(p, backticks)
+		if len(closing) != len(ticks) {
+			continue
+		}
+		text := strings.ReplaceAll(p.subject[afterOpening:p.pos()-len(ticks)], "\n", " ")
+		// One space either side is stripped so that code can start or end with a backtick.
+		if len(text) > 1 && text[0] == ' ' && text[len(text)-1] == ' ' && strings.Trim(text, " ") != "" {
+			text = text[1 : len(text)-1]
+		}
+		parent.appendChild(&inline{kind: CodeSpanKind, text: text, start: start, end: p.pos()})
+		return true
+	}
+	p.seek(afterOpening)
+	p.appendText(parent, ticks, start)
+	return true
+}
+
+// handleDelimiter adds a run of * or _ to the text and the delimiter stack.
+func (p *inlineParser) handleDelimiter(parent *inline, c byte) bool {
+	start := p.pos()
+	count, canOpen, canClose := p.scanDelimiters(c)
+	p.in.Take(count)
+	node := p.appendText(parent, p.subject[start:p.pos()], start)
+	p.delimiters = &delimiter{
+		char: c, count: count, original: count, node: node, previous: p.delimiters, canOpen: canOpen, canClose: canClose,
+	}
+	if p.delimiters.previous != nil {
+		p.delimiters.previous.next = p.delimiters
+	}
+	return true
+}
+
+// scanDelimiters measures a run of delimiters and whether it can open or close emphasis, which depends on whether it
+// is left or right flanking, i.e. on the characters either side of it.
+func (p *inlineParser) scanDelimiters(c byte) (count int, canOpen, canClose bool) {
+	start := p.pos()
+	for start+count < len(p.subject) && p.subject[start+count] == c {
+		count++
+	}
+	before, after := '\n', '\n'
+	if start > 0 {
+		before, _ = utf8.DecodeLastRuneInString(p.subject[:start])
+	}
+	if start+count < len(p.subject) {
+		after, _ = utf8.DecodeRuneInString(p.subject[start+count:])
+	}
+	afterIsWhitespace, afterIsPunctuation := isUnicodeWhitespace(after), isUnicodePunctuation(after)
+	beforeIsWhitespace, beforeIsPunctuation := isUnicodeWhitespace(before), isUnicodePunctuation(before)
+	leftFlanking := !afterIsWhitespace && (!afterIsPunctuation || beforeIsWhitespace || beforeIsPunctuation)
+	rightFlanking := !beforeIsWhitespace && (!beforeIsPunctuation || afterIsWhitespace || afterIsPunctuation)
+	if c == '_' {
+		// Underscores cannot open or close emphasis inside words.
+		return count, leftFlanking && (!rightFlanking || beforeIsPunctuation), rightFlanking && (!leftFlanking || afterIsPunctuation)
+	}
+	return count, leftFlanking, rightFlanking
+}
+
+func (p *inlineParser) removeDelimiter(d *delimiter) {
+	if d.previous != nil {
+		d.previous.next = d.next
+	}
+	if d.next != nil {
+		d.next.previous = d.previous
+	} else {
+		p.delimiters = d.previous
+	}
+}
+
+// processEmphasis matches the openers and closers on the delimiter stack above bottom, turning the text between them
+// into emphasis, then removes them from the stack.
+func (p *inlineParser) processEmphasis(bottom *delimiter) {
+	// The lowest opener each kind of closer could match, as openers below it have already been ruled out.
+	var openersBottom [12]*delimiter
+	for i := range openersBottom {
+		openersBottom[i] = bottom
+	}
+	closer := p.delimiters
+	for closer != nil && closer.previous != bottom {
+		closer = closer.previous
+	}
+	for closer != nil {
+		if !closer.canClose {
+			closer = closer.next
+			continue
+		}
+		index := closer.original % 3
+		if closer.canOpen {
+			index += 3
+		}
+		if closer.char == '*' {
+			index += 6
+		}
+		opener := closer.previous
+		found := false
+		for opener != nil && opener != bottom && opener != openersBottom[index] {
+			// If either could both open and close, the sum of their lengths cannot be a multiple of 3 unless both are.
+			oddMatch := (closer.canOpen || opener.canClose) && closer.original%3 != 0 &&
+				(opener.original+closer.original)%3 == 0
+			if opener.char == closer.char && opener.canOpen && !oddMatch {
+				found = true
+				break
+			}
+			opener = opener.previous
+		}
+		if !found {
+			openersBottom[index] = closer.previous
+			next := closer.next
+			if !closer.canOpen {
+				p.removeDelimiter(closer)
+			}
+			closer = next
+			continue
+		}
+
+		// Two delimiters from each side make strong emphasis, otherwise one makes regular emphasis.
+		use := 1
+		kind := EmphasisKind
+		if closer.count >= 2 && opener.count >= 2 {
+			use = 2
+			kind = StrongKind
+		}
+		openerNode, closerNode := opener.node, closer.node
+		opener.count -= use
+		closer.count -= use
+		openerNode.text = openerNode.text[:len(openerNode.text)-use]
+		openerNode.end -= use
+		closerNode.text = closerNode.text[use:]
+		closerNode.start += use
+		emphasis := &inline{kind: kind, start: openerNode.end, end: closerNode.start}
+		for n := openerNode.next; n != nil && n != closerNode; {
+			next := n.next
+			emphasis.appendChild(n)
+			n = next
+		}
+		openerNode.insertAfter(emphasis)
+
+		// Delimiters between the opener and closer can no longer match.
+		if opener.next != closer {
+			opener.next = closer
+			closer.previous = opener
+		}
+		if opener.count == 0 {
+			openerNode.unlink()
+			p.removeDelimiter(opener)
+		}
+		if closer.count == 0 {
+			closerNode.unlink()
+			next := closer.next
+			p.removeDelimiter(closer)
+			closer = next
+		}
+	}
+	for p.delimiters != nil && p.delimiters != bottom {
+		p.removeDelimiter(p.delimiters)
+	}
+}
+
+func (p *inlineParser) addBracket(node *inline, index int, image bool) {
+	if p.brackets != nil {
+		p.brackets.bracketAfter = true
+	}
+	p.brackets = &bracket{node: node, previous: p.brackets, previousDelimiter: p.delimiters, index: index, image: image, active: true}
+}
+
+func (p *inlineParser) parseOpenBracket(parent *inline) bool {
+	start := p.pos()
+	p.in.Take(1)
+	p.addBracket(p.appendText(parent, "[", start), start, false)
+	return true
+}
+
+func (p *inlineParser) parseBang(parent *inline) bool {
+	start := p.pos()
+	p.in.Take(1)
+	if p.peek() != '[' {
+		p.appendText(parent, "!", start)
+		return true
+	}
+	p.in.Take(1)
+	p.addBracket(p.appendText(parent, "![", start), start+1, true)
+	return true
+}
+
+// parseCloseBracket parses a ], which closes a link or image if it follows an opening bracket and is followed by a
+// destination or is a reference to a definition.
+func (p *inlineParser) parseCloseBracket(parent *inline) bool {
+	p.in.Take(1)
+	start := p.pos()
+	opener := p.brackets
+	if opener == nil {
+		p.appendText(parent, "]", start-1)
+		return true
+	}
+	if !opener.active {
+		p.appendText(parent, "]", start-1)
+		p.brackets = opener.previous
+		return true
+	}
+
+	var destination, title string
+	matched := false
+	if p.peek() == '(' {
+		p.in.Take(1)
+		run(p, spnl)
+		if d, ok := p.parseLinkDestination(); ok {
+			destination = d
+			run(p, spnl)
+			// A title must be separated from the destination.
+			if isUnicodeWhitespace(rune(p.subject[p.pos()-1])) {
+				if t, ok := run(p, linkTitle); ok {
+					title = unescape(t)
+				}
+			}
+			run(p, spnl)
+			if p.peek() == ')' {
+				p.in.Take(1)
+				matched = true
+			}
+		}
+		if !matched {
+			p.seek(start)
+		}
+	}
+	if !matched {
+		// A full reference link is followed by its label, otherwise the text is the label.
+		beforeLabel := p.pos()
+		n := p.parseLinkLabel()
+		var label string
+		if n > 2 {
+			label = p.subject[beforeLabel : beforeLabel+n]
+		} else if !opener.bracketAfter {
+			label = p.subject[opener.index:start]
+		}
+		if n == 0 {
+			p.seek(start)
+		}
+		if label != "" {
+			if ref, ok := p.refs[normalizeReference(label)]; ok {
+				destination, title = ref.destination, ref.title
+				matched = true
+			}
+		}
+	}
+	if !matched {
+		p.brackets = opener.previous
+		p.seek(start)
+		p.appendText(parent, "]", start-1)
+		return true
+	}
+
+	link := &inline{kind: LinkKind, destination: destination, title: title, start: opener.node.start, end: p.pos()}
+	if opener.image {
+		link.kind = ImageKind
+	}
+	for n := opener.node.next; n != nil; {
+		next := n.next
+		link.appendChild(n)
+		n = next
+	}
+	parent.appendChild(link)
+	p.processEmphasis(opener.previousDelimiter)
+	p.brackets = opener.previous
+	opener.node.unlink()
+	if !opener.image {
+		// Links cannot contain links, so earlier brackets can no longer open one.
+		for b := p.brackets; b != nil; b = b.previous {
+			if !b.image {
+				b.active = false
+			}
+		}
+	}
+	return true
+}
+
+// parseLinkDestination parses a destination, which is either within <> or has balanced parentheses and no spaces.
+func (p *inlineParser) parseLinkDestination() (string, bool) {
+	start := p.pos()
+	if p.peek() == '<' {
+		for i := start + 1; i < len(p.subject); i++ {
+			switch p.subject[i] {
+			case '>':
+				p.in.Take(i + 1 - start)
+				return normalizeURI(unescape(p.subject[start+1 : i])), true
+			case '\n', '<':
+				return "", false
+			case '\\':
+				if i+1 < len(p.subject) && p.subject[i+1] != '\n' {
+					i++
+				}
+			}
+		}
+		return "", false
+	}
+	i, parentheses := start, 0
+loop:
+	for ; i < len(p.subject); i++ {
+		switch c := p.subject[i]; {
+		case c == '\\' && i+1 < len(p.subject) && isASCIIPunctuation(int(p.subject[i+1])):
+			i++
+		case c == '(':
+			// As in cmark, nesting is limited so that an unclosed destination isn't scanned again for every link.
+			if parentheses++; parentheses > 32 {
+				return "", false
+			}
+		case c == ')':
+			if parentheses == 0 {
+				break loop
+			}
+			parentheses--
+		case c <= ' ' || c == 0x7f:
+			break loop
+		}
+	}
+	if (i == start && (i == len(p.subject) || p.subject[i] != ')')) || parentheses != 0 {
+		return "", false
+	}
+	p.in.Take(i - start)
+	return normalizeURI(unescape(p.subject[start:i])), true
+}
+
+// parseLinkLabel parses a link label in brackets, returning its length or 0 if there isn't one.
+func (p *inlineParser) parseLinkLabel() int {
+	start := p.pos()
+	if p.peek() != '[' {
+		return 0
+	}
+	runes := 0
+	for i := start + 1; i < len(p.subject) && runes <= 999; i++ {
+		switch p.subject[i] {
+		case '[':
+			return 0
+		case ']':
+			p.in.Take(i + 1 - start)
+			return i + 1 - start
+		case '\\':
+			if i+1 == len(p.subject) {
+				return 0
+			}
+			i++
+		}
+		if utf8.RuneStart(p.subject[i]) {
+			runes++
+		}
+	}
+	return 0
+}
+
+// parseReference parses a link reference definition at the start of the text, adding it to the references unless the
+// label is already defined. It returns the length of the definition or 0 if there isn't one.
+func (p *inlineParser) parseReference() int {
+	n := p.parseLinkLabel()
+	if n == 0 || p.peek() != ':' {
+		return 0
+	}
+	label := p.subject[:n]
+	p.in.Take(1)
+	run(p, spnl)
+	destination, ok := p.parseLinkDestination()
+	if !ok {
+		return 0
+	}
+	beforeTitle := p.in.Checkpoint()
+	run(p, spnl)
+	var title string
+	hasTitle := false
+	if p.in.Checkpoint().Position() != beforeTitle.Position() {
+		var t string
+		if t, hasTitle = run(p, linkTitle); hasTitle {
+			title = unescape(t)
+		}
+	}
+	if !hasTitle {
+		p.in.Restore(beforeTitle)
+	}
+	if _, ok := run(p, lineEnd); !ok {
+		if !hasTitle {
+			return 0
+		}
+		// The title isn't followed by the end of the line, but the destination may be.
+		title = ""
+		p.in.Restore(beforeTitle)
+		if _, ok := run(p, lineEnd); !ok {
+			return 0
+		}
+	}
+	key := normalizeReference(label)
+	if key == "" {
+		return 0
+	}
+	if _, ok := p.refs[key]; !ok {
+		p.refs[key] = reference{destination: destination, title: title}
+	}
+	return p.pos()
+}
+
+// parseReferences parses the link reference definitions at the start of a paragraph, returning their length.
+func (p *blockParser) parseReferences(c *content) int {
+	n := 0
+	for strings.HasPrefix(c.text[n:], "[") {
+		parser := newInlineParser(p.in, c.text[n:], p.refs)
+		length := parser.parseReference()
+		if parser.err != nil && p.err == nil {
+			p.err = parser.err
+		}
+		if length == 0 || p.err != nil {
+			break
+		}
+		n += length
+	}
+	return n
+}
+
+// normalizeReference returns the key a link label is matched by, which ignores case and collapses whitespace.
+func normalizeReference(label string) string {
+	label = strings.TrimSpace(whitespace.ReplaceAllString(label[1:len(label)-1], " "))
+	// Go's case mapping is one rune to one rune, so ß is folded to ss by hand as it is by Unicode case folding.
+	return strings.ToUpper(strings.ReplaceAll(strings.ToLower(label), "ß", "ss"))
+}
+
+// parseAutolink parses a URI or email address in angle brackets.
+func (p *inlineParser) parseAutolink(parent *inline) bool {
+	start := p.pos()
+	if m := email.FindStringSubmatch(p.subject[start:]); m != nil {
+		p.in.Take(len(m[0]))
+		link := &inline{kind: LinkKind, destination: normalizeURI("mailto:" + m[1]), start: start, end: p.pos()}
+		link.appendChild(&inline{kind: TextKind, text: m[1], start: start + 1, end: p.pos() - 1})
+		parent.appendChild(link)
+		return true
+	}
+	if _, ok := run(p, uri); ok {
+		text := p.subject[start+1 : p.pos()-1]
+		link := &inline{kind: LinkKind, destination: normalizeURI(text), start: start, end: p.pos()}
+		link.appendChild(&inline{kind: TextKind, text: text, start: start + 1, end: p.pos() - 1})
+		parent.appendChild(link)
+		return true
+	}
+	return false
+}
+
+// parseEntity parses an HTML entity or numeric character reference, e.g. &amp; or &#35;.
+func (p *inlineParser) parseEntity(parent *inline) bool {
+	start := p.pos()
+	s, ok := run(p, entity)
+	if !ok {
+		return false
+	}
+	decoded, ok := decodeEntity(s)
+	if !ok {
+		p.seek(start)
+		return false
+	}
+	p.appendText(parent, decoded, start)
+	return true
+}
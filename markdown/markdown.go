@@ -0,0 +1,176 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"strings"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Document parses a CommonMark document into a tree of blocks and inlines, each with its span in the input.
+//
+// The blocks are paragraphs, ATX and setext headings, thematic breaks, indented and fenced code blocks, block quotes,
+// lists and link reference definitions, and the inlines are emphasis, code spans, links, images, autolinks, hard and
+// soft line breaks, backslash escapes and entities. HTML is not supported, so HTML blocks and inline HTML are text.
+//
+// All of the input is consumed, as any text is a valid document, so it always matches unless the Limits or context of
+// the input, which apply to parsing the inlines as well as the blocks, stop it with an error.
+// Lines end with LF, CRLF or CR, as they do in the CommonMark spec.
+var Document Parser[Node] = func(in Input) (Node, bool, error) {
+	start := in.Checkpoint()
+	p := newBlockParser(in)
+	for {
+		lineStart := in.Checkpoint().Position()
+		line, _, err := restOfLine(in)
+		if err != nil {
+			in.Restore(start)
+			return Node{}, false, err
+		}
+		_, ended, err := lineEnding(in)
+		if err != nil {
+			in.Restore(start)
+			return Node{}, false, err
+		}
+		if !ended && line == "" {
+			break
+		}
+		p.incorporateLine(line, lineStart, in.Checkpoint().Position())
+		if p.err != nil {
+			in.Restore(start)
+			return Node{}, false, p.err
+		}
+		if !ended {
+			break
+		}
+	}
+	for p.tip != nil {
+		p.finalize(p.tip)
+	}
+	p.doc.end = in.Checkpoint().Position()
+	if p.err != nil {
+		in.Restore(start)
+		return Node{}, false, p.err
+	}
+	if err := p.parseInlines(p.doc); err != nil {
+		in.Restore(start)
+		return Node{}, false, err
+	}
+	return p.doc.node(), true, nil
+}
+
+var (
+	lineEnding = Any(NewLine, CR)
+	restOfLine = StringWhileNotEOFOr(lineEnding)
+)
+
+// parseInlines parses the text of every paragraph and heading.
+func (p *blockParser) parseInlines(b *block) error {
+	for _, child := range b.children {
+		if err := p.parseInlines(child); err != nil {
+			return err
+		}
+	}
+	if b.kind != ParagraphKind && b.kind != HeadingKind {
+		return nil
+	}
+	// Leading and trailing whitespace is not part of the text.
+	text := strings.TrimRight(b.content.text, " \t\n")
+	trimmed := len(text)
+	text = strings.TrimLeft(text, " \t\n")
+	b.content.trimStart(trimmed - len(text))
+	b.content.text = text
+	b.inlines = &inline{}
+	return newInlineParser(p.in, text, p.refs).parseInlines(b.inlines)
+}
+
+func (b *block) node() Node {
+	n := Node{Kind: b.kind, Span: Range{Start: b.start, End: b.end}}
+	switch b.kind {
+	case HeadingKind:
+		n.Level = b.level
+	case CodeBlockKind:
+		n.Text = replaceNull(b.literal)
+		n.Info = replaceNull(b.info)
+		n.Fenced = b.fenced
+	case ListKind:
+		n.Ordered = b.list.ordered
+		n.Start = b.list.start
+		n.Tight = b.list.tight
+		n.Marker = rune(b.list.bullet)
+		if b.list.ordered {
+			n.Marker = rune(b.list.delimiter)
+		}
+	}
+	for _, child := range b.children {
+		n.Children = append(n.Children, child.node())
+	}
+	if b.inlines != nil {
+		n.Children = b.inlineNodes(b.inlines)
+	}
+	return n
+}
+
+// inlineNodes returns the children of an inline, merging adjacent text and dropping any that is empty.
+func (b *block) inlineNodes(parent *inline) []Node {
+	var nodes []Node
+	for n := parent.first; n != nil; n = n.next {
+		if n.kind != TextKind {
+			node := Node{Kind: n.kind, Span: b.span(n.start, n.end), Destination: replaceNull(n.destination), Title: replaceNull(n.title)}
+			if n.kind == CodeSpanKind {
+				node.Text = replaceNull(n.text)
+			}
+			node.Children = b.inlineNodes(n)
+			nodes = append(nodes, node)
+			continue
+		}
+		last := n
+		for last.next != nil && last.next.kind == TextKind {
+			last = last.next
+		}
+		// Empty text, e.g. left by delimiters that became emphasis, has no part in the span.
+		var text strings.Builder
+		start, end := -1, 0
+		for ; ; n = n.next {
+			if n.text != "" {
+				if start < 0 {
+					start = n.start
+				}
+				text.WriteString(n.text)
+				end = n.end
+			}
+			if n == last {
+				break
+			}
+		}
+		if text.Len() > 0 {
+			nodes = append(nodes, Node{Kind: TextKind, Span: b.span(start, end), Text: replaceNull(text.String())})
+		}
+	}
+	return nodes
+}
+
+// span returns the span in the input of part of the block's text.
+func (b *block) span(start, end int) Range {
+	if end == start {
+		return Range{Start: b.content.position(start), End: b.content.position(start)}
+	}
+	return Range{Start: b.content.position(start), End: b.content.end(end)}
+}
+
+// replaceNull replaces null characters, which are not allowed in HTML, with the replacement character.
+func replaceNull(s string) string {
+	return strings.ReplaceAll(s, "\x00", "�")
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/markdown"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument(t *testing.T) {
+	node := func(kind Kind, start, end int, children ...Node) Node {
+		return Node{Kind: kind, Span: core.Range{Start: start, End: end}, Children: children}
+	}
+	text := func(text string, start, end int) Node {
+		return Node{Kind: TextKind, Span: core.Range{Start: start, End: end}, Text: text}
+	}
+	heading := node(HeadingKind, 0, 12, text("Hi ", 2, 5), node(EmphasisKind, 5, 12, text("there", 6, 11)))
+	heading.Level = 1
+	list := node(ListKind, 2, 7, node(ListItemKind, 2, 7, node(ParagraphKind, 4, 7, Node{Kind: CodeSpanKind, Span: core.Range{Start: 4, End: 7}, Text: "x"})))
+	list.Marker, list.Tight = '-', true
+	tests := []ParserTest[Node]{
+		{Name: "empty", Input: "", Parser: Document, ExpectedMatch: node(DocumentKind, 0, 0), ExpectedOK: true},
+		{Name: "heading", Input: "# Hi *there*", Parser: Document, ExpectedMatch: node(DocumentKind, 0, 12, heading), ExpectedOK: true},
+		{
+			Name:          "hard break",
+			Input:         "a\\\nb",
+			Parser:        Document,
+			ExpectedMatch: node(DocumentKind, 0, 4, node(ParagraphKind, 0, 4, text("a", 0, 1), node(HardBreakKind, 1, 3), text("b", 3, 4))),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "soft break spans CRLF",
+			Input:         "a\r\nb\r\n",
+			Parser:        Document,
+			ExpectedMatch: node(DocumentKind, 0, 6, node(ParagraphKind, 0, 4, text("a", 0, 1), node(SoftBreakKind, 1, 3), text("b", 3, 4))),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "CR line endings",
+			Input:         "a\rb",
+			Parser:        Document,
+			ExpectedMatch: node(DocumentKind, 0, 3, node(ParagraphKind, 0, 3, text("a", 0, 1), node(SoftBreakKind, 1, 2), text("b", 2, 3))),
+			ExpectedOK:    true,
+		},
+		{Name: "nested blocks", Input: "> - `x`", Parser: Document, ExpectedMatch: node(DocumentKind, 0, 7, node(BlockQuoteKind, 0, 7, list)), ExpectedOK: true},
+	}
+	RunTests(t, tests)
+}
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name, input, expected string
+	}{
+		{"tight list", "- a\n- b\n", "<ul>\n<li>a</li>\n<li>b</li>\n</ul>\n"},
+		{"loose list", "1. a\n\n2. b\n", "<ol>\n<li>\n<p>a</p>\n</li>\n<li>\n<p>b</p>\n</li>\n</ol>\n"},
+		{"fenced code", "~~~ go run\na < b\n~~~\n", "<pre><code class=\"language-go\">a &lt; b\n</code></pre>\n"},
+		{"reference link", "[Notes][]\n\n[notes]: /notes \"My notes\"\n", "<p><a href=\"/notes\" title=\"My notes\">Notes</a></p>\n"},
+		{"image alt text", "![*a* `b`](c.png)", "<p><img src=\"c.png\" alt=\"a b\" /></p>\n"},
+		{"email autolink", "<me@example.com>", "<p><a href=\"mailto:me@example.com\">me@example.com</a></p>\n"},
+		{"HTML is text", "<b>bold</b>", "<p>&lt;b&gt;bold&lt;/b&gt;</p>\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			node, ok, err := Document(core.NewInput(test.input))
+			assert.True(t, ok)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, HTML(node))
+		})
+	}
+}
+
+func TestRollback(t *testing.T) {
+	CheckRollback(t, Document, "# Notes\n\n- [ ] task\n", "> quote\n```\ncode", "*a **b", "")
+}
+
+func TestLimits(t *testing.T) {
+	// Both documents are a single paragraph of the same length, but the links take more work to parse.
+	text := strings.Repeat("a", 700)
+	links := strings.Repeat("[a](b) ", 100)
+	limits := core.Limits{MaxSteps: 4300}
+
+	_, ok, err := core.Parse(context.Background(), Document, text, limits)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	_, ok, err = core.Parse(context.Background(), Document, links, limits)
+	assert.False(t, ok)
+	var limitErr *core.LimitError
+	assert.ErrorAs(t, err, &limitErr)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown
+
+import (
+	"fmt"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Kind is the type of a Markdown node.
+type Kind int
+
+const (
+	DocumentKind Kind = iota
+	ParagraphKind
+	HeadingKind
+	ThematicBreakKind
+	CodeBlockKind
+	BlockQuoteKind
+	ListKind
+	ListItemKind
+	TextKind
+	SoftBreakKind
+	HardBreakKind
+	CodeSpanKind
+	EmphasisKind
+	StrongKind
+	LinkKind
+	ImageKind
+)
+
+func (k Kind) String() string {
+	switch k {
+	case DocumentKind:
+		return "document"
+	case ParagraphKind:
+		return "paragraph"
+	case HeadingKind:
+		return "heading"
+	case ThematicBreakKind:
+		return "thematic break"
+	case CodeBlockKind:
+		return "code block"
+	case BlockQuoteKind:
+		return "block quote"
+	case ListKind:
+		return "list"
+	case ListItemKind:
+		return "list item"
+	case TextKind:
+		return "text"
+	case SoftBreakKind:
+		return "soft break"
+	case HardBreakKind:
+		return "hard break"
+	case CodeSpanKind:
+		return "code span"
+	case EmphasisKind:
+		return "emphasis"
+	case StrongKind:
+		return "strong"
+	case LinkKind:
+		return "link"
+	case ImageKind:
+		return "image"
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Node is a block or inline along with the part of the input it was parsed from, see Document.
+type Node struct {
+	Kind Kind
+	// Span of the node in the input. A block's span starts at its first marker or character and ends at the end of its
+	// last line, excluding the line break. An inline's span includes its delimiters, e.g. the asterisks of emphasis.
+	Span     Range
+	Children []Node
+	// Level of a heading from 1 to 6.
+	Level int
+	// Text of a text node, code span or code block, with any escapes and entities decoded.
+	Text string
+	// Info string of a fenced code block, e.g. go for ```go.
+	Info string
+	// Fenced reports whether a code block is fenced rather than indented.
+	Fenced bool
+	// Destination and Title of a link or image, with any escapes and entities decoded.
+	Destination string
+	Title       string
+	// Ordered reports whether a list is numbered rather than bulleted.
+	Ordered bool
+	// Start is the number of the first item of an ordered list.
+	Start int
+	// Marker of the items of a list, either the bullet (-, + or *) or the delimiter after the number (. or )).
+	Marker rune
+	// Tight reports whether the items of a list are not separated by blank lines, so their paragraphs are not
+	// wrapped in <p> tags when rendered.
+	Tight bool
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package markdown_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/markdown"
+	"github.com/stretchr/testify/assert"
+)
+
+// specExample is an example in spec.json, the examples of the CommonMark spec as JSON.
+type specExample struct {
+	Markdown string `json:"markdown"`
+	HTML     string `json:"html"`
+	Example  int    `json:"example"`
+	Section  string `json:"section"`
+}
+
+// The sections of the spec for HTML, which is not supported.
+var unsupportedSections = map[string]bool{"HTML blocks": true, "Raw HTML": true}
+
+// Examples in other sections whose expected output relies on HTML being passed through.
+var unsupportedExamples = map[int]string{
+	21:  "raw HTML containing a backslash escape",
+	31:  "raw HTML containing entities",
+	201: "raw HTML after a link reference definition",
+	308: "an HTML comment block separating lists",
+	309: "an HTML comment block separating a list from code",
+	344: "raw HTML taking precedence over a code span",
+	475: "raw HTML taking precedence over emphasis",
+	476: "raw HTML taking precedence over strong emphasis",
+	477: "raw HTML taking precedence over strong emphasis",
+	491: "raw HTML spanning lines where a link destination is not allowed to",
+	494: "raw HTML where a link destination is not closed",
+	524: "raw HTML taking precedence over a link",
+	536: "raw HTML taking precedence over a reference link",
+	642: "raw HTML containing a hard line break",
+	643: "raw HTML containing a backslash hard line break",
+}
+
+// TestSpec renders the examples of the CommonMark 0.31.2 spec in testdata/spec/spec.json, which is vendored unchanged
+// from the copy in the goldmark module (github.com/yuin/goldmark v1.8.2, _test/spec.json). It is licensed under
+// CC BY-SA 4.0, see testdata/spec/LICENSE.
+func TestSpec(t *testing.T) {
+	data, err := os.ReadFile("testdata/spec/spec.json")
+	assert.NoError(t, err)
+	var examples []specExample
+	assert.NoError(t, json.Unmarshal(data, &examples))
+	assert.Len(t, examples, 652)
+	for _, example := range examples {
+		t.Run(fmt.Sprintf("%s/%d", example.Section, example.Example), func(t *testing.T) {
+			if unsupportedSections[example.Section] {
+				t.Skip("HTML is not supported")
+			}
+			if reason, ok := unsupportedExamples[example.Example]; ok {
+				t.Skip("HTML is not supported: " + reason)
+			}
+			node, ok, err := Document(core.NewInput(example.Markdown))
+			assert.True(t, ok)
+			assert.NoError(t, err)
+			assert.Equal(t, example.HTML, HTML(node), "markdown: %q", example.Markdown)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	. "github.com/liamawhite/parse/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// FuzzLimits bounds each run of a fuzzed parser so that a parser that loops forever fails instead of hanging.
+// They are generous enough that any parser which runs out on a short input is almost certainly not making progress.
+var FuzzLimits = Limits{MaxSteps: 1_000_000, MaxDepth: 10_000}
+
+// How long a single run of a fuzzed parser may take before it is assumed to be stuck.
+const fuzzTimeout = 10 * time.Second
+
+// FuzzParser fuzzes the parser starting from the seeds, failing if for any input the parser:
+//   - panics
+//   - exceeds FuzzLimits or fuzzTimeout, which usually means it is stuck in a loop
+//   - does not roll back to where it started, including any user state, when it does not match or returns an error
+//   - consumes more than the input or stops part way through a rune of valid UTF-8
+//
+// Call it from a fuzz test, e.g. func FuzzDate(f *testing.F) { FuzzParser(f, Date, "2024-01-01") }.
+// Under go test only the seeds are run, use go test -fuzz to explore further.
+func FuzzParser[T any](f *testing.F, parser Parser[T], seeds ...string) {
+	f.Helper()
+	fuzz(f, parser, nil, seeds)
+}
+
+// FuzzRoundTrip is FuzzParser with the additional check that whenever the parser matches, formatting the match and
+// parsing it again consumes all of the formatted text and returns an equal value.
+// Values are compared with assert.ObjectsAreEqual if equal is nil.
+func FuzzRoundTrip[T any](f *testing.F, parser Parser[T], format func(T) string, equal func(a, b T) bool, seeds ...string) {
+	f.Helper()
+	if equal == nil {
+		equal = func(a, b T) bool { return assert.ObjectsAreEqual(a, b) }
+	}
+	fuzz(f, parser, func(t testing.TB, match T) {
+		text := format(match)
+		again, ok, err := checkRun(t, parser, text)
+		if err != nil || !ok {
+			t.Fatalf("formatted match %q did not parse (ok: %v, err: %v)", text, ok, err)
+		}
+		if again.position != len(text) {
+			t.Fatalf("formatted match %q was only parsed up to position %d", text, again.position)
+		}
+		if !equal(match, again.match) {
+			t.Fatalf("formatted match %q parsed as %v, want %v", text, again.match, match)
+		}
+	}, seeds)
+}
+
+func fuzz[T any](f *testing.F, parser Parser[T], roundTrip func(t testing.TB, match T), seeds []string) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		result, ok, err := checkRun(t, parser, s)
+		if ok && err == nil && roundTrip != nil {
+			roundTrip(t, result.match)
+		}
+	})
+}
+
+type fuzzResult[T any] struct {
+	match    T
+	position int
+}
+
+// checkRun runs the parser over the string within FuzzLimits, failing the test if any of the invariants of FuzzParser do not hold.
+func checkRun[T any](t testing.TB, parser Parser[T], s string) (result fuzzResult[T], ok bool, err error) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), fuzzTimeout)
+	defer cancel()
+	in, err := NewInputContext(ctx, s, FuzzLimits)
+	if err != nil {
+		t.Fatalf("failed to create input: %v", err)
+	}
+
+	start := in.Checkpoint()
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("parser panicked on %q: %v", s, r)
+			}
+		}()
+		result.match, ok, err = parser(in)
+	}()
+	end := in.Checkpoint()
+	result.position = end.Position()
+
+	var limit *LimitError
+	if errors.As(err, &limit) || errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("parser did not finish on %q, it may be stuck in a loop: %v", s, err)
+	}
+	if (!ok || err != nil) && end != start {
+		t.Fatalf("parser did not roll back on %q (ok: %v, err: %v): %s", s, ok, err, in.Debug())
+	}
+	if result.position < 0 || result.position > len(s) {
+		t.Fatalf("parser consumed %d bytes of %q", result.position, s)
+	}
+	if utf8.ValidString(s) && result.position < len(s) && !utf8.RuneStart(s[result.position]) {
+		t.Fatalf("parser stopped part way through a rune at position %d of %q", result.position, s)
+	}
+	return result, ok, err
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"math/rand/v2"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Number of strings Generate tries before giving up.
+const generateAttempts = 1000
+
+// Generate returns a random string the parser matches in full without error, or false if none was found.
+// Strings are generated by running the parser over NewGeneratingInput and kept only if they parse on their own,
+// so parsers that read the input directly need a WithGenerator description to be generated.
+func Generate[T any](parser Parser[T], r *rand.Rand) (string, bool) {
+	for range generateAttempts {
+		in := NewGeneratingInput(r)
+		if _, ok, err := parser(in); err != nil || !ok {
+			continue
+		}
+		s, _ := in.Peek(-in.Checkpoint().Position())
+		if accepts(parser, s) {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// accepts reports whether the parser matches all of s without error.
+func accepts[T any](parser Parser[T], s string) bool {
+	in := NewInput(s)
+	_, ok, err := parser(in)
+	return ok && err == nil && in.Checkpoint().Position() == len(s)
+}
@@ -0,0 +1,160 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/core"
+	"github.com/stretchr/testify/assert"
+)
+
+// Extension of the files holding the expected result for each input.
+const goldenExtension = ".golden"
+
+// Environment variable that, when set to 1, makes RunGolden write the golden files instead of comparing against them.
+const updateGoldenEnv = "UPDATE_GOLDEN"
+
+// Flag that does the same when the package under test defines it. It is looked up rather than defined here
+// as defining it in both would panic.
+const updateGoldenFlag = "update"
+
+// GoldenTest runs a parser over every file in a directory, comparing the results against golden files.
+type GoldenTest[T any] struct {
+	// Directory of inputs, usually testdata. Subdirectories and .golden files are ignored.
+	Dir    string
+	Parser Parser[T]
+	// Serializes a match, defaults to indented JSON which omits unexported fields.
+	Format func(T) string
+	// Update writes the golden files instead of comparing against them, as does setting UPDATE_GOLDEN=1
+	// or passing -update when the package under test defines that flag.
+	Update bool
+}
+
+// RunGolden parses each input in the directory and compares the result with the golden file next to it,
+// e.g. testdata/note.md.golden for testdata/note.md. Run go test -update, if the package defines the flag, or
+// UPDATE_GOLDEN=1 go test to write the golden files instead.
+//
+// The result records whether the parser matched, the match, any input that was not consumed and any error.
+// Errors with an int Position field, like those returned by the core parsers, also record the line and column.
+func RunGolden[T any](t testing.TB, test GoldenTest[T]) {
+	t.Helper()
+	update := test.Update || os.Getenv(updateGoldenEnv) == "1" || updateFlag()
+	entries, err := os.ReadDir(test.Dir)
+	if err != nil {
+		t.Fatalf("failed to read golden test inputs: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), goldenExtension) {
+			continue
+		}
+		run(t, entry.Name(), func(t testing.TB) {
+			t.Helper()
+			path := filepath.Join(test.Dir, entry.Name())
+			input, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read input: %v", err)
+			}
+			got := golden(test, string(input))
+
+			if update {
+				if err := os.WriteFile(path+goldenExtension, []byte(got), 0o644); err != nil {
+					t.Fatalf("failed to update golden file: %v", err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path + goldenExtension)
+			if err != nil {
+				t.Fatalf("failed to read golden file, run with UPDATE_GOLDEN=1 to create it: %v", err)
+			}
+			assert.Equal(t, string(want), got, "Result doesn't match %s", path+goldenExtension)
+		})
+	}
+}
+
+// updateFlag reports whether the -update flag is set, if the package under test defines it.
+func updateFlag() bool {
+	f := flag.Lookup(updateGoldenFlag)
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	update, ok := getter.Get().(bool)
+	return ok && update
+}
+
+// golden runs the parser over the input and serializes the result.
+func golden[T any](test GoldenTest[T], input string) string {
+	in := NewInput(input)
+	match, ok, err := test.Parser(in)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "ok: %v\n", ok)
+	if err != nil {
+		fmt.Fprintf(&b, "error: %v\n", err)
+		if position, ok := errorPosition(err); ok {
+			line, column := LineColumn(input, position)
+			fmt.Fprintf(&b, "position: line %d, column %d\n", line, column)
+		}
+	}
+	if remaining, _ := in.Peek(len(input) - in.Checkpoint().Position()); remaining != "" {
+		fmt.Fprintf(&b, "remaining: %q\n", remaining)
+	}
+	if ok {
+		b.WriteString("match:\n")
+		b.WriteString(strings.TrimSuffix(formatGolden(test.Format, match), "\n"))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func formatGolden[T any](format func(T) string, match T) string {
+	if format != nil {
+		return format(match)
+	}
+	s, err := json.MarshalIndent(match, "", "  ")
+	if err != nil {
+		return fmt.Sprintf("failed to serialize match: %v", err)
+	}
+	return string(s)
+}
+
+// errorPosition returns the Position field of the first error in the chain that has one.
+func errorPosition(err error) (int, bool) {
+	for ; err != nil; err = errors.Unwrap(err) {
+		v := reflect.ValueOf(err)
+		for v.Kind() == reflect.Pointer && !v.IsNil() {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		if position := v.FieldByName("Position"); position.IsValid() && position.CanInt() {
+			return int(position.Int()), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// Packages that import test can define the common -update flag for their own golden files.
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestRunGolden(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "words.txt"), []byte("hello world"), 0o644))
+	golden := GoldenTest[string]{Dir: dir, Parser: StringFrom(OneOrMore(Letter))}
+
+	golden.Update = true
+	RunGolden(t, golden)
+	written, err := os.ReadFile(filepath.Join(dir, "words.txt.golden"))
+	assert.NoError(t, err)
+	assert.Equal(t, "ok: true\nremaining: \" world\"\nmatch:\n\"hello\"\n", string(written))
+
+	golden.Update = false
+	RunGolden(t, golden)
+}
+
+func TestRunGoldenUpdateFlag(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "words.txt"), []byte("hello world"), 0o644))
+	assert.NoError(t, flag.Set("update", "true"))
+	t.Cleanup(func() { _ = flag.Set("update", "false") })
+
+	RunGolden(t, GoldenTest[string]{Dir: dir, Parser: StringFrom(OneOrMore(Letter))})
+	_, err := os.Stat(filepath.Join(dir, "words.txt.golden"))
+	assert.NoError(t, err)
+}
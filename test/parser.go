@@ -12,15 +12,17 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package core_test
+package test
 
 import (
+	"fmt"
 	"testing"
 
 	. "github.com/liamawhite/parse/core"
 	"github.com/stretchr/testify/assert"
 )
 
+// ParserTest is a table driven test of a parser for RunTests and BenchTests.
 type ParserTest[T any] struct {
 	Name           string
 	Input          string
@@ -29,31 +31,87 @@ type ParserTest[T any] struct {
 	ExpectedOK     bool
 	WantErr        bool
 	RemainingInput string
+	// Compares the expected and actual match, e.g. to ignore positions, defaults to assert.ObjectsAreEqual.
+	Equal func(expected, actual T) bool
 }
 
-func RunTests[T any](t *testing.T, tests []ParserTest[T]) {
+// RunTests runs each test as a subtest, failing if the match, whether it matched, the error or the remaining input
+// are not as expected. Failures report the remaining input and the position of any error.
+func RunTests[T any](t testing.TB, tests []ParserTest[T]) {
+	t.Helper()
 	for _, test := range tests {
-		t.Run(test.Name, func(t *testing.T) {
-			in := NewInput(test.Input)
-			match, ok, err := test.Parser(in)
-			if test.ExpectedOK {
-				assert.True(t, ok, "Expected match")
-			} else {
-				assert.False(t, ok, "Expected no match")
-			}
-			assert.Equal(t, test.ExpectedMatch, match, "Expected result doesn't match")
-			if test.WantErr {
-				assert.Error(t, err, "Expected error")
-			} else {
-				assert.NoError(t, err, "Expected no error")
-			}
+		run(t, test.Name, func(t testing.TB) {
+			t.Helper()
+			test.check(t)
+		})
+	}
+}
 
-			remaining, _, _ := StringWhileNot(EOF[string]())(in)
-			assert.Equal(t, test.RemainingInput, remaining, "Remaining input doesn't match")
+// BenchTests benchmarks the parser of each test as a sub-benchmark, after checking that it passes.
+func BenchTests[T any](b *testing.B, tests []ParserTest[T]) {
+	b.Helper()
+	for _, test := range tests {
+		b.Run(test.Name, func(b *testing.B) {
+			test.check(b)
+			b.ReportAllocs()
+			b.SetBytes(int64(len(test.Input)))
+			b.ResetTimer()
+			for range b.N {
+				test.Parser(NewInput(test.Input))
+			}
 		})
 	}
 }
 
+func (test ParserTest[T]) check(t testing.TB) {
+	t.Helper()
+	in := NewInput(test.Input)
+	match, ok, err := test.Parser(in)
+	position := in.Checkpoint().Position()
+	remaining, _ := in.Peek(len(test.Input) - position)
+	context := describe(test.Input, remaining, err)
+
+	if test.ExpectedOK {
+		assert.True(t, ok, "Expected match (%s)", context)
+	} else {
+		assert.False(t, ok, "Expected no match (%s)", context)
+	}
+	if test.Equal != nil {
+		assert.True(t, test.Equal(test.ExpectedMatch, match), "Expected result doesn't match: expected %v, actual %v (%s)", test.ExpectedMatch, match, context)
+	} else {
+		assert.Equal(t, test.ExpectedMatch, match, "Expected result doesn't match (%s)", context)
+	}
+	if test.WantErr {
+		assert.Error(t, err, "Expected error (%s)", context)
+	} else {
+		assert.NoError(t, err, "Expected no error (%s)", context)
+	}
+	assert.Equal(t, test.RemainingInput, remaining, "Remaining input doesn't match (%s)", context)
+}
+
+// describe summarizes where a parser stopped for failure messages.
+func describe(input, remaining string, err error) string {
+	s := fmt.Sprintf("remaining input %q", remaining)
+	if position, ok := errorPosition(err); ok {
+		line, column := LineColumn(input, position)
+		s += fmt.Sprintf(", error at line %d, column %d", line, column)
+	}
+	return s
+}
+
+// run runs f as a subtest if t supports them.
+func run(t testing.TB, name string, f func(t testing.TB)) {
+	t.Helper()
+	switch t := t.(type) {
+	case *testing.T:
+		t.Run(name, func(t *testing.T) { f(t) })
+	case *testing.B:
+		t.Run(name, func(b *testing.B) { f(b) })
+	default:
+		f(t)
+	}
+}
+
 // Change some of the characters in the string to uppercase
 func CaPiTaLiZe(s string) string {
 	runes := []rune(s)
@@ -0,0 +1,101 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test_test
+
+import (
+	"fmt"
+	"testing"
+	"unicode"
+
+	. "github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder records failures rather than failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+// word is a match with the position it was found at.
+type word struct {
+	Text     string
+	Position int
+}
+
+var positionedWord = func(in Input) (word, bool, error) {
+	position := in.Checkpoint().Position()
+	text, ok, err := StringFrom(OneOrMore(RuneInRanges(unicode.Letter)))(in)
+	return word{Text: text, Position: position}, ok, err
+}
+
+func TestRunTests(t *testing.T) {
+	t.Run("Passing", func(t *testing.T) {
+		r := &recorder{TB: t}
+		RunTests(r, []ParserTest[string]{
+			{Name: "Match", Input: "AB", Parser: String("A"), ExpectedMatch: "A", ExpectedOK: true, RemainingInput: "B"},
+			{Name: "No match", Input: "B", Parser: String("A"), RemainingInput: "B"},
+		})
+		assert.Empty(t, r.failures)
+	})
+	t.Run("Failures report the remaining input", func(t *testing.T) {
+		r := &recorder{TB: t}
+		RunTests(r, []ParserTest[string]{
+			{Name: "Wrong remaining", Input: "AB", Parser: String("A"), ExpectedMatch: "A", ExpectedOK: true},
+		})
+		if assert.Len(t, r.failures, 1) {
+			assert.Contains(t, r.failures[0], `remaining input "B"`)
+		}
+	})
+	t.Run("Failures report the error position", func(t *testing.T) {
+		r := &recorder{TB: t}
+		// The optional parser stops making progress at the start of the second line.
+		noProgress := WhileNot(Optional(Any(String("A"), String("\n"))), String("C"))
+		RunTests(r, []ParserTest[[]Match[string]]{
+			{Name: "Unexpected error", Input: "A\nB", Parser: noProgress, ExpectedOK: true},
+		})
+		assert.NotEmpty(t, r.failures)
+		for _, failure := range r.failures {
+			assert.Contains(t, failure, "error at line 2, column 1")
+		}
+	})
+	t.Run("Custom equality", func(t *testing.T) {
+		r := &recorder{TB: t}
+		sameText := func(expected, actual word) bool { return expected.Text == actual.Text }
+		RunTests(r, []ParserTest[word]{
+			{Name: "Ignores position", Input: "abc", Parser: positionedWord, ExpectedMatch: word{Text: "abc", Position: 5}, ExpectedOK: true, Equal: sameText},
+			{Name: "Compares text", Input: "abc", Parser: positionedWord, ExpectedMatch: word{Text: "abd"}, ExpectedOK: true, Equal: sameText},
+		})
+		if assert.Len(t, r.failures, 1) {
+			assert.Contains(t, r.failures[0], "abd")
+		}
+	})
+}
+
+func TestBenchTests(t *testing.T) {
+	result := testing.Benchmark(func(b *testing.B) {
+		BenchTests(b, []ParserTest[string]{
+			{Name: "String", Input: "AB", Parser: String("A"), ExpectedMatch: "A", ExpectedOK: true, RemainingInput: "B"},
+		})
+	})
+	assert.Positive(t, result.N)
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Liam White
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Number of random inputs generated from the runes of the inputs passed to CheckRollback and CheckLookahead.
+const generatedInputs = 200
+
+// CheckRollback fails the test if the parser does not restore the input, including any user state, to where it started
+// whenever it does not match or returns an error.
+//
+// The parser is run from every position of each input and from the start of inputs generated from them:
+// the empty string, every prefix and random strings made up of the same runes.
+func CheckRollback[T any](t testing.TB, parser Parser[T], inputs ...string) {
+	t.Helper()
+	checkInputs(inputs, func(in Input, s string) {
+		start := in.Checkpoint()
+		_, ok, err := parser(in)
+		if (!ok || err != nil) && in.Checkpoint() != start {
+			t.Errorf("parser did not roll back from position %d of %q (ok: %v, err: %v): %s", start.Position(), s, ok, err, in.Debug())
+		}
+	})
+}
+
+// CheckLookahead fails the test if a parser that should only peek at the input, such as EOF, ever advances it,
+// whether or not it matches. It runs the parser over the same inputs as CheckRollback.
+func CheckLookahead[T any](t testing.TB, parser Parser[T], inputs ...string) {
+	t.Helper()
+	checkInputs(inputs, func(in Input, s string) {
+		start := in.Checkpoint()
+		_, ok, err := parser(in)
+		if in.Checkpoint() != start {
+			t.Errorf("parser advanced from position %d of %q (ok: %v, err: %v): %s", start.Position(), s, ok, err, in.Debug())
+		}
+	})
+}
+
+// checkInputs calls check with an input positioned at every rune of each input, and at the start of each generated input.
+func checkInputs(inputs []string, check func(in Input, s string)) {
+	for _, s := range inputs {
+		for i := range s {
+			in := NewInput(s)
+			in.Take(i)
+			check(in, s)
+		}
+	}
+	for _, s := range generateInputs(inputs) {
+		check(NewInput(s), s)
+	}
+}
+
+// generateInputs returns the empty string, every prefix of the inputs and random strings made from their runes.
+func generateInputs(inputs []string) []string {
+	generated := []string{""}
+	var alphabet []rune
+	for _, s := range inputs {
+		for i, r := range s {
+			if i > 0 {
+				generated = append(generated, s[:i])
+			}
+			if !slices.Contains(alphabet, r) {
+				alphabet = append(alphabet, r)
+			}
+		}
+	}
+	if len(alphabet) == 0 {
+		return generated
+	}
+
+	random := rand.New(rand.NewPCG(1, 2))
+	for range generatedInputs {
+		var s strings.Builder
+		for range 1 + random.IntN(16) {
+			s.WriteRune(alphabet[random.IntN(len(alphabet))])
+		}
+		generated = append(generated, s.String())
+	}
+	return generated
+}
@@ -0,0 +1,286 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Clock is a time of day with an optional time zone.
+type Clock struct {
+	Hour, Minute, Second, Nanosecond int
+	// Location is the time zone given after the time, or nil if there was none.
+	Location *time.Location
+}
+
+// On returns the clock time on the same day as the date. The clock's location is used if it has one, otherwise the date's.
+func (c Clock) On(date time.Time) time.Time {
+	loc := c.Location
+	if loc == nil {
+		loc = date.Location()
+	}
+	year, month, day := date.Date()
+	return time.Date(year, month, day, c.Hour, c.Minute, c.Second, c.Nanosecond, loc)
+}
+
+// TimeOfDay parses a time in either 24 hour (14:30, 14:30:05, 14:30:05.123) or 12 hour (2pm, 2:30 PM, 2:30:05 p.m.) format,
+// or one of noon, midday or midnight. The time may be followed by a time zone, either Z, an offset (+02:00, -0530, +02),
+// UTC, GMT or an IANA name (Europe/London), optionally separated by whitespace.
+var TimeOfDay = func(in Input) (Clock, bool, error) {
+	start := in.Checkpoint()
+	clock, ok, err := Longest(clock24, clock12, clockNamed)(in)
+	if err != nil || !ok {
+		return Clock{}, false, err
+	}
+
+	m, ok, err := SequenceOf2(OptionalInlineWhitespace, timeZone)(in)
+	if err != nil {
+		in.Restore(start)
+		return Clock{}, false, err
+	}
+	if ok {
+		_, clock.Location = m.Values()
+	}
+	return clock, true, nil
+}
+
+// H:mm, HH:mm, HH:mm:ss or HH:mm:ss.fraction
+var clock24 = func(in Input) (Clock, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf3(clockHour, isoPrefixed(":", isoNumber(2)), Optional(SequenceOf2(isoPrefixed(":", isoNumber(2)), Optional(isoFraction))))(in)
+	if err != nil || !ok {
+		return Clock{}, false, err
+	}
+	hour, minute, seconds := m.Values()
+	clock := Clock{Hour: hour, Minute: minute}
+	if seconds.Ok() {
+		second, fraction := seconds.Values().Values()
+		clock.Second, clock.Nanosecond = second, fraction.Values()
+	}
+	if err := clock.validate(start); err != nil {
+		in.Restore(start)
+		return Clock{}, false, err
+	}
+	return clock, true, nil
+}
+
+// h, h:mm or h:mm:ss followed by am or pm, optionally with periods and whitespace.
+var clock12 = func(in Input) (Clock, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf5(
+		clockHour,
+		Optional(isoPrefixed(":", isoNumber(2))),
+		Optional(isoPrefixed(":", isoNumber(2))),
+		OptionalInlineWhitespace,
+		oneOf(map[string]bool{"am": false, "a.m.": false, "pm": true, "p.m.": true}),
+	)(in)
+	if err != nil || !ok {
+		return Clock{}, false, err
+	}
+	if !wordEnd(in) {
+		in.Restore(start)
+		return Clock{}, false, nil
+	}
+	hour, minute, second, _, pm := m.Values()
+	// Other hours are not 12 hour times, though they may be the start of something else, e.g. 0:30 then pm.
+	if hour < 1 || hour > 12 {
+		in.Restore(start)
+		return Clock{}, false, nil
+	}
+	// 12am is midnight and 12pm is noon.
+	hour %= 12
+	if pm {
+		hour += 12
+	}
+	clock := Clock{Hour: hour, Minute: minute.Values(), Second: second.Values()}
+	if err := clock.validate(start); err != nil {
+		in.Restore(start)
+		return Clock{}, false, err
+	}
+	return clock, true, nil
+}
+
+var clockNamed = func(in Input) (Clock, bool, error) {
+	start := in.Checkpoint()
+	clock, ok, err := oneOf(map[string]Clock{"noon": {Hour: 12}, "midday": {Hour: 12}, "midnight": {}})(in)
+	if err != nil || !ok {
+		return Clock{}, false, err
+	}
+	if !wordEnd(in) {
+		in.Restore(start)
+		return Clock{}, false, nil
+	}
+	return clock, true, nil
+}
+
+// One or two digit hour.
+var clockHour = numberWithDigits(1, 2)
+
+func (c Clock) validate(start Checkpoint) error {
+	switch {
+	case c.Hour > 23:
+		return fmt.Errorf("failed to parse time: hour %d out of range at position %d", c.Hour, start.Position())
+	case c.Minute > 59:
+		return fmt.Errorf("failed to parse time: minute %d out of range at position %d", c.Minute, start.Position())
+	case c.Second > 59:
+		return fmt.Errorf("failed to parse time: second %d out of range at position %d", c.Second, start.Position())
+	}
+	return nil
+}
+
+// wordEnd reports whether the input is not followed by a letter.
+func wordEnd(in Input) bool {
+	r, size := PeekRune(in)
+	return size == 0 || !unicode.IsLetter(r)
+}
+
+// Areas of IANA time zone names, a name in one of these areas that does not exist is an error rather than not a time zone.
+var ianaAreas = []string{"Africa", "America", "Antarctica", "Arctic", "Asia", "Atlantic", "Australia", "Etc", "Europe", "Indian", "Pacific"}
+
+// Z, an offset, UTC, GMT or an IANA name.
+var timeZone = func(in Input) (*time.Location, bool, error) {
+	start := in.Checkpoint()
+	loc, ok, err := Longest(isoOffset, oneOf(map[string]*time.Location{"utc": time.UTC, "gmt": time.UTC}), ianaZone)(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if !wordEnd(in) {
+		in.Restore(start)
+		return nil, false, nil
+	}
+	return loc, true, nil
+}
+
+// Area/Location, e.g. Europe/London or America/Argentina/Buenos_Aires.
+var ianaZone = func(in Input) (*time.Location, bool, error) {
+	start := in.Checkpoint()
+	part := OneOrMore(Any(Letter, RuneIn("_-+0123456789")))
+	m, ok, err := SequenceOf2(StringFrom(part), StringFrom(SkipOneOrMore(SequenceOf2(Rune('/'), StringFrom(part)))))(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	area, rest := m.Values()
+	if !slices.Contains(ianaAreas, area) {
+		in.Restore(start)
+		return nil, false, nil
+	}
+	loc, err := time.LoadLocation(area + rest)
+	if err != nil {
+		in.Restore(start)
+		return nil, false, fmt.Errorf("failed to parse time zone: %w at position %d", err, start.Position())
+	}
+	return loc, true, nil
+}
+
+// DateTime parses a date and a time of day in either order, e.g. 2024-02-01 14:30, 2024-02-01T2pm, tomorrow at noon,
+// next friday, 9am, 3rd March 2024 14:30 or 14:30 UTC on the 3rd of next month. The date may be an ISO 8601 date, a date
+// written with the month's name as accepted by DayMonthYear, or any phrase supported by RelativeDate, which is resolved against
+// the reference time and location. Numeric dates such as 03/04/2024 are not accepted as the order of the day and month is ambiguous.
+// The time is in the given location unless it has a time zone.
+// The date and time may be separated by T, whitespace, a comma, "at" (date first) or "on" (time first).
+func DateTime(ref time.Time, loc *time.Location) Parser[time.Time] {
+	date := Longest(isoDay(loc), validDate(writtenDate, loc), RelativeDate(ref, loc))
+	dateFirst := func(in Input) (time.Time, bool, error) {
+		separator := Longest(
+			Rune('T'),
+			StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace),
+			StringFrom(InlineWhitespace, StringInsensitive("at"), InlineWhitespace),
+			InlineWhitespace,
+		)
+		m, ok, err := SequenceOf3(date, separator, TimeOfDay)(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		d, _, clock := m.Values()
+		return clock.On(d), true, nil
+	}
+	timeFirst := func(in Input) (time.Time, bool, error) {
+		separator := Longest(
+			StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace),
+			StringFrom(InlineWhitespace, StringInsensitive("on"), InlineWhitespace),
+			InlineWhitespace,
+		)
+		m, ok, err := SequenceOf3(TimeOfDay, separator, date)(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		clock, _, d := m.Values()
+		return clock.On(d), true, nil
+	}
+	return Longest(dateFirst, timeFirst)
+}
+
+// isoDay parses an ISO 8601 date with day precision, returning midnight in the location.
+func isoDay(loc *time.Location) Parser[time.Time] {
+	return func(in Input) (time.Time, bool, error) {
+		start := in.Checkpoint()
+		d, ok, err := isoDate(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		if d.precision != DayPrecision {
+			in.Restore(start)
+			return time.Time{}, false, nil
+		}
+		date, err := d.resolve(isoTimeFields{}, loc)
+		if err != nil {
+			in.Restore(start)
+			return time.Time{}, false, err
+		}
+		return date, true, nil
+	}
+}
+
+// FormatTimeOfDay formats the clock in 24 hour format, e.g. 14:30, 14:30:05 or 14:30:05.5, the inverse of TimeOfDay.
+// Seconds are only written when they are not zero. The location is written as UTC, its IANA name or its offset.
+func FormatTimeOfDay(c Clock) string {
+	s := fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+	if c.Second != 0 || c.Nanosecond != 0 {
+		s += fmt.Sprintf(":%02d", c.Second) + formatFraction(c.Nanosecond)
+	}
+	if c.Location == nil {
+		return s
+	}
+	return s + formatZone(c.On(time.Date(2000, time.January, 1, 0, 0, 0, 0, c.Location)))
+}
+
+// FormatDateTime formats the time as its date and time of day separated by a space, e.g. 2024-02-01 14:30 Europe/London,
+// which DateTime parses back to the same instant.
+func FormatDateTime(t time.Time) string {
+	// The zone is formatted from the time itself as its offset depends on the date.
+	clock := FormatTimeOfDay(Clock{Hour: t.Hour(), Minute: t.Minute(), Second: t.Second(), Nanosecond: t.Nanosecond()})
+	return FormatYearMonthDay(t) + " " + clock + formatZone(t)
+}
+
+// formatZone formats the location of the time as UTC, an IANA name in one of the ianaAreas or the offset at that time.
+// The offset is also used when the name would be ambiguous, e.g. 01:30 as the clocks go back.
+func formatZone(t time.Time) string {
+	name := t.Location().String()
+	if name == "UTC" {
+		return " UTC"
+	}
+	year, month, day := t.Date()
+	wall := time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	if area, _, ok := strings.Cut(name, "/"); ok && slices.Contains(ianaAreas, area) && wall.Equal(t) {
+		return " " + name
+	}
+	return t.Format("-07:00")
+}
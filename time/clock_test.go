@@ -0,0 +1,313 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeOfDay(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	tests := []ParserTest[Clock]{
+		{
+			Name:          "24 hour",
+			Input:         "14:30",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14, Minute: 30},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "24 hour with single digit hour",
+			Input:         "9:05",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 9, Minute: 5},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "24 hour with fractional seconds",
+			Input:         "14:30:05.123",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14, Minute: 30, Second: 5, Nanosecond: 123000000},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "12 hour",
+			Input:         "2pm",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "12 hour with minutes and periods",
+			Input:         "2:30 P.M.",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14, Minute: 30},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "12am is midnight",
+			Input:         "12am",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "12pm is noon",
+			Input:         "12 pm",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 12},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "noon",
+			Input:         "noon",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 12},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "midnight",
+			Input:         "Midnight",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "UTC",
+			Input:         "14:30 UTC",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14, Minute: 30, Location: time.UTC},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Z",
+			Input:         "14:30Z",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 14, Minute: 30, Location: time.UTC},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "offset",
+			Input:         "9am +02:00",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 9, Location: time.FixedZone("", 2*60*60)},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "IANA name",
+			Input:         "9am Europe/London",
+			Parser:        TimeOfDay,
+			ExpectedMatch: Clock{Hour: 9, Location: london},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "unknown IANA name",
+			Input:          "9am Europe/Londn",
+			Parser:         TimeOfDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "9am Europe/Londn",
+		},
+		{
+			Name:           "not a time zone",
+			Input:          "9am and/or 10am",
+			Parser:         TimeOfDay,
+			ExpectedMatch:  Clock{Hour: 9},
+			ExpectedOK:     true,
+			RemainingInput: " and/or 10am",
+		},
+		{
+			Name:           "zone must be a whole word",
+			Input:          "14:30 Zoom call",
+			Parser:         TimeOfDay,
+			ExpectedMatch:  Clock{Hour: 14, Minute: 30},
+			ExpectedOK:     true,
+			RemainingInput: " Zoom call",
+		},
+		{
+			Name:           "invalid hour",
+			Input:          "25:00",
+			Parser:         TimeOfDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "25:00",
+		},
+		{
+			Name:           "invalid 12 hour",
+			Input:          "13pm",
+			Parser:         TimeOfDay,
+			ExpectedOK:     false,
+			RemainingInput: "13pm",
+		},
+		{
+			Name:           "invalid 12 hour leaves a 24 hour time",
+			Input:          "0:30pm",
+			Parser:         TimeOfDay,
+			ExpectedMatch:  Clock{Minute: 30},
+			ExpectedOK:     true,
+			RemainingInput: "pm",
+		},
+		{
+			Name:           "zone followed by non-ASCII punctuation",
+			Input:          "9am UTC—ish",
+			Parser:         TimeOfDay,
+			ExpectedMatch:  Clock{Hour: 9, Location: time.UTC},
+			ExpectedOK:     true,
+			RemainingInput: "—ish",
+		},
+		{
+			Name:           "meridiem must be a whole word",
+			Input:          "2 amps",
+			Parser:         TimeOfDay,
+			ExpectedOK:     false,
+			RemainingInput: "2 amps",
+		},
+		{
+			Name:           "number without a time",
+			Input:          "2024",
+			Parser:         TimeOfDay,
+			ExpectedOK:     false,
+			RemainingInput: "2024",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestDateTime(t *testing.T) {
+	// Wednesday the 31st of January 2024.
+	ref := time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)
+	tokyo := time.FixedZone("JST", 9*60*60)
+	parser := DateTime(ref, time.UTC)
+
+	tests := []ParserTest[time.Time]{
+		{
+			Name:          "ISO date and time",
+			Input:         "2024-02-01 14:30",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.February, 1, 14, 30, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ISO date with T",
+			Input:         "2024-02-01T2pm",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.February, 1, 14, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "relative date at a time",
+			Input:         "tomorrow at noon",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.February, 1, 12, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "relative date and time separated by a comma",
+			Input:         "next friday, 9am",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.February, 2, 9, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "time first with zone",
+			Input:         "14:30 UTC on the 3rd of next month",
+			Parser:        DateTime(ref, tokyo),
+			ExpectedMatch: time.Date(2024, time.February, 3, 14, 30, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "time in the given location",
+			Input:         "9am tomorrow",
+			Parser:        DateTime(ref, tokyo),
+			ExpectedMatch: time.Date(2024, time.February, 1, 9, 0, 0, 0, tokyo),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "written date and time",
+			Input:         "3rd March 2024 14:30",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.March, 3, 14, 30, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "time on a written date",
+			Input:         "14:30 on 1 March 2024",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.March, 1, 14, 30, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "written date month first in the given location",
+			Input:         "March 3, 2024 at 9am",
+			Parser:        DateTime(ref, tokyo),
+			ExpectedMatch: time.Date(2024, time.March, 3, 9, 0, 0, 0, tokyo),
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "numeric date",
+			Input:          "03/04/2024 14:30",
+			Parser:         parser,
+			ExpectedOK:     false,
+			RemainingInput: "03/04/2024 14:30",
+		},
+		{
+			Name:           "invalid written date",
+			Input:          "30th February 2024 14:30",
+			Parser:         parser,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "30th February 2024 14:30",
+		},
+		{
+			Name:           "date without a time",
+			Input:          "2024-02-01",
+			Parser:         parser,
+			ExpectedOK:     false,
+			RemainingInput: "2024-02-01",
+		},
+		{
+			Name:           "reduced precision date",
+			Input:          "2024-02 14:30",
+			Parser:         parser,
+			ExpectedOK:     false,
+			RemainingInput: "2024-02 14:30",
+		},
+		{
+			Name:           "invalid date",
+			Input:          "2024-02-30 14:30",
+			Parser:         parser,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-02-30 14:30",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestClockOn(t *testing.T) {
+	date := time.Date(2024, time.February, 1, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.February, 1, 9, 30, 0, 0, time.UTC), Clock{Hour: 9, Minute: 30}.On(date))
+	tokyo := time.FixedZone("JST", 9*60*60)
+	assert.Equal(t, time.Date(2024, time.February, 1, 9, 30, 0, 0, tokyo), Clock{Hour: 9, Minute: 30, Location: tokyo}.On(date))
+}
@@ -0,0 +1,457 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// CronSchedule is a parsed cron expression, see Cron.
+type CronSchedule struct {
+	// Bitsets of the values each field matches, e.g. bit 5 of minutes is set if the schedule fires at 5 minutes past.
+	seconds, minutes, hours, days, months, weekdays uint64
+	// Whether the day of month and day of week fields are unrestricted, see Next.
+	daysAny, weekdaysAny bool
+	// Interval of an @every schedule.
+	every Period
+}
+
+// Number of years Next will search before giving up on a schedule that never fires, e.g. the 30th of February.
+const maxCronYears = 9
+
+// Next returns up to n fire times strictly after the given time, in the location of after.
+// As in most cron implementations, when both the day of month and day of week fields are restricted the schedule fires
+// when either matches, otherwise both must match. An @every schedule fires at fixed intervals starting from after.
+// Fewer than n times are returned if the schedule never fires, e.g. on the 30th of February.
+func (s CronSchedule) Next(after time.Time, n int) []time.Time {
+	var times []time.Time
+	for len(times) < n {
+		next, ok := s.next(after)
+		if !ok {
+			break
+		}
+		times = append(times, next)
+		after = next
+	}
+	return times
+}
+
+func (s CronSchedule) next(after time.Time) (time.Time, bool) {
+	if !s.every.IsZero() {
+		return s.every.AddTo(after), true
+	}
+
+	loc := after.Location()
+	t := after.Truncate(time.Second).Add(time.Second)
+	limit := t.Year() + maxCronYears
+	// advance moves t forward to the start of the next unit, guarding against wall clock times that repeat around daylight saving transitions.
+	advance := func(next time.Time, fallback time.Duration) {
+		if !next.After(t) {
+			next = t.Add(fallback)
+		}
+		t = next
+	}
+	for t.Year() <= limit {
+		switch {
+		case !has(s.months, int(t.Month())):
+			advance(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc), 24*time.Hour)
+		case !s.dayMatches(t):
+			advance(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc), time.Hour)
+		case !has(s.hours, t.Hour()):
+			advance(time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc), time.Hour)
+		case !has(s.minutes, t.Minute()):
+			advance(t.Truncate(time.Minute).Add(time.Minute), time.Minute)
+		case !has(s.seconds, t.Second()):
+			advance(t.Add(time.Second), time.Second)
+		default:
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s CronSchedule) dayMatches(t time.Time) bool {
+	day, weekday := has(s.days, t.Day()), has(s.weekdays, int(t.Weekday()))
+	if s.daysAny || s.weekdaysAny {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+func has(bits uint64, n int) bool {
+	return bits&(1<<n) != 0
+}
+
+// CronFieldError is returned by Cron when a field is syntactically valid but its value is not, e.g. minute 75.
+type CronFieldError struct {
+	Field    string
+	Value    string
+	Position int
+	Reason   string
+}
+
+func (e *CronFieldError) Error() string {
+	return fmt.Sprintf("invalid cron %s %s at position %d: %s", e.Field, e.Value, e.Position, e.Reason)
+}
+
+// Cron parses a cron expression with either 5 fields (minute hour day-of-month month day-of-week)
+// or 6 fields (second minute hour day-of-month month day-of-week), separated by whitespace.
+//
+// Each field is a comma separated list of *, a value, a range (1-5) or any of those with a step (*/15, 1-30/2, 5/10).
+// Months and days of the week may be names (JAN, MON, monday), days of the week may be 0 to 7 where both 0 and 7 are sunday,
+// and ? is the same as * in the day fields.
+//
+// The macros @yearly, @annually, @monthly, @weekly, @daily, @midnight and @hourly are supported,
+// as well as @every followed by a Duration, e.g. @every 1h30m.
+var Cron = func(in Input) (CronSchedule, bool, error) {
+	schedule, ok, err := cronMacro(in)
+	if err != nil || ok {
+		return schedule, ok, err
+	}
+	return cronExpression(in)
+}
+
+// cronExpression parses the fields of a cron expression.
+func cronExpression(in Input) (CronSchedule, bool, error) {
+	start := in.Checkpoint()
+	var schedule CronSchedule
+	fields, ok, err := Longest(cronFields(6), cronFields(5))(in)
+	if err != nil || !ok {
+		return CronSchedule{}, false, err
+	}
+	specs := cronFieldSpecs
+	if len(fields) == 5 {
+		specs = specs[1:]
+		schedule.seconds = 1
+	}
+	targets := []*uint64{&schedule.seconds, &schedule.minutes, &schedule.hours, &schedule.days, &schedule.months, &schedule.weekdays}
+	targets = targets[len(targets)-len(fields):]
+	for i, field := range fields {
+		bits, err := specs[i].compile(field)
+		if err != nil {
+			in.Restore(start)
+			return CronSchedule{}, false, err
+		}
+		*targets[i] = bits
+	}
+	schedule.daysAny, schedule.weekdaysAny = fields[len(fields)-3].any, fields[len(fields)-1].any
+	return schedule, true, nil
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMacro = func(in Input) (CronSchedule, bool, error) {
+	start := in.Checkpoint()
+	name, ok, err := StringFrom(Rune('@'), StringFrom(SkipOneOrMore(RuneInRanges(unicode.Letter))))(in)
+	if err != nil || !ok {
+		return CronSchedule{}, false, err
+	}
+
+	if strings.EqualFold(name, "@every") {
+		m, ok, err := SequenceOf2(InlineWhitespace, Duration)(in)
+		if err != nil || !ok {
+			in.Restore(start)
+			return CronSchedule{}, false, err
+		}
+		_, every := m.Values()
+		if every.IsZero() {
+			in.Restore(start)
+			return CronSchedule{}, false, &CronFieldError{Field: "interval", Value: "0", Position: start.Position(), Reason: "must be greater than zero"}
+		}
+		return CronSchedule{every: every}, true, nil
+	}
+
+	expression, ok := cronMacros[strings.ToLower(name)]
+	if !ok {
+		in.Restore(start)
+		return CronSchedule{}, false, nil
+	}
+	schedule, _, err := cronExpression(NewInput(expression))
+	return schedule, true, err
+}
+
+// cronValue is a number or a name in a cron field.
+type cronValue struct {
+	n        int
+	kind     cronNameKind
+	position int
+}
+
+type cronNameKind int
+
+const (
+	cronNumber cronNameKind = iota
+	cronWeekdayName
+	cronMonthName
+)
+
+// cronItem is one element of the comma separated list in a cron field.
+type cronItem struct {
+	any      bool
+	from, to cronValue
+	ranged   bool
+	step     cronValue
+	stepped  bool
+}
+
+type cronField struct {
+	items []cronItem
+	// Whether the field starts with * or ?.
+	any bool
+}
+
+// cronDigits parses the digits of a number in a cron field, which are generated small enough to be valid in most fields.
+var cronDigits = WithGenerator(StringFrom(SkipOneOrMore(isoDigit)), func(r *rand.Rand) string { return strconv.Itoa(1 + r.IntN(12)) })
+
+var cronNumberOrName = func(in Input) (cronValue, bool, error) {
+	position := in.Checkpoint().Position()
+	digits, ok, err := cronDigits(in)
+	if err != nil {
+		return cronValue{}, false, err
+	}
+	if ok {
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			// Too large, which is reported as out of range when the field is compiled.
+			n = math.MaxInt32
+		}
+		return cronValue{n: n, position: position}, true, nil
+	}
+	day, ok, err := DayOfWeek(in)
+	if err != nil {
+		return cronValue{}, false, err
+	}
+	if ok {
+		return cronValue{n: int(day), kind: cronWeekdayName, position: position}, true, nil
+	}
+	month, ok, err := MonthOfYear(in)
+	if err != nil || !ok {
+		return cronValue{}, false, err
+	}
+	return cronValue{n: int(month), kind: cronMonthName, position: position}, true, nil
+}
+
+var cronItemParser = func(in Input) (cronItem, bool, error) {
+	var item cronItem
+	anyValue, _, err := Optional(RuneIn("*?"))(in)
+	if err != nil {
+		return cronItem{}, false, err
+	}
+	if anyValue.Ok() {
+		item.any = true
+	} else {
+		m, ok, err := SequenceOf2(cronNumberOrName, Optional(SequenceOf2(Rune('-'), cronNumberOrName)))(in)
+		if err != nil || !ok {
+			return cronItem{}, false, err
+		}
+		var to Match[Tuple2[string, cronValue]]
+		item.from, to = m.Values()
+		if to.Ok() {
+			_, item.to = to.Values().Values()
+			item.ranged = true
+		}
+	}
+
+	step, _, err := Optional(SequenceOf2(Rune('/'), cronNumberOrName))(in)
+	if err != nil {
+		return cronItem{}, false, err
+	}
+	if step.Ok() {
+		_, item.step = step.Values().Values()
+		item.stepped = true
+	}
+	return item, true, nil
+}
+
+var cronFieldParser = func(in Input) (cronField, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf2(cronItemParser, ZeroOrMore(SequenceOf2(Rune(','), cronItemParser)))(in)
+	if err != nil || !ok {
+		return cronField{}, false, err
+	}
+	first, rest := m.Values()
+	field := cronField{items: []cronItem{first}, any: first.any}
+	for _, r := range rest {
+		_, item := r.Values()
+		field.items = append(field.items, item)
+	}
+	// Fields must be followed by whitespace or the end of the input.
+	if r, size := PeekRune(in); size > 0 && !unicode.IsSpace(r) {
+		in.Restore(start)
+		return cronField{}, false, nil
+	}
+	return field, true, nil
+}
+
+// cronFields parses exactly n whitespace separated fields.
+func cronFields(n int) Parser[[]cronField] {
+	return func(in Input) ([]cronField, bool, error) {
+		m, ok, err := SequenceOf2(cronFieldParser, Times(n-1, SequenceOf2(InlineWhitespace, cronFieldParser)))(in)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		first, rest := m.Values()
+		fields := []cronField{first}
+		for _, r := range rest {
+			_, field := r.Values()
+			fields = append(fields, field)
+		}
+		return fields, true, nil
+	}
+}
+
+type cronFieldSpec struct {
+	name     string
+	min, max int
+	names    cronNameKind
+}
+
+var cronFieldSpecs = []cronFieldSpec{
+	{name: "second", min: 0, max: 59},
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: cronMonthName},
+	// 7 is also sunday.
+	{name: "day of week", min: 0, max: 7, names: cronWeekdayName},
+}
+
+// compile returns the bitset of values the field matches.
+func (spec cronFieldSpec) compile(field cronField) (uint64, error) {
+	fail := func(value cronValue, text, reason string) (uint64, error) {
+		return 0, &CronFieldError{Field: spec.name, Value: text, Position: value.position, Reason: reason}
+	}
+	check := func(value cronValue) error {
+		if value.kind != cronNumber && value.kind != spec.names {
+			_, err := fail(value, strconv.Itoa(value.n), "names are not allowed")
+			return err
+		}
+		if value.n < spec.min || value.n > spec.max {
+			_, err := fail(value, strconv.Itoa(value.n), fmt.Sprintf("must be between %d and %d", spec.min, spec.max))
+			return err
+		}
+		return nil
+	}
+
+	var bits uint64
+	for _, item := range field.items {
+		from, to := spec.min, spec.max
+		if !item.any {
+			if err := check(item.from); err != nil {
+				return 0, err
+			}
+			from, to = item.from.n, item.from.n
+			if item.ranged {
+				if err := check(item.to); err != nil {
+					return 0, err
+				}
+				if item.to.n < item.from.n {
+					return fail(item.from, fmt.Sprintf("%d-%d", item.from.n, item.to.n), "range must not be descending")
+				}
+				to = item.to.n
+			} else if item.stepped {
+				// A value with a step runs until the end of the field, e.g. 5/10 is 5-59/10.
+				to = spec.max
+			}
+		}
+		step := 1
+		if item.stepped {
+			if item.step.kind != cronNumber || item.step.n < 1 || item.step.n > spec.max {
+				return fail(item.step, strconv.Itoa(item.step.n), fmt.Sprintf("step must be between 1 and %d", spec.max))
+			}
+			step = item.step.n
+		}
+		for v := from; v <= to; v += step {
+			bits |= 1 << v
+		}
+	}
+	if spec.names == cronWeekdayName && has(bits, 7) {
+		bits = bits&^(1<<7) | 1
+	}
+	return bits, nil
+}
+
+// FormatCron formats the schedule as a cron expression, the inverse of Cron. Schedules that only fire at 0 seconds
+// are written with 5 fields and @every schedules as @every followed by an ISO 8601 duration.
+// Fields are written as * when they match every value, otherwise as a comma separated list of numbers and ranges.
+func FormatCron(s CronSchedule) string {
+	if !s.every.IsZero() {
+		return "@every " + FormatISODuration(s.every)
+	}
+	fields := []string{
+		formatCronField(s.seconds, cronFieldSpecs[0], false),
+		formatCronField(s.minutes, cronFieldSpecs[1], false),
+		formatCronField(s.hours, cronFieldSpecs[2], false),
+		formatCronField(s.days, cronFieldSpecs[3], s.daysAny),
+		formatCronField(s.months, cronFieldSpecs[4], false),
+		formatCronField(s.weekdays, cronFieldSpecs[5], s.weekdaysAny),
+	}
+	if s.seconds == 1 {
+		fields = fields[1:]
+	}
+	return strings.Join(fields, " ")
+}
+
+// formatCronField formats the bitset of a field, where any is whether a day field started with *.
+func formatCronField(bits uint64, spec cronFieldSpec, any bool) string {
+	last := spec.max
+	if spec.names == cronWeekdayName {
+		// 7 is folded into sunday when parsed so it is never set.
+		last = 6
+	}
+	dayField := spec.name == "day of month" || spec.name == "day of week"
+	if full := uint64(1)<<(last+1) - uint64(1)<<spec.min; bits == full && (any || !dayField) {
+		return "*"
+	}
+
+	var items []string
+	if any {
+		// A * with a step beyond the end of the field matches only the first value, which a * always includes.
+		items = append(items, fmt.Sprintf("*/%d", spec.max))
+		bits &^= 1 << spec.min
+	}
+	for v := spec.min; v <= last; v++ {
+		if !has(bits, v) {
+			continue
+		}
+		end := v
+		for end < last && has(bits, end+1) {
+			end++
+		}
+		if end == v {
+			items = append(items, strconv.Itoa(v))
+		} else {
+			items = append(items, fmt.Sprintf("%d-%d", v, end))
+		}
+		v = end
+	}
+	return strings.Join(items, ",")
+}
@@ -0,0 +1,170 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func cron(t *testing.T, expression string) CronSchedule {
+	schedule, ok, err := Cron(core.NewInput(expression))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	return schedule
+}
+
+func TestCron(t *testing.T) {
+	tests := []ParserTest[CronSchedule]{
+		{
+			Name:          "names are the same as numbers",
+			Input:         "0 9 * JAN-MAR MON-FRI",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "0 9 * 1-3 1-5"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "7 is sunday",
+			Input:         "0 0 * * 7",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "0 0 * * sunday"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "question mark is any",
+			Input:         "0 0 ? * 1",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "0 0 * * 1"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "lists and steps",
+			Input:         "0,30 */6 1-15/7 * *",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "0,30 0,6,12,18 1,8,15 * *"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "six fields",
+			Input:         "30 0 9 * * *",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "30 0 9 * * *"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "macro",
+			Input:         "@daily",
+			Parser:        Cron,
+			ExpectedMatch: cron(t, "0 0 * * *"),
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "remaining input",
+			Input:          "0 9 * * MON water the plants",
+			Parser:         Cron,
+			ExpectedMatch:  cron(t, "0 9 * * 1"),
+			ExpectedOK:     true,
+			RemainingInput: " water the plants",
+		},
+		{
+			Name:           "followed by non-ASCII whitespace",
+			Input:          "0 9 * * MON\u00a0water the plants",
+			Parser:         Cron,
+			ExpectedMatch:  cron(t, "0 9 * * 1"),
+			ExpectedOK:     true,
+			RemainingInput: "\u00a0water the plants",
+		},
+		{
+			Name:           "invalid minute",
+			Input:          "75 9 * * *",
+			Parser:         Cron,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "75 9 * * *",
+		},
+		{
+			Name:           "descending range",
+			Input:          "0 9 * * 5-1",
+			Parser:         Cron,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "0 9 * * 5-1",
+		},
+		{
+			Name:           "too few fields",
+			Input:          "0 9 * *",
+			Parser:         Cron,
+			ExpectedOK:     false,
+			RemainingInput: "0 9 * *",
+		},
+		{
+			Name:           "unknown macro",
+			Input:          "@someone",
+			Parser:         Cron,
+			ExpectedOK:     false,
+			RemainingInput: "@someone",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestCronFieldError(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		expected CronFieldError
+	}{
+		{"0 24 * * *", CronFieldError{Field: "hour", Value: "24", Position: 2, Reason: "must be between 0 and 23"}},
+		{"0 0 0 * *", CronFieldError{Field: "day of month", Value: "0", Position: 4, Reason: "must be between 1 and 31"}},
+		{"0 0 * MON *", CronFieldError{Field: "month", Value: "1", Position: 6, Reason: "names are not allowed"}},
+		{"0 0 * * 1-5/0", CronFieldError{Field: "day of week", Value: "0", Position: 12, Reason: "step must be between 1 and 7"}},
+		{"0 0 * * FRI-MON", CronFieldError{Field: "day of week", Value: "5-1", Position: 8, Reason: "range must not be descending"}},
+		{"@every 0s", CronFieldError{Field: "interval", Value: "0", Position: 0, Reason: "must be greater than zero"}},
+	} {
+		t.Run(test.input, func(t *testing.T) {
+			_, ok, err := Cron(core.NewInput(test.input))
+			assert.False(t, ok)
+			assert.Equal(t, &test.expected, err)
+		})
+	}
+}
+
+func TestCronScheduleNext(t *testing.T) {
+	// Wednesday the 31st of January 2024.
+	after := time.Date(2024, time.January, 31, 9, 30, 0, 0, time.UTC)
+	at := func(month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(2024, month, day, hour, minute, second, 0, time.UTC)
+	}
+
+	assert.Equal(t, []time.Time{at(time.January, 31, 10, 0, 0), at(time.January, 31, 11, 0, 0)}, cron(t, "@hourly").Next(after, 2))
+	assert.Equal(t, []time.Time{at(time.February, 1, 9, 0, 0), at(time.February, 2, 9, 0, 0), at(time.February, 5, 9, 0, 0)}, cron(t, "0 9 * * MON-FRI").Next(after, 3))
+	assert.Equal(t, []time.Time{at(time.January, 31, 9, 30, 15), at(time.January, 31, 9, 30, 45)}, cron(t, "15/30 * * * * *").Next(after, 2))
+	assert.Equal(t, []time.Time{at(time.February, 29, 0, 0, 0), time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)}, cron(t, "0 0 29 2 *").Next(after, 2))
+	assert.Equal(t, []time.Time{at(time.January, 31, 11, 0, 0), at(time.January, 31, 12, 30, 0)}, cron(t, "@every 1h30m").Next(after, 2))
+	assert.Empty(t, cron(t, "0 0 30 2 *").Next(after, 1))
+
+	// Both day fields restricted fires on either, the 1st of the month or a monday.
+	assert.Equal(t, []time.Time{at(time.February, 1, 0, 0, 0), at(time.February, 5, 0, 0, 0), at(time.February, 12, 0, 0, 0)}, cron(t, "0 0 1 * MON").Next(after, 3))
+	// Only one restricted must match both, mondays in february.
+	assert.Equal(t, []time.Time{at(time.February, 5, 0, 0, 0)}, cron(t, "0 0 * FEB MON").Next(after, 1))
+
+	// Fire times are in the location of after.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	assert.Equal(t, []time.Time{time.Date(2024, time.February, 1, 9, 0, 0, 0, tokyo)}, cron(t, "0 9 * * *").Next(after.In(tokyo), 1))
+}
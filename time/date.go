@@ -17,59 +17,51 @@ package time
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	. "github.com/liamawhite/parse/core"
 )
 
-// Parse a date in the format yyyy-MM-dd.
-var YearMonthDay = func(in Input) (time.Time, bool, error) {
-	// Create parsers for year, month and day.
-	year := StringFrom(Times(4, Digit))
-	month := StringFrom(RuneIn("01"), Digit)
-	day := StringFrom(RuneIn("0123"), Digit)
+// DateError is returned when a date component is out of range, has the wrong ordinal suffix or the date does not exist.
+type DateError struct {
+	Component string
+	Value     string
+	Position  int
+	Reason    string
+}
 
-	// Create string parser for yyyy-MM-dd.
-	date := StringFrom(All(year, Rune('-'), month, Rune('-'), day))
+func (e *DateError) Error() string {
+	return fmt.Sprintf("invalid %s %s at position %d: %s", e.Component, e.Value, e.Position, e.Reason)
+}
 
-	s, ok, err := date(in)
+// Parse a date in the format yyyy-MM-dd.
+var YearMonthDay = func(in Input) (time.Time, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf5(isoNumber(4), Rune('-'), positioned(isoNumber(2)), Rune('-'), positioned(isoNumber(2)))(in)
 	if err != nil || !ok {
 		return time.Time{}, false, err
 	}
-
-	// Parse the date.
-	match, err := time.Parse("2006-01-02", s)
-	if err != nil {
-		return time.Time{}, false, fmt.Errorf("failed to parse date: %w", err)
+	year, _, month, _, day := m.Values()
+	if err := validateMonth(month); err != nil {
+		in.Restore(start)
+		return time.Time{}, false, err
 	}
-
-	return match, true, nil
+	if err := validateDay(year, time.Month(month.value), day); err != nil {
+		in.Restore(start)
+		return time.Time{}, false, err
+	}
+	return time.Date(year, time.Month(month.value), day.value, 0, 0, 0, 0, time.UTC), true, nil
 }
 
-// mon, monday, tue, tues, tuesday, wed, weds, wednesday, thu, thur, thurs, thursday, fri, friday, sat, saturday, sun, sunday
+// Parse the name of a day of the week in the current locale, English by default (mon, monday, tue, tues, tuesday, wed, weds,
+// wednesday, thu, thur, thurs, thursday, fri, friday, sat, saturday, sun, sunday). See WithLocale.
 var DayOfWeek = func(in Input) (match time.Weekday, ok bool, err error) {
-	m := map[time.Weekday]Parser[string]{
-		// Ordering is important! Use more specific parsers first.
-		time.Monday:    Any(StringInsensitive("monday"), StringInsensitive("mon")),
-		time.Tuesday:   Any(StringInsensitive("tuesday"), StringInsensitive("tues"), StringInsensitive("tue")),
-		time.Wednesday: Any(StringInsensitive("wednesday"), StringInsensitive("weds"), StringInsensitive("wed")),
-		time.Thursday:  Any(StringInsensitive("thursday"), StringInsensitive("thurs"), StringInsensitive("thur"), StringInsensitive("thu")),
-		time.Friday:    Any(StringInsensitive("friday"), StringInsensitive("fri")),
-		time.Saturday:  Any(StringInsensitive("saturday"), StringInsensitive("sat")),
-		time.Sunday:    Any(StringInsensitive("sunday"), StringInsensitive("sun")),
-	}
-
-	for day, parser := range m {
-		_, ok, err := parser(in)
-		if err != nil {
-			return time.Weekday(-1), false, err
-		}
-		if ok {
-			return day, true, nil
-		}
+	day, ok, err := currentLocale(in).weekdayNames()(in)
+	if err != nil || !ok {
+		return time.Weekday(-1), false, err
 	}
-
-	return time.Weekday(-1), false, nil
+	return time.Weekday(day), true, nil
 }
 
 // Space separated list of days of the week.
@@ -100,58 +92,265 @@ var DaysOfWeek = func(in Input) (match []time.Weekday, ok bool, err error) {
 	return days, true, nil
 }
 
-// Parse a number followed by an optional ordinal (st, nd, rd, th).
-var MonthDay = func(in Input) (match int, ok bool, err error) {
-	n, ok, err := StringFrom(AtLeast(1, Digit))(in)
-	if err != nil {
-		return 0, false, err
+// Parse a day of the month from 1 to 31 followed by an optional ordinal (st, nd, rd, th) that must agree with the number, e.g. 1st, 2nd, 11th.
+var MonthDay = monthDay(false)
+
+// Parse a day of the month with a required ordinal, e.g. 3rd.
+var ordinalDay = monthDay(true)
+
+func monthDay(ordinalRequired bool) Parser[int] {
+	return func(in Input) (int, bool, error) {
+		start := in.Checkpoint()
+		n, ok, err := StringFrom(SkipAtLeast(1, isoDigit))(in)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		ordinal, hasOrdinal, err := Any(StringInsensitive("st"), StringInsensitive("nd"), StringInsensitive("rd"), StringInsensitive("th"))(in)
+		if err != nil {
+			in.Restore(start)
+			return 0, false, err
+		}
+		// Longer numbers without an ordinal are not days, e.g. a year.
+		if !hasOrdinal && (ordinalRequired || len(n) > 2) {
+			in.Restore(start)
+			return 0, false, nil
+		}
+
+		number, err := strconv.Atoi(n)
+		if err != nil || len(n) > 2 || number < 1 || number > 31 {
+			in.Restore(start)
+			return 0, false, &DateError{Component: "day", Value: n + ordinal, Position: start.Position(), Reason: "must be between 1 and 31"}
+		}
+		if expected := ordinalSuffix(number); hasOrdinal && !strings.EqualFold(ordinal, expected) {
+			in.Restore(start)
+			return 0, false, &DateError{Component: "day", Value: n + ordinal, Position: start.Position(), Reason: "should be " + n + expected}
+		}
+		return number, true, nil
+	}
+}
+
+// ordinalSuffix returns the English ordinal suffix for the number, e.g. st for 1 and 21 but th for 11.
+func ordinalSuffix(n int) string {
+	if n%100 >= 11 && n%100 <= 13 {
+		return "th"
 	}
-	if !ok {
-		return 0, false, nil
+	switch n % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
 	}
+	return "th"
+}
 
-	// Parse an optional ordinal.
-	ordinal := Any(StringInsensitive("st"), StringInsensitive("nd"), StringInsensitive("rd"), StringInsensitive("th"))
-	_, _, err = ordinal(in)
-	if err != nil {
+// Parse a month number from 1 to 12, optionally zero padded.
+var MonthNumber = func(in Input) (time.Month, bool, error) {
+	start := in.Checkpoint()
+	month, ok, err := positioned(numberWithDigits(1, 2))(in)
+	if err != nil || !ok {
 		return 0, false, err
 	}
+	if err := validateMonth(month); err != nil {
+		in.Restore(start)
+		return 0, false, err
+	}
+	return time.Month(month.value), true, nil
+}
 
-	// Convert the number to an integer.
-	number, err := strconv.Atoi(n)
-	if err != nil {
-		return 0, false, fmt.Errorf("failed to parse number: %w", err)
+// DateOrder is the order of the day and month in numeric dates such as 03/04/2024.
+type DateOrder int
+
+const (
+	// Day, month, year, e.g. 03/04/2024 is the 3rd of April.
+	DMY DateOrder = iota
+	// Month, day, year, e.g. 03/04/2024 is the 4th of March.
+	MDY
+)
+
+// DayMonthYear parses a date with the year last, returning midnight UTC. It accepts written dates in either order
+// (3rd March 2024, 3 Mar 2024, the 3rd of March, 2024, March 3, 2024, March 3rd 2024) and numeric dates separated
+// by /, - or . (03/03/2024, 3-3-2024) whose day and month are ordered by the given order.
+// Dates that do not exist, such as the 30th of February, are an error. Month names follow the current locale, see WithLocale,
+// but ordinal suffixes and the words "the" and "of" are English in every locale.
+func DayMonthYear(order DateOrder) Parser[time.Time] {
+	numeric := func(in Input) (dateFields, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := SequenceOf5(positioned(numberWithDigits(1, 2)), RuneIn("/-."), positioned(numberWithDigits(1, 2)), RuneIn("/-."), isoNumber(4))(in)
+		if err != nil || !ok {
+			return dateFields{}, false, err
+		}
+		first, sep1, second, sep2, year := m.Values()
+		if sep1 != sep2 {
+			in.Restore(start)
+			return dateFields{}, false, nil
+		}
+		day, month := first, second
+		if order == MDY {
+			day, month = second, first
+		}
+		if err := validateMonth(month); err != nil {
+			in.Restore(start)
+			return dateFields{}, false, err
+		}
+		if day.value < 1 || day.value > 31 {
+			in.Restore(start)
+			return dateFields{}, false, &DateError{Component: "day", Value: strconv.Itoa(day.value), Position: day.position, Reason: "must be between 1 and 31"}
+		}
+		return dateFields{year: year, month: time.Month(month.value), day: day}, true, nil
 	}
+	return validDate(Longest(writtenDate, numeric), time.UTC)
+}
 
-	return number, true, nil
+// writtenDate parses a date with the month written as a name in either order, e.g. 3rd March 2024 or March 3, 2024.
+var writtenDate = func() Parser[dateFields] {
+	comma := Longest(StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace), InlineWhitespace)
+	dayFirst := func(in Input) (dateFields, bool, error) {
+		m, ok, err := SequenceOf6(
+			Optional(SequenceOf2(StringInsensitive("the"), InlineWhitespace)),
+			positioned(MonthDay),
+			InlineWhitespace,
+			Optional(SequenceOf2(StringInsensitive("of"), InlineWhitespace)),
+			MonthOfYear,
+			SequenceOf2(comma, isoNumber(4)),
+		)(in)
+		if err != nil || !ok {
+			return dateFields{}, false, err
+		}
+		_, day, _, _, month, year := m.Values()
+		_, y := year.Values()
+		return dateFields{year: y, month: month, day: day}, true, nil
+	}
+	monthFirst := func(in Input) (dateFields, bool, error) {
+		m, ok, err := SequenceOf5(MonthOfYear, InlineWhitespace, positioned(MonthDay), comma, isoNumber(4))(in)
+		if err != nil || !ok {
+			return dateFields{}, false, err
+		}
+		month, _, day, _, year := m.Values()
+		return dateFields{year: year, month: month, day: day}, true, nil
+	}
+	return Longest(dayFirst, monthFirst)
+}()
+
+// validDate returns midnight in the location on the date parsed, or an error if the date does not exist.
+func validDate(parser Parser[dateFields], loc *time.Location) Parser[time.Time] {
+	return func(in Input) (time.Time, bool, error) {
+		start := in.Checkpoint()
+		d, ok, err := parser(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		if err := validateDay(d.year, d.month, d.day); err != nil {
+			in.Restore(start)
+			return time.Time{}, false, err
+		}
+		return time.Date(d.year, d.month, d.day.value, 0, 0, 0, 0, loc), true, nil
+	}
 }
 
-var MonthOfYear = func(in Input) (match time.Month, ok bool, err error) {
-	m := map[time.Month]Parser[string]{
-		// Ordering is important! Use more specific parsers first.
-		time.January:   Any(StringInsensitive("january"), StringInsensitive("jan")),
-		time.February:  Any(StringInsensitive("february"), StringInsensitive("feb")),
-		time.March:     Any(StringInsensitive("march"), StringInsensitive("mar")),
-		time.April:     Any(StringInsensitive("april"), StringInsensitive("apr")),
-		time.May:       StringInsensitive("may"),
-		time.June:      Any(StringInsensitive("june"), StringInsensitive("jun")),
-		time.July:      Any(StringInsensitive("july"), StringInsensitive("jul")),
-		time.August:    Any(StringInsensitive("august"), StringInsensitive("aug")),
-		time.September: Any(StringInsensitive("september"), StringInsensitive("sept"), StringInsensitive("sep")),
-		time.October:   Any(StringInsensitive("october"), StringInsensitive("oct")),
-		time.November:  Any(StringInsensitive("november"), StringInsensitive("nov")),
-		time.December:  Any(StringInsensitive("december"), StringInsensitive("dec")),
-	}
-
-	for month, parser := range m {
-		_, ok, err := parser(in)
-		if err != nil {
-			return time.Month(-1), false, err
+type dateFields struct {
+	year  int
+	month time.Month
+	day   position[int]
+}
+
+// position is a match along with the position it started at, used to report errors found after parsing.
+type position[T any] struct {
+	value    T
+	position int
+}
+
+func positioned[T any](parser Parser[T]) Parser[position[T]] {
+	return func(in Input) (position[T], bool, error) {
+		start := in.Checkpoint().Position()
+		match, ok, err := parser(in)
+		if err != nil || !ok {
+			return position[T]{}, false, err
+		}
+		return position[T]{value: match, position: start}, true, nil
+	}
+}
+
+// numberWithDigits parses a number with between min and max ASCII digits.
+func numberWithDigits(min, max int) Parser[int] {
+	return func(in Input) (int, bool, error) {
+		s, ok, err := StringFrom(SkipBetween(min, max, isoDigit))(in)
+		if err != nil || !ok {
+			return 0, false, err
 		}
-		if ok {
-			return month, true, nil
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to parse number: %w", err)
 		}
+		return n, true, nil
+	}
+}
+
+func validateMonth(month position[int]) error {
+	if month.value < 1 || month.value > 12 {
+		return &DateError{Component: "month", Value: strconv.Itoa(month.value), Position: month.position, Reason: "must be between 1 and 12"}
+	}
+	return nil
+}
+
+func validateDay(year int, month time.Month, day position[int]) error {
+	if days := daysInMonth(year, month); day.value < 1 || day.value > days {
+		return &DateError{Component: "day", Value: strconv.Itoa(day.value), Position: day.position, Reason: fmt.Sprintf("%s %d has %d days", month, year, days)}
+	}
+	return nil
+}
+
+// Parse the name of a month in the current locale, English by default (jan, january, feb, february, ...). See WithLocale.
+var MonthOfYear = func(in Input) (match time.Month, ok bool, err error) {
+	month, ok, err := currentLocale(in).monthNames()(in)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	return time.Month(month + 1), true, nil
+}
+
+// FormatYearMonthDay formats the date as yyyy-MM-dd, the inverse of YearMonthDay.
+func FormatYearMonthDay(date time.Time) string {
+	return date.Format(time.DateOnly)
+}
+
+// FormatDayOfWeek formats the day of the week as its full English name, the inverse of DayOfWeek.
+// See Locale.WeekdayName for other locales.
+func FormatDayOfWeek(day time.Weekday) string {
+	return English.WeekdayName(day)
+}
+
+// FormatDaysOfWeek formats the days of the week as a space separated list, the inverse of DaysOfWeek.
+func FormatDaysOfWeek(days []time.Weekday) string {
+	names := make([]string, len(days))
+	for i, day := range days {
+		names[i] = FormatDayOfWeek(day)
 	}
+	return strings.Join(names, " ")
+}
+
+// FormatMonthDay formats the day of the month with its ordinal suffix, e.g. 3rd, the inverse of MonthDay.
+func FormatMonthDay(day int) string {
+	return strconv.Itoa(day) + ordinalSuffix(day)
+}
+
+// FormatMonthNumber formats the month as a number without zero padding, the inverse of MonthNumber.
+func FormatMonthNumber(month time.Month) string {
+	return strconv.Itoa(int(month))
+}
+
+// FormatDayMonthYear formats the date as a numeric date separated by / in the given order, e.g. 03/04/2024,
+// the inverse of DayMonthYear.
+func FormatDayMonthYear(order DateOrder, date time.Time) string {
+	if order == MDY {
+		return date.Format("01/02/2006")
+	}
+	return date.Format("02/01/2006")
+}
 
-	return 0, false, nil
+// FormatMonthOfYear formats the month as its full English name, the inverse of MonthOfYear.
+// See Locale.MonthName for other locales.
+func FormatMonthOfYear(month time.Month) string {
+	return English.MonthName(month)
 }
@@ -18,8 +18,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/liamawhite/parse/core"
 	. "github.com/liamawhite/parse/test"
 	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestYearMonthDay(t *testing.T) {
@@ -32,32 +34,36 @@ func TestYearMonthDay(t *testing.T) {
 			ExpectedOK:    true,
 		},
 		{
-			Name:       "invalid day high",
-			Input:      "2021-01-32",
-			Parser:     YearMonthDay,
-			ExpectedOK: false,
-			WantErr:    true,
+			Name:           "invalid day high",
+			Input:          "2021-01-32",
+			Parser:         YearMonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-01-32",
 		},
 		{
-			Name:       "invalid day low",
-			Input:      "2021-01-00",
-			Parser:     YearMonthDay,
-			ExpectedOK: false,
-			WantErr:    true,
+			Name:           "invalid day low",
+			Input:          "2021-01-00",
+			Parser:         YearMonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-01-00",
 		},
 		{
-			Name:       "invalid month high",
-			Input:      "2021-13-01",
-			Parser:     YearMonthDay,
-			ExpectedOK: false,
-			WantErr:    true,
+			Name:           "invalid month high",
+			Input:          "2021-13-01",
+			Parser:         YearMonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-13-01",
 		},
 		{
-			Name:       "invalid month low",
-			Input:      "2021-00-01",
-			Parser:     YearMonthDay,
-			ExpectedOK: false,
-			WantErr:    true,
+			Name:           "invalid month low",
+			Input:          "2021-00-01",
+			Parser:         YearMonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-00-01",
 		},
 		{
 			Name:          "leap year",
@@ -67,11 +73,12 @@ func TestYearMonthDay(t *testing.T) {
 			ExpectedOK:    true,
 		},
 		{
-			Name:       "not a leap year",
-			Input:      "2021-02-29",
-			Parser:     YearMonthDay,
-			ExpectedOK: false,
-			WantErr:    true,
+			Name:           "not a leap year",
+			Input:          "2021-02-29",
+			Parser:         YearMonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-02-29",
 		},
 	}
 	RunTests(t, tests)
@@ -281,6 +288,66 @@ func TestMonthDay(t *testing.T) {
 			ExpectedMatch: 4,
 			ExpectedOK:    true,
 		},
+		{
+			Name:          "11th",
+			Input:         "11th",
+			Parser:        MonthDay,
+			ExpectedMatch: 11,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "22nd",
+			Input:         "22nd",
+			Parser:        MonthDay,
+			ExpectedMatch: 22,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "31st",
+			Input:         "31st",
+			Parser:        MonthDay,
+			ExpectedMatch: 31,
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "out of range",
+			Input:          "99th",
+			Parser:         MonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "99th",
+		},
+		{
+			Name:           "zero",
+			Input:          "0",
+			Parser:         MonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "0",
+		},
+		{
+			Name:           "wrong ordinal",
+			Input:          "1nd",
+			Parser:         MonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "1nd",
+		},
+		{
+			Name:           "wrong ordinal for a teen",
+			Input:          "11st",
+			Parser:         MonthDay,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "11st",
+		},
+		{
+			Name:           "longer number",
+			Input:          "2024",
+			Parser:         MonthDay,
+			ExpectedOK:     false,
+			RemainingInput: "2024",
+		},
 	}
 	RunTests(t, tests)
 }
@@ -458,3 +525,138 @@ func TestMonthOfYear(t *testing.T) {
 	}
 	RunTests(t, tests)
 }
+
+func TestMonthNumber(t *testing.T) {
+	tests := []ParserTest[time.Month]{
+		{
+			Name:          "padded",
+			Input:         "03",
+			Parser:        MonthNumber,
+			ExpectedMatch: time.March,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "unpadded",
+			Input:         "12",
+			Parser:        MonthNumber,
+			ExpectedMatch: time.December,
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "out of range",
+			Input:          "13",
+			Parser:         MonthNumber,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "13",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestDayMonthYear(t *testing.T) {
+	march3 := time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC)
+	tests := []ParserTest[time.Time]{
+		{
+			Name:          "day first",
+			Input:         "3rd March 2024",
+			Parser:        DayMonthYear(DMY),
+			ExpectedMatch: march3,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "day first with of and comma",
+			Input:         "the 3rd of Mar, 2024",
+			Parser:        DayMonthYear(DMY),
+			ExpectedMatch: march3,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "month first",
+			Input:         "March 3, 2024",
+			Parser:        DayMonthYear(DMY),
+			ExpectedMatch: march3,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "month first with ordinal",
+			Input:         "mar 3rd 2024",
+			Parser:        DayMonthYear(MDY),
+			ExpectedMatch: march3,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "numeric DMY",
+			Input:         "04/03/2024",
+			Parser:        DayMonthYear(DMY),
+			ExpectedMatch: time.Date(2024, time.March, 4, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "numeric MDY",
+			Input:         "04/03/2024",
+			Parser:        DayMonthYear(MDY),
+			ExpectedMatch: time.Date(2024, time.April, 3, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "numeric unpadded with dots",
+			Input:         "3.3.2024",
+			Parser:        DayMonthYear(DMY),
+			ExpectedMatch: march3,
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "numeric with mixed separators",
+			Input:          "03/03-2024",
+			Parser:         DayMonthYear(DMY),
+			ExpectedOK:     false,
+			RemainingInput: "03/03-2024",
+		},
+		{
+			Name:           "impossible date",
+			Input:          "Feb 30, 2024",
+			Parser:         DayMonthYear(DMY),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "Feb 30, 2024",
+		},
+		{
+			Name:           "numeric month out of range",
+			Input:          "12/13/2024",
+			Parser:         DayMonthYear(DMY),
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "12/13/2024",
+		},
+		{
+			Name:           "remaining input",
+			Input:          "3 March 2024 at noon",
+			Parser:         DayMonthYear(DMY),
+			ExpectedMatch:  march3,
+			ExpectedOK:     true,
+			RemainingInput: " at noon",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestDateError(t *testing.T) {
+	for _, test := range []struct {
+		input    string
+		parser   core.Parser[time.Time]
+		expected DateError
+	}{
+		{"Feb 30, 2024", DayMonthYear(DMY), DateError{Component: "day", Value: "30", Position: 4, Reason: "February 2024 has 29 days"}},
+		{"the 31st of April 2024", DayMonthYear(DMY), DateError{Component: "day", Value: "31", Position: 4, Reason: "April 2024 has 30 days"}},
+		{"12/13/2024", DayMonthYear(DMY), DateError{Component: "month", Value: "13", Position: 3, Reason: "must be between 1 and 12"}},
+		{"2023-02-29", YearMonthDay, DateError{Component: "day", Value: "29", Position: 8, Reason: "February 2023 has 28 days"}},
+		{"March 3nd, 2024", DayMonthYear(DMY), DateError{Component: "day", Value: "3nd", Position: 6, Reason: "should be 3rd"}},
+	} {
+		t.Run(test.input, func(t *testing.T) {
+			_, ok, err := test.parser(core.NewInput(test.input))
+			assert.False(t, ok)
+			assert.Equal(t, &test.expected, err)
+		})
+	}
+}
@@ -15,13 +15,375 @@
 package time
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
 	. "github.com/liamawhite/parse/core"
 )
 
 var (
-	// More specific first.
-	Day   = Any(String("days"), String("day"))
-	Week  = Any(String("weeks"), String("week"))
-	Month = Any(String("months"), String("month"))
-	Year  = Any(String("years"), String("year"))
+	Day   = Longest(String("days"), String("day"))
+	Week  = Longest(String("weeks"), String("week"))
+	Month = Longest(String("months"), String("month"))
+	Year  = Longest(String("years"), String("year"))
+
+	Hour   = Longest(String("hours"), String("hour"))
+	Minute = Longest(String("minutes"), String("minute"))
+	Second = Longest(String("seconds"), String("second"))
 )
+
+// Period is a calendar aware duration. Years, months, weeks and days are kept separate from clock time
+// because their length depends on the date they are added to, e.g. 1 month is 28 days when added to the 1st of February.
+type Period struct {
+	Years  int
+	Months int
+	Weeks  int
+	Days   int
+	Clock  time.Duration
+}
+
+// AddTo returns the time with the period added, adding the calendar units before the clock time.
+func (p Period) AddTo(t time.Time) time.Time {
+	return t.AddDate(p.Years, p.Months, p.Weeks*7+p.Days).Add(p.Clock)
+}
+
+// Add returns the sum of both periods.
+func (p Period) Add(o Period) Period {
+	return Period{
+		Years:  p.Years + o.Years,
+		Months: p.Months + o.Months,
+		Weeks:  p.Weeks + o.Weeks,
+		Days:   p.Days + o.Days,
+		Clock:  p.Clock + o.Clock,
+	}
+}
+
+// Negate returns the period with every unit negated.
+func (p Period) Negate() Period {
+	return Period{Years: -p.Years, Months: -p.Months, Weeks: -p.Weeks, Days: -p.Days, Clock: -p.Clock}
+}
+
+// IsZero reports whether the period has no length.
+func (p Period) IsZero() bool {
+	return p == Period{}
+}
+
+// Duration parses either an ISO 8601 duration (P1Y2M3DT4H5M6.5S, P2W) or a human readable duration made up of
+// one or more numbers followed by a unit, e.g. 3 days, 2w, 1 year 2 months, 1h30m, 1 hour and 30 minutes or 1.5 hours.
+// Only clock units (hours, minutes and seconds) may be fractional.
+var Duration = func(in Input) (Period, bool, error) {
+	return Longest(ISODuration, HumanDuration)(in)
+}
+
+// HumanDuration parses one or more numbers followed by a unit, optionally separated by whitespace, commas or "and".
+// Units may be written in full (days), abbreviated (hrs) or as a single letter (d). Note that m is minutes and mo is months.
+var HumanDuration = func(in Input) (Period, bool, error) {
+	start := in.Checkpoint()
+	separator := Any(
+		StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace),
+		StringFrom(InlineWhitespace, String("and"), InlineWhitespace),
+		OptionalInlineWhitespace,
+	)
+
+	term := Optional(durationTerm)
+
+	var period Period
+	for i := 0; ; i++ {
+		beforeTerm := in.Checkpoint()
+		if i > 0 {
+			_, ok, err := separator(in)
+			if err != nil {
+				in.Restore(start)
+				return Period{}, false, err
+			}
+			if !ok {
+				break
+			}
+		}
+		m, _, err := term(in)
+		if err != nil {
+			in.Restore(start)
+			return Period{}, false, err
+		}
+		if !m.Ok() {
+			in.Restore(beforeTerm)
+			if i == 0 {
+				return Period{}, false, nil
+			}
+			break
+		}
+		sum, ok := addPeriods(period, m.Values())
+		if !ok {
+			in.Restore(start)
+			return Period{}, false, fmt.Errorf("failed to parse duration: too large at position %d", beforeTerm.Position())
+		}
+		period = sum
+	}
+	return period, true, nil
+}
+
+type durationUnit struct {
+	parser Parser[string]
+	// Number of nanoseconds in the unit if it is a clock unit.
+	clock time.Duration
+	apply func(p *Period, n int)
+}
+
+var durationUnits = []durationUnit{
+	{parser: Longest(Year, String("yrs"), String("yr"), String("y")), apply: func(p *Period, n int) { p.Years += n }},
+	{parser: Longest(Month, String("mos"), String("mo")), apply: func(p *Period, n int) { p.Months += n }},
+	{parser: Longest(Week, String("wks"), String("wk"), String("w")), apply: func(p *Period, n int) { p.Weeks += n }},
+	{parser: Longest(Day, String("d")), apply: func(p *Period, n int) { p.Days += n }},
+	{parser: Longest(Hour, String("hrs"), String("hr"), String("h")), clock: time.Hour},
+	{parser: Longest(Minute, String("mins"), String("min"), String("m")), clock: time.Minute},
+	{parser: Longest(Second, String("secs"), String("sec"), String("s")), clock: time.Second},
+}
+
+// The first of the durationUnits that is not immediately followed by another letter.
+var durationUnitParser = func() Parser[durationUnit] {
+	parsers := make([]Parser[durationUnit], len(durationUnits))
+	for i, unit := range durationUnits {
+		parsers[i] = func(in Input) (durationUnit, bool, error) {
+			start := in.Checkpoint()
+			_, ok, err := unit.parser(in)
+			if err != nil || !ok {
+				return durationUnit{}, false, err
+			}
+			if r, size := PeekRune(in); size > 0 && unicode.IsLetter(r) {
+				in.Restore(start)
+				return durationUnit{}, false, nil
+			}
+			return unit, true, nil
+		}
+	}
+	return Any(parsers...)
+}()
+
+// A number, optional whitespace and a unit that is not immediately followed by another letter.
+var durationTerm = func(in Input) (Period, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf3(durationNumber, OptionalInlineWhitespace, durationUnitParser)(in)
+	if err != nil || !ok {
+		return Period{}, false, err
+	}
+	number, _, unit := m.Values()
+	period, err := durationPeriod(number, unit)
+	if err != nil {
+		in.Restore(start)
+		return Period{}, false, err
+	}
+	return period, true, nil
+}
+
+// An integer or decimal number.
+var durationNumber = StringFrom(SequenceOf2(SkipOneOrMore(isoDigit), Optional(SequenceOf2(Rune('.'), SkipOneOrMore(isoDigit)))))
+
+func durationPeriod(number string, unit durationUnit) (Period, error) {
+	var period Period
+	if unit.clock == 0 {
+		n, err := strconv.Atoi(number)
+		if errors.Is(err, strconv.ErrRange) {
+			return Period{}, fmt.Errorf("failed to parse duration: %s is too large", number)
+		}
+		if err != nil {
+			return Period{}, fmt.Errorf("failed to parse duration: %s must be a whole number", number)
+		}
+		unit.apply(&period, n)
+		return period, nil
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return Period{}, fmt.Errorf("failed to parse duration: %w", err)
+	}
+	clock := n * float64(unit.clock)
+	if clock > math.MaxInt64 {
+		return Period{}, fmt.Errorf("failed to parse duration: %s is too large", number)
+	}
+	period.Clock = time.Duration(math.Round(clock))
+	return period, nil
+}
+
+// ISODuration parses an ISO 8601 duration in the format PnYnMnWnDTnHnMnS where each component is optional
+// but at least one must be present. Only the seconds may be fractional.
+var ISODuration = func(in Input) (Period, bool, error) {
+	start := in.Checkpoint()
+	_, ok, err := Rune('P')(in)
+	if err != nil || !ok {
+		return Period{}, false, err
+	}
+
+	var period Period
+	components := 0
+	for _, c := range []struct {
+		designator rune
+		field      *int
+	}{{'Y', &period.Years}, {'M', &period.Months}, {'W', &period.Weeks}, {'D', &period.Days}} {
+		number, ok, err := isoDurationComponent(c.designator, false)(in)
+		if err != nil {
+			in.Restore(start)
+			return Period{}, false, err
+		}
+		if ok {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				in.Restore(start)
+				return Period{}, false, fmt.Errorf("failed to parse duration: %s is too large at position %d", number, start.Position())
+			}
+			*c.field = n
+			components++
+		}
+	}
+
+	_, ok, err = Rune('T')(in)
+	if err != nil {
+		in.Restore(start)
+		return Period{}, false, err
+	}
+	if ok {
+		timeComponents := 0
+		for _, c := range []struct {
+			designator rune
+			unit       time.Duration
+		}{{'H', time.Hour}, {'M', time.Minute}, {'S', time.Second}} {
+			number, ok, err := isoDurationComponent(c.designator, c.designator == 'S')(in)
+			if err != nil {
+				in.Restore(start)
+				return Period{}, false, err
+			}
+			if ok {
+				// Allow for a comma as the decimal separator.
+				n, err := strconv.ParseFloat(strings.Replace(number, ",", ".", 1), 64)
+				if err != nil {
+					in.Restore(start)
+					return Period{}, false, fmt.Errorf("failed to parse duration: %w", err)
+				}
+				clock := math.Round(n * float64(c.unit))
+				if clock >= float64(math.MaxInt64-period.Clock) {
+					in.Restore(start)
+					return Period{}, false, fmt.Errorf("failed to parse duration: too large at position %d", start.Position())
+				}
+				period.Clock += time.Duration(clock)
+				timeComponents++
+			}
+		}
+		// A T must be followed by at least one time component.
+		if timeComponents == 0 {
+			in.Restore(start)
+			return Period{}, false, nil
+		}
+		components += timeComponents
+	}
+
+	if components == 0 {
+		in.Restore(start)
+		return Period{}, false, nil
+	}
+	return period, true, nil
+}
+
+// A number followed by the designator, e.g. 3D, returning the number.
+func isoDurationComponent(designator rune, fractional bool) Parser[string] {
+	return func(in Input) (string, bool, error) {
+		number := StringFrom(SkipOneOrMore(isoDigit))
+		if fractional {
+			number = StringFrom(SequenceOf2(SkipOneOrMore(isoDigit), Optional(SequenceOf2(RuneIn(".,"), SkipOneOrMore(isoDigit)))))
+		}
+		m, ok, err := SequenceOf2(number, Rune(designator))(in)
+		if err != nil || !ok {
+			return "", false, err
+		}
+		s, _ := m.Values()
+		return s, true, nil
+	}
+}
+
+// addPeriods returns the sum of both periods, or false if any unit overflows.
+func addPeriods(p, o Period) (Period, bool) {
+	sum := p.Add(o)
+	for _, c := range [][3]int64{
+		{int64(p.Years), int64(o.Years), int64(sum.Years)},
+		{int64(p.Months), int64(o.Months), int64(sum.Months)},
+		{int64(p.Weeks), int64(o.Weeks), int64(sum.Weeks)},
+		{int64(p.Days), int64(o.Days), int64(sum.Days)},
+		{int64(p.Clock), int64(o.Clock), int64(sum.Clock)},
+	} {
+		if (c[1] > 0 && c[2] < c[0]) || (c[1] < 0 && c[2] > c[0]) {
+			return Period{}, false
+		}
+	}
+	return sum, true
+}
+
+// FormatISODuration formats the period as an ISO 8601 duration, e.g. P1Y2M3DT4H5M6.5S, the inverse of ISODuration.
+// Zero components are omitted and the zero period is PT0S. Negative components, e.g. from Negate, are not supported
+// as ISO 8601 has no way to write them, so the result for a period with any cannot be parsed.
+func FormatISODuration(p Period) string {
+	var b strings.Builder
+	b.WriteRune('P')
+	for _, c := range []struct {
+		n          int
+		designator string
+	}{{p.Years, "Y"}, {p.Months, "M"}, {p.Weeks, "W"}, {p.Days, "D"}} {
+		if c.n != 0 {
+			b.WriteString(strconv.Itoa(c.n) + c.designator)
+		}
+	}
+	if p.Clock != 0 || b.Len() == 1 {
+		b.WriteRune('T')
+		hours, minutes, seconds := splitClock(p.Clock)
+		if hours != 0 {
+			b.WriteString(strconv.Itoa(hours) + "H")
+		}
+		if minutes != 0 {
+			b.WriteString(strconv.Itoa(minutes) + "M")
+		}
+		if seconds != "0" || p.Clock == 0 {
+			b.WriteString(seconds + "S")
+		}
+	}
+	return b.String()
+}
+
+// FormatHumanDuration formats the period as words, e.g. 1 year 2 months 3 days 4 hours 5 minutes 6.5 seconds,
+// the inverse of HumanDuration. Zero components are omitted and the zero period is 0 seconds.
+// As with FormatISODuration, negative components are not supported.
+func FormatHumanDuration(p Period) string {
+	hours, minutes, seconds := splitClock(p.Clock)
+	var terms []string
+	for _, c := range []struct {
+		n    int
+		unit string
+	}{{p.Years, "year"}, {p.Months, "month"}, {p.Weeks, "week"}, {p.Days, "day"}, {hours, "hour"}, {minutes, "minute"}} {
+		if c.n == 1 {
+			terms = append(terms, "1 "+c.unit)
+		} else if c.n != 0 {
+			terms = append(terms, strconv.Itoa(c.n)+" "+c.unit+"s")
+		}
+	}
+	if seconds == "1" {
+		terms = append(terms, "1 second")
+	} else if seconds != "0" || len(terms) == 0 {
+		terms = append(terms, seconds+" seconds")
+	}
+	return strings.Join(terms, " ")
+}
+
+// splitClock splits a positive duration into whole hours and minutes, and seconds with any fraction.
+func splitClock(d time.Duration) (hours, minutes int, seconds string) {
+	hours, d = int(d/time.Hour), d%time.Hour
+	minutes, d = int(d/time.Minute), d%time.Minute
+	return hours, minutes, strconv.Itoa(int(d/time.Second)) + formatFraction(int(d%time.Second))
+}
+
+// formatFraction formats nanoseconds as a decimal fraction of a second without trailing zeros, e.g. .5, or nothing if it is zero.
+func formatFraction(nanosecond int) string {
+	if nanosecond == 0 {
+		return ""
+	}
+	return "." + strings.TrimRight(fmt.Sprintf("%09d", nanosecond), "0")
+}
@@ -0,0 +1,218 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []ParserTest[Period]{
+		{
+			Name:          "days",
+			Input:         "3 days",
+			Parser:        Duration,
+			ExpectedMatch: Period{Days: 3},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "single day",
+			Input:         "1 day",
+			Parser:        Duration,
+			ExpectedMatch: Period{Days: 1},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "weeks abbreviated",
+			Input:         "2w",
+			Parser:        Duration,
+			ExpectedMatch: Period{Weeks: 2},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "years and months",
+			Input:         "1 year 2 months",
+			Parser:        Duration,
+			ExpectedMatch: Period{Years: 1, Months: 2},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "hours and minutes abbreviated",
+			Input:         "1h30m",
+			Parser:        Duration,
+			ExpectedMatch: Period{Clock: 90 * time.Minute},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "months abbreviated",
+			Input:         "6mo",
+			Parser:        Duration,
+			ExpectedMatch: Period{Months: 6},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "separated by commas and and",
+			Input:         "1 hour, 2 mins and 3 secs",
+			Parser:        Duration,
+			ExpectedMatch: Period{Clock: time.Hour + 2*time.Minute + 3*time.Second},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "fractional clock unit",
+			Input:         "1.5 hours",
+			Parser:        Duration,
+			ExpectedMatch: Period{Clock: 90 * time.Minute},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "fractional calendar unit",
+			Input:          "1.5 days",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "1.5 days",
+		},
+		{
+			Name:           "stops at the last unit",
+			Input:          "3 days from now",
+			Parser:         Duration,
+			ExpectedMatch:  Period{Days: 3},
+			ExpectedOK:     true,
+			RemainingInput: " from now",
+		},
+		{
+			Name:           "unit must be a whole word",
+			Input:          "2 mondays",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			RemainingInput: "2 mondays",
+		},
+		{
+			Name:           "unit followed by a non-ASCII letter",
+			Input:          "2 mé",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			RemainingInput: "2 mé",
+		},
+		{
+			Name:           "unit followed by non-ASCII punctuation",
+			Input:          "3 days—then rest",
+			Parser:         Duration,
+			ExpectedMatch:  Period{Days: 3},
+			ExpectedOK:     true,
+			RemainingInput: "—then rest",
+		},
+		{
+			Name:           "number without a unit",
+			Input:          "3 apples",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			RemainingInput: "3 apples",
+		},
+		{
+			Name:          "ISO 8601",
+			Input:         "P1Y2M3DT4H",
+			Parser:        Duration,
+			ExpectedMatch: Period{Years: 1, Months: 2, Days: 3, Clock: 4 * time.Hour},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ISO 8601 weeks",
+			Input:         "P2W",
+			Parser:        Duration,
+			ExpectedMatch: Period{Weeks: 2},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ISO 8601 time only with fractional seconds",
+			Input:         "PT1M30.5S",
+			Parser:        Duration,
+			ExpectedMatch: Period{Clock: time.Minute + 30*time.Second + 500*time.Millisecond},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "ISO 8601 without components",
+			Input:          "PT",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			RemainingInput: "PT",
+		},
+		{
+			Name:           "calendar unit out of range",
+			Input:          "99999999999999999999 days",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "99999999999999999999 days",
+		},
+		{
+			Name:           "sum of calendar units out of range",
+			Input:          "9223372036854775807 days 1 day",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "9223372036854775807 days 1 day",
+		},
+		{
+			Name:           "ISO 8601 calendar component out of range",
+			Input:          "P99999999999999999999D",
+			Parser:         Duration,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "P99999999999999999999D",
+		},
+		{
+			Name:          "ISO 8601 calendar component beyond float precision",
+			Input:         "P9007199254740993D",
+			Parser:        Duration,
+			ExpectedMatch: Period{Days: 9007199254740993},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "ISO 8601 out of order",
+			Input:          "P1D2Y",
+			Parser:         Duration,
+			ExpectedMatch:  Period{Days: 1},
+			ExpectedOK:     true,
+			RemainingInput: "2Y",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestPeriodAddTo(t *testing.T) {
+	start := time.Date(2024, time.January, 31, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.March, 2, 12, 0, 0, 0, time.UTC), Period{Months: 1}.AddTo(start))
+	assert.Equal(t, time.Date(2025, time.February, 14, 13, 30, 0, 0, time.UTC), Period{Years: 1, Weeks: 2, Clock: 90 * time.Minute}.AddTo(start))
+	assert.Equal(t, start, Period{Days: 3}.Add(Period{Days: 3}.Negate()).AddTo(start))
+	assert.True(t, Period{}.IsZero())
+}
+
+func TestFormatNegativePeriod(t *testing.T) {
+	// Negative periods are not supported by the formats, so make sure they are not silently parsed as something else.
+	negative := Period{Days: 3, Clock: time.Hour}.Negate()
+	_, ok, err := ISODuration(core.NewInput(FormatISODuration(negative)))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, ok, err = HumanDuration(core.NewInput(FormatHumanDuration(negative)))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
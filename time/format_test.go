@@ -0,0 +1,417 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+// Number of random values each round trip property is checked against.
+const roundTrips = 1000
+
+// Number of values parsed from generated text each round trip property is checked against, which are slower to find.
+const generatedRoundTrips = 100
+
+// roundTrip checks that parsing a formatted value consumes all of the input and returns an equal value.
+// Values are compared with assert.ObjectsAreEqual if equal is nil.
+func roundTrip[T any](t testing.TB, generate func(r *rand.Rand) T, format func(T) string, parser core.Parser[T], equal func(a, b T) bool) {
+	t.Helper()
+	checkRoundTrips(t, roundTrips, generate, format, parser, equal)
+}
+
+// generatedRoundTrip is roundTrip for values parsed from text generated from the parser's grammar, see Generate.
+// Values the format cannot represent are skipped if formattable is not nil.
+func generatedRoundTrip[T any](t testing.TB, format func(T) string, parser core.Parser[T], equal func(a, b T) bool, formattable func(T) bool) {
+	t.Helper()
+	generate := func(r *rand.Rand) T {
+		for range generatedRoundTrips {
+			text, ok := Generate(parser, r)
+			if !ok {
+				break
+			}
+			value, _, _ := parser(core.NewInput(text))
+			if formattable == nil || formattable(value) {
+				return value
+			}
+		}
+		t.Fatal("failed to generate text the parser accepts")
+		var zero T
+		return zero
+	}
+	checkRoundTrips(t, generatedRoundTrips, generate, format, parser, equal)
+}
+
+func checkRoundTrips[T any](t testing.TB, n int, generate func(r *rand.Rand) T, format func(T) string, parser core.Parser[T], equal func(a, b T) bool) {
+	t.Helper()
+	if equal == nil {
+		equal = func(a, b T) bool { return assert.ObjectsAreEqual(a, b) }
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	for range n {
+		want := generate(r)
+		text := format(want)
+		in := core.NewInput(text)
+		got, ok, err := parser(in)
+		if !assert.NoError(t, err, text) || !assert.True(t, ok, "%q did not parse", text) {
+			return
+		}
+		if remaining, more := in.Peek(1); !assert.False(t, more, "%q has remaining input %q", text, remaining) {
+			return
+		}
+		if !assert.True(t, equal(want, got), "%q parsed as %v, want %v", text, got, want) {
+			return
+		}
+	}
+}
+
+func randomDate(r *rand.Rand) time.Time {
+	return time.Date(1000+r.IntN(9000), time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, r.IntN(366))
+}
+
+// randomZone returns UTC or a fixed offset in whole minutes.
+func randomZone(r *rand.Rand) *time.Location {
+	if r.IntN(3) == 0 {
+		return time.UTC
+	}
+	offset := (r.IntN(26*60) - 12*60) * 60
+	if offset == 0 {
+		return time.UTC
+	}
+	return time.FixedZone("", offset)
+}
+
+func randomTime(r *rand.Rand) time.Time {
+	date := randomDate(r)
+	return time.Date(date.Year(), date.Month(), date.Day(), r.IntN(24), r.IntN(60), r.IntN(60), r.IntN(2)*r.IntN(1e9), randomZone(r))
+}
+
+func equalTimes(a, b time.Time) bool {
+	return a.Equal(b)
+}
+
+// fourDigitYear reports whether the time's year can be formatted, which is not the case for the results of adding large durations.
+func fourDigitYear(t time.Time) bool {
+	return t.Year() >= 0 && t.Year() <= 9999
+}
+
+func TestFormatDate(t *testing.T) {
+	t.Run("YearMonthDay", func(t *testing.T) {
+		roundTrip(t, randomDate, FormatYearMonthDay, YearMonthDay, nil)
+		generatedRoundTrip(t, FormatYearMonthDay, YearMonthDay, nil, nil)
+	})
+	t.Run("DayOfWeek", func(t *testing.T) {
+		roundTrip(t, func(r *rand.Rand) time.Weekday { return time.Weekday(r.IntN(7)) }, FormatDayOfWeek, DayOfWeek, nil)
+		generatedRoundTrip(t, FormatDayOfWeek, DayOfWeek, nil, nil)
+	})
+	t.Run("DaysOfWeek", func(t *testing.T) {
+		generate := func(r *rand.Rand) []time.Weekday {
+			days := make([]time.Weekday, 1+r.IntN(7))
+			for i := range days {
+				days[i] = time.Weekday(r.IntN(7))
+			}
+			return days
+		}
+		roundTrip(t, generate, FormatDaysOfWeek, DaysOfWeek, nil)
+		generatedRoundTrip(t, FormatDaysOfWeek, DaysOfWeek, nil, nil)
+	})
+	t.Run("MonthDay", func(t *testing.T) {
+		roundTrip(t, func(r *rand.Rand) int { return 1 + r.IntN(31) }, FormatMonthDay, MonthDay, nil)
+		generatedRoundTrip(t, FormatMonthDay, MonthDay, nil, nil)
+	})
+	t.Run("MonthNumber", func(t *testing.T) {
+		roundTrip(t, func(r *rand.Rand) time.Month { return time.Month(1 + r.IntN(12)) }, FormatMonthNumber, MonthNumber, nil)
+		generatedRoundTrip(t, FormatMonthNumber, MonthNumber, nil, nil)
+	})
+	t.Run("MonthOfYear", func(t *testing.T) {
+		roundTrip(t, func(r *rand.Rand) time.Month { return time.Month(1 + r.IntN(12)) }, FormatMonthOfYear, MonthOfYear, nil)
+		generatedRoundTrip(t, FormatMonthOfYear, MonthOfYear, nil, nil)
+	})
+	for _, order := range []DateOrder{DMY, MDY} {
+		t.Run(fmt.Sprintf("DayMonthYear %d", order), func(t *testing.T) {
+			format := func(date time.Time) string { return FormatDayMonthYear(order, date) }
+			roundTrip(t, randomDate, format, DayMonthYear(order), nil)
+			generatedRoundTrip(t, format, DayMonthYear(order), nil, nil)
+		})
+	}
+}
+
+func TestFormatLocale(t *testing.T) {
+	for _, locale := range []Locale{English, French, German, Spanish, Japanese} {
+		t.Run(locale.Name, func(t *testing.T) {
+			roundTrip(t, func(r *rand.Rand) time.Weekday { return time.Weekday(r.IntN(7)) }, locale.WeekdayName, WithLocale(locale, DayOfWeek), nil)
+			roundTrip(t, func(r *rand.Rand) time.Month { return time.Month(1 + r.IntN(12)) }, locale.MonthName, WithLocale(locale, MonthOfYear), nil)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	generate := func(r *rand.Rand) Period {
+		// Leave roughly half of the units out.
+		n := func(max int) int { return r.IntN(2) * r.IntN(max) }
+		return Period{
+			Years:  n(10),
+			Months: n(24),
+			Weeks:  n(10),
+			Days:   n(100),
+			Clock:  time.Duration(n(100))*time.Hour + time.Duration(n(60))*time.Minute + time.Duration(n(60))*time.Second + time.Duration(n(1e9)),
+		}
+	}
+	t.Run("ISODuration", func(t *testing.T) {
+		roundTrip(t, generate, FormatISODuration, ISODuration, nil)
+		generatedRoundTrip(t, FormatISODuration, ISODuration, nil, nil)
+	})
+	t.Run("HumanDuration", func(t *testing.T) {
+		roundTrip(t, generate, FormatHumanDuration, HumanDuration, nil)
+		generatedRoundTrip(t, FormatHumanDuration, HumanDuration, nil, nil)
+	})
+	t.Run("Duration", func(t *testing.T) {
+		roundTrip(t, generate, FormatISODuration, Duration, nil)
+		roundTrip(t, generate, FormatHumanDuration, Duration, nil)
+		generatedRoundTrip(t, FormatISODuration, Duration, nil, nil)
+	})
+	assert.Equal(t, "PT0S", FormatISODuration(Period{}))
+	assert.Equal(t, "0 seconds", FormatHumanDuration(Period{}))
+	assert.Equal(t, "1 year 2 weeks 1 hour 1.5 seconds", FormatHumanDuration(Period{Years: 1, Weeks: 2, Clock: time.Hour + 1500*time.Millisecond}))
+}
+
+func TestFormatISO8601(t *testing.T) {
+	generate := func(r *rand.Rand) Timestamp {
+		t := randomTime(r)
+		precision := Precision(r.IntN(int(SubsecondPrecision) + 1))
+		year, month, day := t.Date()
+		switch precision {
+		case YearPrecision:
+			t = time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+		case MonthPrecision:
+			t = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC)
+		case WeekPrecision:
+			t = time.Date(year, month, day-(int(t.Weekday())+6)%7, 0, 0, 0, 0, time.UTC)
+		case DayPrecision:
+			t = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		case HourPrecision:
+			t = time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+		case MinutePrecision:
+			t = time.Date(year, month, day, t.Hour(), t.Minute(), 0, 0, t.Location())
+		case SecondPrecision:
+			t = time.Date(year, month, day, t.Hour(), t.Minute(), t.Second(), 0, t.Location())
+		}
+		return Timestamp{Time: t, Precision: precision}
+	}
+	equal := func(a, b Timestamp) bool {
+		return a.Precision == b.Precision && a.Time.Equal(b.Time)
+	}
+	roundTrip(t, generate, FormatISO8601, ISO8601, equal)
+	generatedRoundTrip(t, FormatISO8601, ISO8601, equal, nil)
+}
+
+func TestFormatRFC3339(t *testing.T) {
+	roundTrip(t, randomTime, FormatRFC3339, RFC3339, equalTimes)
+	generatedRoundTrip(t, FormatRFC3339, RFC3339, equalTimes, nil)
+}
+
+func TestFormatTimeOfDay(t *testing.T) {
+	locations := []*time.Location{nil, time.UTC, time.FixedZone("", 2*60*60), time.FixedZone("", -(5*60+30)*60)}
+	for _, name := range []string{"Europe/London", "America/Argentina/Buenos_Aires"} {
+		if loc, err := time.LoadLocation(name); err == nil {
+			locations = append(locations, loc)
+		}
+	}
+	generate := func(r *rand.Rand) Clock {
+		return Clock{Hour: r.IntN(24), Minute: r.IntN(60), Second: r.IntN(2) * r.IntN(60), Nanosecond: r.IntN(2) * r.IntN(1e9), Location: locations[r.IntN(len(locations))]}
+	}
+	equal := func(a, b Clock) bool {
+		if a.Location == nil || b.Location == nil {
+			return a == b
+		}
+		date := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+		return a.On(date).Equal(b.On(date)) && a.Location.String() == b.Location.String()
+	}
+	roundTrip(t, generate, FormatTimeOfDay, TimeOfDay, equal)
+	generatedRoundTrip(t, FormatTimeOfDay, TimeOfDay, equal, nil)
+
+	assert.Equal(t, "14:30", FormatTimeOfDay(Clock{Hour: 14, Minute: 30}))
+	assert.Equal(t, "09:05:01.25+02:00", FormatTimeOfDay(Clock{Hour: 9, Minute: 5, Second: 1, Nanosecond: 250000000, Location: time.FixedZone("", 2*60*60)}))
+}
+
+func TestFormatDateTime(t *testing.T) {
+	ref := time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)
+	// Zones with daylight saving time, with and without a name DateTime accepts.
+	var zones []*time.Location
+	for _, name := range []string{"America/New_York", "Europe/London", "US/Eastern", "Local"} {
+		if loc, err := time.LoadLocation(name); err == nil {
+			zones = append(zones, loc)
+		}
+	}
+	generate := func(r *rand.Rand) time.Time {
+		t := randomTime(r)
+		if len(zones) == 0 || r.IntN(2) == 0 {
+			return t
+		}
+		// Recent years, as offsets before standard time were not whole minutes.
+		date := time.Date(1970+r.IntN(130), time.January, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, r.IntN(366))
+		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), zones[r.IntN(len(zones))])
+	}
+	roundTrip(t, generate, FormatDateTime, DateTime(ref, time.UTC), equalTimes)
+	generatedRoundTrip(t, FormatDateTime, DateTime(ref, time.UTC), equalTimes, fourDigitYear)
+
+	if london, err := time.LoadLocation("Europe/London"); err == nil {
+		assert.Equal(t, "2024-07-01 09:00 Europe/London", FormatDateTime(time.Date(2024, time.July, 1, 9, 0, 0, 0, london)))
+	}
+	if eastern, err := time.LoadLocation("US/Eastern"); err == nil {
+		assert.Equal(t, "2024-07-01 14:30-04:00", FormatDateTime(time.Date(2024, time.July, 1, 14, 30, 0, 0, eastern)))
+	}
+	if newYork, err := time.LoadLocation("America/New_York"); err == nil {
+		// 01:30 happens twice as the clocks go back, so the second one is written with its offset.
+		first := time.Date(2024, time.November, 3, 1, 30, 0, 0, newYork)
+		assert.Equal(t, "2024-11-03 01:30 America/New_York", FormatDateTime(first))
+		assert.Equal(t, "2024-11-03 01:30-05:00", FormatDateTime(first.Add(time.Hour)))
+	}
+}
+
+func TestFormatRelativeDate(t *testing.T) {
+	// 23:00 UTC on the 31st is already the 1st of February in Tokyo.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	ref := time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC)
+	today := time.Date(2024, time.February, 1, 0, 0, 0, 0, tokyo)
+	generate := func(r *rand.Rand) time.Time {
+		return today.AddDate(0, 0, r.IntN(801)-400)
+	}
+	format := func(date time.Time) string { return FormatRelativeDate(ref, tokyo, date) }
+	roundTrip(t, generate, format, RelativeDate(ref, tokyo), equalTimes)
+	// Only whole days can be formatted.
+	date := func(t time.Time) bool {
+		year, month, day := t.In(tokyo).Date()
+		return fourDigitYear(t) && t.Equal(time.Date(year, month, day, 0, 0, 0, 0, tokyo))
+	}
+	generatedRoundTrip(t, format, RelativeDate(ref, tokyo), equalTimes, date)
+
+	assert.Equal(t, "today", format(today))
+	assert.Equal(t, "yesterday", format(today.AddDate(0, 0, -1)))
+	assert.Equal(t, "in 3 days", format(today.AddDate(0, 0, 3)))
+	assert.Equal(t, "3 days ago", format(today.AddDate(0, 0, -3)))
+}
+
+func TestFormatInterval(t *testing.T) {
+	generate := func(r *rand.Rand) Span {
+		if r.IntN(2) == 0 {
+			start := randomDate(r)
+			return Span{Start: start, End: start.AddDate(0, 0, r.IntN(60)), Inclusive: true}
+		}
+		start := randomTime(r)
+		return Span{Start: start, End: start.Add(time.Duration(r.Int64N(int64(90 * 24 * time.Hour))))}
+	}
+	equal := func(a, b Span) bool {
+		return a.Inclusive == b.Inclusive && a.Start.Equal(b.Start) && a.End.Equal(b.End)
+	}
+	roundTrip(t, generate, FormatInterval, Interval(time.Now(), time.UTC), equal)
+	generatedRoundTrip(t, FormatInterval, Interval(time.Now(), time.UTC), equal, func(s Span) bool { return fourDigitYear(s.Start) && fourDigitYear(s.End) })
+}
+
+func TestFormatRRule(t *testing.T) {
+	generate := func(r *rand.Rand) RecurrenceRule {
+		rule := RecurrenceRule{Frequency: Frequency(r.IntN(4)), Interval: 1 + r.IntN(2)*r.IntN(10)}
+		for range r.IntN(2) * (1 + r.IntN(7)) {
+			rule.Weekdays = append(rule.Weekdays, time.Weekday(r.IntN(7)))
+		}
+		for range r.IntN(2) * (1 + r.IntN(5)) {
+			rule.MonthDays = append(rule.MonthDays, 1+r.IntN(31))
+		}
+		for range r.IntN(2) * (1 + r.IntN(3)) {
+			rule.Months = append(rule.Months, time.Month(1+r.IntN(12)))
+		}
+		switch r.IntN(4) {
+		case 0:
+			rule.Count = 1 + r.IntN(100)
+		case 1:
+			rule.Until = randomDate(r).AddDate(0, 0, 1).Add(-time.Nanosecond)
+		case 2:
+			rule.Until = randomTime(r).UTC().Truncate(time.Second)
+		}
+		return rule
+	}
+	equal := func(a, b RecurrenceRule) bool {
+		until := a.Until.Equal(b.Until)
+		a.Until, b.Until = time.Time{}, time.Time{}
+		return until && assert.ObjectsAreEqual(a, b)
+	}
+	roundTrip(t, generate, FormatRRule, RRule, equal)
+	roundTrip(t, generate, FormatRRule, Recurrence, equal)
+	generatedRoundTrip(t, FormatRRule, RRule, equal, nil)
+	// Only an RRULE with at most one of a count and an end date can be parsed back.
+	generatedRoundTrip(t, FormatRRule, Recurrence, equal, func(r RecurrenceRule) bool { return r.Count == 0 || r.Until.IsZero() })
+
+	assert.Equal(t, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20241231", FormatRRule(RecurrenceRule{
+		Frequency: Weekly,
+		Interval:  2,
+		Weekdays:  []time.Weekday{time.Monday, time.Wednesday},
+		Until:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC).Add(-time.Nanosecond),
+	}))
+}
+
+func TestFormatCron(t *testing.T) {
+	specs := []struct{ min, max int }{{0, 59}, {0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	item := func(r *rand.Rand, min, max int) string {
+		from := min + r.IntN(max-min+1)
+		step := ""
+		if r.IntN(3) == 0 {
+			step = fmt.Sprintf("/%d", 1+r.IntN(max))
+		}
+		switch r.IntN(4) {
+		case 0:
+			return "*" + step
+		case 1:
+			return fmt.Sprintf("%d-%d", from, from+r.IntN(max-from+1)) + step
+		}
+		return fmt.Sprint(from) + step
+	}
+	generate := func(r *rand.Rand) CronSchedule {
+		if r.IntN(10) == 0 {
+			every := time.Duration(1+r.IntN(1000)) * time.Minute
+			schedule, _, _ := Cron(core.NewInput("@every " + every.String()))
+			return schedule
+		}
+		fields := specs[r.IntN(2):]
+		expression := make([]string, len(fields))
+		for i, spec := range fields {
+			items := make([]string, 1+r.IntN(2)*r.IntN(4))
+			for j := range items {
+				items[j] = item(r, spec.min, spec.max)
+			}
+			expression[i] = strings.Join(items, ",")
+		}
+		schedule, ok, err := Cron(core.NewInput(strings.Join(expression, " ")))
+		if !ok || err != nil {
+			t.Fatalf("generated invalid expression %q: %v", strings.Join(expression, " "), err)
+		}
+		return schedule
+	}
+	roundTrip(t, generate, FormatCron, Cron, nil)
+	generatedRoundTrip(t, FormatCron, Cron, nil, nil)
+
+	for _, expression := range []string{"0,15,30,45 9-17 * * 1-5", "0 0 1 1 *", "30 0 0 */31,15 * *", "@every PT1H30M"} {
+		schedule, _, err := Cron(core.NewInput(expression))
+		assert.NoError(t, err)
+		assert.Equal(t, expression, FormatCron(schedule))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+var fuzzRef = time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)
+
+func FuzzYearMonthDay(f *testing.F) {
+	FuzzRoundTrip(f, YearMonthDay, FormatYearMonthDay, equalTimes, "2024-02-01", "2024-02-30", "0000-01-01")
+}
+
+func FuzzDayMonthYear(f *testing.F) {
+	FuzzRoundTrip(f, DayMonthYear(DMY), func(t time.Time) string { return FormatDayMonthYear(DMY, t) }, equalTimes,
+		"3rd March 2024", "the 3rd of March, 2024", "March 3, 2024", "03/04/2024", "31.12.2024")
+}
+
+func FuzzMonthDay(f *testing.F) {
+	FuzzRoundTrip(f, MonthDay, FormatMonthDay, nil, "1st", "22nd", "11th", "31", "2024")
+}
+
+func FuzzDuration(f *testing.F) {
+	FuzzRoundTrip(f, Duration, FormatHumanDuration, nil, "P1Y2M3DT4H5M6.5S", "1 hour and 30 minutes", "1.5h", "2w 3d", "PT0,5S")
+}
+
+func FuzzISODuration(f *testing.F) {
+	FuzzRoundTrip(f, ISODuration, FormatISODuration, nil, "P1Y2M3DT4H5M6.5S", "P2W", "PT36H", "PT0,5S")
+}
+
+func FuzzISO8601(f *testing.F) {
+	equal := func(a, b Timestamp) bool { return a.Precision == b.Precision && a.Time.Equal(b.Time) }
+	FuzzRoundTrip(f, ISO8601, FormatISO8601, equal, "2024-02-01T14:30:05.123+02:00", "20240201T143005Z", "2024-W05-3", "2024-032", "2024-02", "2024")
+}
+
+func FuzzRFC3339(f *testing.F) {
+	FuzzRoundTrip(f, RFC3339, FormatRFC3339, equalTimes, "2024-02-01T14:30:05Z", "2024-02-01 14:30:05.5-05:30")
+}
+
+func FuzzTimeOfDay(f *testing.F) {
+	equal := func(a, b Clock) bool {
+		if a.Location == nil || b.Location == nil {
+			return a == b
+		}
+		date := time.Date(2024, time.June, 1, 0, 0, 0, 0, time.UTC)
+		return a.On(date).Equal(b.On(date))
+	}
+	FuzzRoundTrip(f, TimeOfDay, FormatTimeOfDay, equal, "14:30:05.5 UTC", "2:30 p.m.", "12am", "noon", "9am +05:30", "9am Europe/London")
+}
+
+func FuzzDateTime(f *testing.F) {
+	FuzzRoundTrip(f, DateTime(fuzzRef, time.UTC), FormatDateTime, equalTimes, "2024-02-01T14:30", "tomorrow at noon", "9am on friday", "next monday, 17:00 UTC", "14:30 on 1 March 2024")
+}
+
+func FuzzRelativeDate(f *testing.F) {
+	FuzzParser(f, RelativeDate(fuzzRef, time.UTC), "the day after tomorrow", "next friday", "in 3 days", "2 weeks ago", "the 3rd of next month")
+}
+
+func FuzzInterval(f *testing.F) {
+	equal := func(a, b Span) bool {
+		return a.Inclusive == b.Inclusive && a.Start.Equal(b.Start) && a.End.Equal(b.End)
+	}
+	FuzzRoundTrip(f, Interval(fuzzRef, time.UTC), FormatInterval, equal, "2024-01-01..2024-01-31", "mon-fri", "jan to mar", "this week", "2024-01-01/P1M", "P1W/2024-01-08")
+}
+
+func FuzzRecurrence(f *testing.F) {
+	FuzzParser(f, Recurrence, "every 2 weeks on mon and wed until 2024-12-31", "RRULE:FREQ=MONTHLY;BYMONTHDAY=1,15;COUNT=10", "daily for 3 times", "every other month on the 1st of jan")
+}
+
+func FuzzRRule(f *testing.F) {
+	FuzzRoundTrip(f, RRule, FormatRRule, nil, "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;UNTIL=20241231", "FREQ=YEARLY;BYMONTH=1,2;COUNT=3", "FREQ=DAILY;UNTIL=20240131T090000Z")
+}
+
+func FuzzCron(f *testing.F) {
+	FuzzRoundTrip(f, Cron, FormatCron, nil, "*/15 9-17 * * MON-FRI", "0 0 1 1 *", "30 */5 0 ? * 7", "@every 1h30m", "@weekly")
+}
@@ -0,0 +1,35 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"flag"
+	"testing"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+// Run go test -update to write the golden files after an intended change.
+var _ = flag.Bool("update", false, "update golden files")
+
+func TestGolden(t *testing.T) {
+	t.Run("Recurrence", func(t *testing.T) {
+		RunGolden(t, GoldenTest[RecurrenceRule]{Dir: "testdata/recurrence", Parser: Recurrence})
+	})
+	t.Run("Cron", func(t *testing.T) {
+		RunGolden(t, GoldenTest[CronSchedule]{Dir: "testdata/cron", Parser: Cron, Format: FormatCron})
+	})
+}
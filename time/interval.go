@@ -0,0 +1,248 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Span is a range of time starting at Start.
+type Span struct {
+	Start time.Time
+	End   time.Time
+	// Inclusive reports whether End is part of the span. Inclusive spans are always whole days and End is midnight at
+	// the start of the last day, e.g. mon-fri ends at the start of friday. Exclusive spans end just before End,
+	// e.g. 2024-01-01/P1M ends at the start of february.
+	Inclusive bool
+}
+
+// HalfOpen returns the start of the span and the first instant after it.
+func (s Span) HalfOpen() (start, end time.Time) {
+	if s.Inclusive {
+		return s.Start, s.End.AddDate(0, 0, 1)
+	}
+	return s.Start, s.End
+}
+
+// Contains reports whether the time is within the span.
+func (s Span) Contains(t time.Time) bool {
+	start, end := s.HalfOpen()
+	return !t.Before(start) && t.Before(end)
+}
+
+// Interval parses a range of dates, resolving any that are relative against the reference time in the given location.
+//
+// The following forms are supported, where a range separator is .., -, –, to, through or thru:
+//   - 2024-01-01..2024-01-31: the days between two dates, inclusive
+//   - mon-fri: the days between two weekdays, starting in the week (monday to sunday) containing the reference, inclusive
+//   - jan to mar, nov-feb 2025: the days between two months, starting in the reference year unless a year is given for the
+//     end month, inclusive; the end month is in the following year if it is before the start month
+//   - this/next/last week, month, year or weekend: the days of that period, weeks start on monday, inclusive
+//   - 2024-01-01/2024-01-31, 2024-01/2024-03: an ISO 8601 interval of dates, inclusive of the whole end date
+//   - 2024-01-01T09:00/2024-01-01T17:00, 2024-01-01/P1M, P1W/2024-01-08: ISO 8601 intervals with a time or duration, exclusive
+//
+// An interval whose end is before its start is an error.
+func Interval(ref time.Time, loc *time.Location) Parser[Span] {
+	now := ref.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	parser := Longest(
+		dateRange(loc),
+		isoInterval(loc),
+		weekdayRange(today),
+		monthRange(today),
+		relativeSpan(today),
+	)
+	return func(in Input) (Span, bool, error) {
+		start := in.Checkpoint()
+		span, ok, err := parser(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		if !wordEnd(in) {
+			in.Restore(start)
+			return Span{}, false, nil
+		}
+		return span, true, nil
+	}
+}
+
+var rangeSeparator = Longest(
+	String(".."),
+	StringFrom(OptionalInlineWhitespace, RuneIn("-–"), OptionalInlineWhitespace),
+	StringFrom(InlineWhitespace, Longest(StringInsensitive("to"), StringInsensitive("through"), StringInsensitive("thru")), InlineWhitespace),
+)
+
+// inclusiveSpan returns an inclusive span of days, or an error at the position of the end if it is before the start.
+func inclusiveSpan(start, end time.Time, position int) (Span, error) {
+	if end.Before(start) {
+		return Span{}, fmt.Errorf("failed to parse interval: end %s is before start %s at position %d", end.Format(time.DateOnly), start.Format(time.DateOnly), position)
+	}
+	return Span{Start: start, End: end, Inclusive: true}, nil
+}
+
+func dateRange(loc *time.Location) Parser[Span] {
+	return func(in Input) (Span, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := SequenceOf3(YearMonthDay, rangeSeparator, positioned(YearMonthDay))(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		from, _, to := m.Values()
+		span, err := inclusiveSpan(inLocation(from, loc), inLocation(to.value, loc), to.position)
+		if err != nil {
+			in.Restore(start)
+			return Span{}, false, err
+		}
+		return span, true, nil
+	}
+}
+
+// inLocation returns midnight on the same day in the location.
+func inLocation(date time.Time, loc *time.Location) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+}
+
+func isoInterval(loc *time.Location) Parser[Span] {
+	// start/end
+	startEnd := func(in Input) (Span, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := SequenceOf3(ISO8601, Rune('/'), positioned(ISO8601))(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		from, _, to := m.Values()
+		var span Span
+		if from.Precision <= DayPrecision && to.value.Precision <= DayPrecision {
+			span, err = inclusiveSpan(inLocation(from.Time, loc), inLocation(to.value.endOf(), loc), to.position)
+		} else if span = (Span{Start: from.Time, End: to.value.Time}); span.End.Before(span.Start) {
+			err = fmt.Errorf("failed to parse interval: end %s is before start %s at position %d", span.End.Format(time.RFC3339), span.Start.Format(time.RFC3339), to.position)
+		}
+		if err != nil {
+			in.Restore(start)
+			return Span{}, false, err
+		}
+		return span, true, nil
+	}
+	// start/duration
+	startDuration := func(in Input) (Span, bool, error) {
+		m, ok, err := SequenceOf3(ISO8601, Rune('/'), ISODuration)(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		from, _, period := m.Values()
+		start := isoStart(from, loc)
+		return Span{Start: start, End: period.AddTo(start)}, true, nil
+	}
+	// duration/end
+	durationEnd := func(in Input) (Span, bool, error) {
+		m, ok, err := SequenceOf3(ISODuration, Rune('/'), ISO8601)(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		period, _, to := m.Values()
+		end := isoStart(to, loc)
+		return Span{Start: period.Negate().AddTo(end), End: end}, true, nil
+	}
+	return Longest(startEnd, startDuration, durationEnd)
+}
+
+// isoStart returns the time of the timestamp, at midnight in the location if it has no time.
+func isoStart(t Timestamp, loc *time.Location) time.Time {
+	if t.Precision <= DayPrecision {
+		return inLocation(t.Time, loc)
+	}
+	return t.Time
+}
+
+func weekdayRange(today time.Time) Parser[Span] {
+	return func(in Input) (Span, bool, error) {
+		m, ok, err := SequenceOf3(DayOfWeek, rangeSeparator, DayOfWeek)(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		from, _, to := m.Values()
+		monday := today.AddDate(0, 0, -daysSinceMonday(today.Weekday()))
+		start := monday.AddDate(0, 0, daysSinceMonday(from))
+		return Span{Start: start, End: start.AddDate(0, 0, (int(to)-int(from)+7)%7), Inclusive: true}, true, nil
+	}
+}
+
+// daysSinceMonday returns the number of days from monday to the weekday, e.g. 6 for sunday.
+func daysSinceMonday(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+func monthRange(today time.Time) Parser[Span] {
+	return func(in Input) (Span, bool, error) {
+		m, ok, err := SequenceOf4(MonthOfYear, rangeSeparator, MonthOfYear, Optional(SequenceOf2(InlineWhitespace, isoNumber(4))))(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		from, _, to, year := m.Values()
+		startYear, endYear := today.Year(), today.Year()
+		if to < from {
+			endYear++
+		}
+		if year.Ok() {
+			_, y := year.Values().Values()
+			startYear, endYear = y-(endYear-startYear), y
+		}
+		start := time.Date(startYear, from, 1, 0, 0, 0, 0, today.Location())
+		end := time.Date(endYear, to, daysInMonth(endYear, to), 0, 0, 0, 0, today.Location())
+		return Span{Start: start, End: end, Inclusive: true}, true, nil
+	}
+}
+
+func relativeSpan(today time.Time) Parser[Span] {
+	units := oneOf(map[string]string{"week": "week", "weekend": "weekend", "month": "month", "year": "year"})
+	return func(in Input) (Span, bool, error) {
+		m, ok, err := SequenceOf3(modifier, InlineWhitespace, units)(in)
+		if err != nil || !ok {
+			return Span{}, false, err
+		}
+		which, _, unit := m.Values()
+		offset := map[relativeModifier]int{thisModifier: 0, nextModifier: 1, lastModifier: -1}[which]
+
+		var start, end time.Time
+		switch unit {
+		case "week", "weekend":
+			monday := today.AddDate(0, 0, -daysSinceMonday(today.Weekday())+offset*7)
+			start, end = monday, monday.AddDate(0, 0, 6)
+			if unit == "weekend" {
+				start = monday.AddDate(0, 0, 5)
+			}
+		case "month":
+			start = time.Date(today.Year(), today.Month()+time.Month(offset), 1, 0, 0, 0, 0, today.Location())
+			end = start.AddDate(0, 1, -1)
+		case "year":
+			start = time.Date(today.Year()+offset, time.January, 1, 0, 0, 0, 0, today.Location())
+			end = start.AddDate(1, 0, -1)
+		}
+		return Span{Start: start, End: end, Inclusive: true}, true, nil
+	}
+}
+
+// FormatInterval formats the span as a range of dates (2024-01-01..2024-01-31) if it is inclusive, otherwise as an
+// ISO 8601 interval of times (2024-01-01T00:00:00Z/2024-02-01T00:00:00Z), which Interval parses back to the same span.
+// Inclusive spans are written without their times, so they must start and end at midnight in the location passed to Interval.
+func FormatInterval(s Span) string {
+	if s.Inclusive {
+		return FormatYearMonthDay(s.Start) + ".." + FormatYearMonthDay(s.End)
+	}
+	return FormatRFC3339(s.Start) + "/" + FormatRFC3339(s.End)
+}
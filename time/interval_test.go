@@ -0,0 +1,224 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+func TestInterval(t *testing.T) {
+	// Wednesday the 31st of January 2024.
+	parser := Interval(time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC), time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	days := func(start, end time.Time) Span {
+		return Span{Start: start, End: end, Inclusive: true}
+	}
+
+	tests := []ParserTest[Span]{
+		{
+			Name:          "date range",
+			Input:         "2024-01-01..2024-01-31",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.January, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "date range with words",
+			Input:         "2024-01-01 to 2024-01-31",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.January, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "single day date range",
+			Input:         "2024-01-01 - 2024-01-01",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.January, 1)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "date range ending before it starts",
+			Input:          "2024-01-31..2024-01-01",
+			Parser:         parser,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-01-31..2024-01-01",
+		},
+		{
+			Name:           "date range with an invalid date",
+			Input:          "2024-01-01..2024-02-30",
+			Parser:         parser,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-01-01..2024-02-30",
+		},
+		{
+			Name:          "weekday range",
+			Input:         "mon-fri",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 29), date(2024, time.February, 2)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "weekday range across the end of the week",
+			Input:         "Friday to Monday",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.February, 2), date(2024, time.February, 5)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "month range",
+			Input:         "jan to mar",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.March, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "month range across the end of the year",
+			Input:         "nov–feb",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.November, 1), date(2025, time.February, 28)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "month range with a year",
+			Input:         "nov through feb 2024",
+			Parser:        parser,
+			ExpectedMatch: days(date(2023, time.November, 1), date(2024, time.February, 29)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "this week",
+			Input:         "this week",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 29), date(2024, time.February, 4)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next weekend",
+			Input:         "next weekend",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.February, 10), date(2024, time.February, 11)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next month",
+			Input:         "Next Month",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.February, 1), date(2024, time.February, 29)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "last year",
+			Input:         "last year",
+			Parser:        parser,
+			ExpectedMatch: days(date(2023, time.January, 1), date(2023, time.December, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "iso dates",
+			Input:         "2024-01-01/2024-01-31",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.January, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "iso months",
+			Input:         "2024-01/2024-03",
+			Parser:        parser,
+			ExpectedMatch: days(date(2024, time.January, 1), date(2024, time.March, 31)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:   "iso times",
+			Input:  "2024-01-01T09:00Z/2024-01-01T17:00Z",
+			Parser: parser,
+			ExpectedMatch: Span{
+				Start: time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC),
+				End:   time.Date(2024, time.January, 1, 17, 0, 0, 0, time.UTC),
+			},
+			ExpectedOK: true,
+		},
+		{
+			Name:           "iso times ending before they start",
+			Input:          "2024-01-01T17:00Z/2024-01-01T09:00Z",
+			Parser:         parser,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-01-01T17:00Z/2024-01-01T09:00Z",
+		},
+		{
+			Name:          "iso start and duration",
+			Input:         "2024-01-01/P1M",
+			Parser:        parser,
+			ExpectedMatch: Span{Start: date(2024, time.January, 1), End: date(2024, time.February, 1)},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "iso duration and end",
+			Input:         "P1W/2024-01-08",
+			Parser:        parser,
+			ExpectedMatch: Span{Start: date(2024, time.January, 1), End: date(2024, time.January, 8)},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "remaining input",
+			Input:          "mon-fri at 9am",
+			Parser:         parser,
+			ExpectedMatch:  days(date(2024, time.January, 29), date(2024, time.February, 2)),
+			ExpectedOK:     true,
+			RemainingInput: " at 9am",
+		},
+		{
+			Name:           "not a word boundary",
+			Input:          "this weekday",
+			Parser:         parser,
+			ExpectedOK:     false,
+			RemainingInput: "this weekday",
+		},
+		{
+			Name:           "not an interval",
+			Input:          "2024-01-01",
+			Parser:         parser,
+			ExpectedOK:     false,
+			RemainingInput: "2024-01-01",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestSpan(t *testing.T) {
+	inclusive := Span{Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC), Inclusive: true}
+	exclusive := Span{Start: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), End: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)}
+
+	start, end := inclusive.HalfOpen()
+	assert.Equal(t, exclusive.Start, start)
+	assert.Equal(t, exclusive.End, end)
+
+	for _, span := range []Span{inclusive, exclusive} {
+		assert.True(t, span.Contains(time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, span.Contains(time.Date(2024, time.January, 31, 23, 59, 0, 0, time.UTC)))
+		assert.False(t, span.Contains(time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, span.Contains(time.Date(2023, time.December, 31, 23, 59, 0, 0, time.UTC)))
+	}
+}
@@ -0,0 +1,492 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Precision is the smallest unit that was specified in a parsed date or time.
+type Precision int
+
+const (
+	YearPrecision Precision = iota
+	MonthPrecision
+	WeekPrecision
+	DayPrecision
+	HourPrecision
+	MinutePrecision
+	SecondPrecision
+	SubsecondPrecision
+)
+
+func (p Precision) String() string {
+	switch p {
+	case YearPrecision:
+		return "year"
+	case MonthPrecision:
+		return "month"
+	case WeekPrecision:
+		return "week"
+	case DayPrecision:
+		return "day"
+	case HourPrecision:
+		return "hour"
+	case MinutePrecision:
+		return "minute"
+	case SecondPrecision:
+		return "second"
+	case SubsecondPrecision:
+		return "subsecond"
+	}
+	return fmt.Sprintf("Precision(%d)", int(p))
+}
+
+// Timestamp is a point in time along with the precision it was specified to.
+// Unspecified fields are set to their earliest value, e.g. 2024-05 is midnight on the 1st of May.
+type Timestamp struct {
+	Time      time.Time
+	Precision Precision
+}
+
+// ISO8601 parses the ISO 8601 family of dates and date times in either basic or extended format.
+//
+// Dates may be calendar dates (2024-02-01, 20240201), reduced precision calendar dates (2024-02, 2024),
+// week dates (2024-W05-3, 2024W053, 2024-W05) or ordinal dates (2024-032, 2024032).
+// They may be followed by T and a time (14:30:05.123, 143005,123, 14:30, 14) with an optional Z or ±hh:mm offset.
+// Times without an offset are returned in UTC.
+var ISO8601 = func(in Input) (Timestamp, bool, error) {
+	start := in.Checkpoint()
+	d, ok, err := isoDate(in)
+	if err != nil || !ok {
+		return Timestamp{}, false, err
+	}
+
+	var t isoTimeFields
+	loc := time.UTC
+	_, ok, err = Rune('T')(in)
+	if err != nil {
+		in.Restore(start)
+		return Timestamp{}, false, err
+	}
+	if ok {
+		t, ok, err = Longest(isoTimeExtended, isoTimeBasic)(in)
+		if err != nil || !ok {
+			in.Restore(start)
+			return Timestamp{}, false, err
+		}
+		offset, ok, err := isoOffset(in)
+		if err != nil {
+			in.Restore(start)
+			return Timestamp{}, false, err
+		}
+		if ok {
+			loc = offset
+		}
+	}
+
+	match, err := d.resolve(t, loc)
+	if err != nil {
+		in.Restore(start)
+		return Timestamp{}, false, err
+	}
+	precision := d.precision
+	if t.precision > precision {
+		precision = t.precision
+	}
+	return Timestamp{Time: match, Precision: precision}, true, nil
+}
+
+// RFC3339 parses a full date time in the format yyyy-MM-ddTHH:mm:ss[.fraction](Z|±hh:mm).
+// As permitted by the RFC the T and Z may be lowercase and T may be replaced with a space.
+var RFC3339 = func(in Input) (time.Time, bool, error) {
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf4(isoCalendarDateExtended, RuneIn("Tt "), isoTimeExtended, rfc3339Offset)(in)
+	if err != nil || !ok {
+		return time.Time{}, false, err
+	}
+	d, _, t, loc := m.Values()
+	if d.precision != DayPrecision || t.precision < SecondPrecision {
+		in.Restore(start)
+		return time.Time{}, false, nil
+	}
+	match, err := d.resolve(t, loc)
+	if err != nil {
+		in.Restore(start)
+		return time.Time{}, false, err
+	}
+	return match, true, nil
+}
+
+type isoDateKind int
+
+const (
+	calendarDate isoDateKind = iota
+	weekDate
+	ordinalDate
+)
+
+// isoDateFields is the raw fields of a date before they are validated.
+type isoDateFields struct {
+	kind             isoDateKind
+	year, month, day int
+	week, weekday    int
+	ordinal          int
+	precision        Precision
+}
+
+// Resolve validates the date and combines it with the time.
+func (d isoDateFields) resolve(t isoTimeFields, loc *time.Location) (time.Time, error) {
+	if t.hour == 24 {
+		if t.minute != 0 || t.second != 0 || t.nanosecond != 0 {
+			return time.Time{}, fmt.Errorf("failed to parse time: hour 24 must be followed by zero minutes and seconds")
+		}
+	} else if t.hour > 23 {
+		return time.Time{}, fmt.Errorf("failed to parse time: hour %d out of range", t.hour)
+	}
+	if t.minute > 59 {
+		return time.Time{}, fmt.Errorf("failed to parse time: minute %d out of range", t.minute)
+	}
+	// Allow for leap seconds.
+	if t.second > 60 {
+		return time.Time{}, fmt.Errorf("failed to parse time: second %d out of range", t.second)
+	}
+
+	var date time.Time
+	switch d.kind {
+	case weekDate:
+		if weeks := isoWeeksInYear(d.year); d.week < 1 || d.week > weeks {
+			return time.Time{}, fmt.Errorf("failed to parse date: week %d out of range, %d has %d weeks", d.week, d.year, weeks)
+		}
+		weekday := d.weekday
+		if d.precision < DayPrecision {
+			weekday = 1
+		}
+		if weekday < 1 || weekday > 7 {
+			return time.Time{}, fmt.Errorf("failed to parse date: weekday %d out of range", weekday)
+		}
+		date = isoWeekStart(d.year, loc).AddDate(0, 0, (d.week-1)*7+weekday-1)
+	case ordinalDate:
+		if days := daysInYear(d.year); d.ordinal < 1 || d.ordinal > days {
+			return time.Time{}, fmt.Errorf("failed to parse date: day %d out of range, %d has %d days", d.ordinal, d.year, days)
+		}
+		date = time.Date(d.year, time.January, d.ordinal, 0, 0, 0, 0, loc)
+	default:
+		month, day := d.month, d.day
+		if d.precision < MonthPrecision {
+			month = 1
+		}
+		if d.precision < DayPrecision {
+			day = 1
+		}
+		if month < 1 || month > 12 {
+			return time.Time{}, fmt.Errorf("failed to parse date: month %d out of range", month)
+		}
+		if days := daysInMonth(d.year, time.Month(month)); day < 1 || day > days {
+			return time.Time{}, fmt.Errorf("failed to parse date: day %d out of range, %s %d has %d days", day, time.Month(month), d.year, days)
+		}
+		date = time.Date(d.year, time.Month(month), day, 0, 0, 0, 0, loc)
+	}
+
+	year, month, day := date.Date()
+	return time.Date(year, month, day, t.hour, t.minute, t.second, t.nanosecond, loc), nil
+}
+
+// isoWeekStart returns the Monday of the first ISO week of the year, which is the week containing the 4th of January.
+func isoWeekStart(year int, loc *time.Location) time.Time {
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	return jan4.AddDate(0, 0, -((int(jan4.Weekday()) + 6) % 7))
+}
+
+// The 28th of December is always in the last ISO week of the year.
+func isoWeeksInYear(year int) int {
+	_, weeks := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return weeks
+}
+
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// isoTimeFields is the raw fields of a time before they are validated.
+type isoTimeFields struct {
+	hour, minute, second, nanosecond int
+	precision                        Precision
+}
+
+var isoDigit = RuneIn("0123456789")
+
+// isoNumber parses exactly n ASCII digits.
+func isoNumber(n int) Parser[int] {
+	digits := WithGenerator(StringFrom(SkipTimes(n, isoDigit)), func(r *rand.Rand) string {
+		return fmt.Sprintf("%0*d", n, isoNumberRange[n][0]+r.IntN(isoNumberRange[n][1]-isoNumberRange[n][0]+1))
+	})
+	return func(in Input) (int, bool, error) {
+		s, ok, err := digits(in)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		number, err := strconv.Atoi(s)
+		if err != nil {
+			return 0, false, fmt.Errorf("failed to parse number: %w", err)
+		}
+		return number, true, nil
+	}
+}
+
+// Ranges that numbers of each width are generated in, which are valid for any field of that width, e.g. 1 to 12 is
+// a valid month, day, week, hour, minute or second.
+var isoNumberRange = map[int][2]int{1: {1, 7}, 2: {1, 12}, 3: {1, 365}, 4: {0, 9999}}
+
+// Parse a date in any of the calendar, week or ordinal formats, preferring whichever consumes the most input.
+var isoDate = Longest(
+	isoCalendarDateExtended,
+	isoCalendarDateBasic,
+	isoWeekDateExtended,
+	isoWeekDateBasic,
+	isoOrdinalDateExtended,
+	isoOrdinalDateBasic,
+)
+
+// yyyy, yyyy-MM or yyyy-MM-dd
+var isoCalendarDateExtended = func(in Input) (isoDateFields, bool, error) {
+	m, ok, err := SequenceOf3(isoNumber(4), Optional(isoPrefixed("-", isoNumber(2))), Optional(isoPrefixed("-", isoNumber(2))))(in)
+	if err != nil || !ok {
+		return isoDateFields{}, false, err
+	}
+	year, month, day := m.Values()
+	d := isoDateFields{kind: calendarDate, year: year, precision: YearPrecision}
+	if month.Ok() {
+		d.month, d.precision = month.Values(), MonthPrecision
+		if day.Ok() {
+			d.day, d.precision = day.Values(), DayPrecision
+		}
+	}
+	return d, true, nil
+}
+
+// yyyyMMdd
+var isoCalendarDateBasic = func(in Input) (isoDateFields, bool, error) {
+	m, ok, err := SequenceOf3(isoNumber(4), isoNumber(2), isoNumber(2))(in)
+	if err != nil || !ok {
+		return isoDateFields{}, false, err
+	}
+	year, month, day := m.Values()
+	return isoDateFields{kind: calendarDate, year: year, month: month, day: day, precision: DayPrecision}, true, nil
+}
+
+// yyyy-Www or yyyy-Www-D
+var isoWeekDateExtended = isoWeekDate("-W", "-")
+
+// yyyyWww or yyyyWwwD
+var isoWeekDateBasic = isoWeekDate("W", "")
+
+func isoWeekDate(weekSeparator, daySeparator string) Parser[isoDateFields] {
+	return func(in Input) (isoDateFields, bool, error) {
+		m, ok, err := SequenceOf3(isoNumber(4), isoPrefixed(weekSeparator, isoNumber(2)), Optional(isoPrefixed(daySeparator, isoNumber(1))))(in)
+		if err != nil || !ok {
+			return isoDateFields{}, false, err
+		}
+		year, week, weekday := m.Values()
+		d := isoDateFields{kind: weekDate, year: year, week: week, precision: WeekPrecision}
+		if weekday.Ok() {
+			d.weekday, d.precision = weekday.Values(), DayPrecision
+		}
+		return d, true, nil
+	}
+}
+
+// yyyy-DDD
+var isoOrdinalDateExtended = isoOrdinalDate("-")
+
+// yyyyDDD
+var isoOrdinalDateBasic = isoOrdinalDate("")
+
+func isoOrdinalDate(separator string) Parser[isoDateFields] {
+	return func(in Input) (isoDateFields, bool, error) {
+		m, ok, err := SequenceOf2(isoNumber(4), isoPrefixed(separator, isoNumber(3)))(in)
+		if err != nil || !ok {
+			return isoDateFields{}, false, err
+		}
+		year, day := m.Values()
+		return isoDateFields{kind: ordinalDate, year: year, ordinal: day, precision: DayPrecision}, true, nil
+	}
+}
+
+// isoPrefixed matches the prefix followed by the parser, returning the parser's match.
+func isoPrefixed(prefix string, parser Parser[int]) Parser[int] {
+	return func(in Input) (int, bool, error) {
+		m, ok, err := SequenceOf2(String(prefix), parser)(in)
+		if err != nil || !ok {
+			return 0, false, err
+		}
+		_, match := m.Values()
+		return match, true, nil
+	}
+}
+
+// HH, HH:mm, HH:mm:ss or HH:mm:ss.fraction
+var isoTimeExtended = isoTimeWithSeparator(":")
+
+// HH, HHmm, HHmmss or HHmmss.fraction
+var isoTimeBasic = isoTimeWithSeparator("")
+
+func isoTimeWithSeparator(separator string) Parser[isoTimeFields] {
+	return func(in Input) (isoTimeFields, bool, error) {
+		hour, ok, err := isoNumber(2)(in)
+		if err != nil || !ok {
+			return isoTimeFields{}, false, err
+		}
+		t := isoTimeFields{hour: hour, precision: HourPrecision}
+
+		minute, ok, err := isoPrefixed(separator, isoNumber(2))(in)
+		if err != nil {
+			return isoTimeFields{}, false, err
+		}
+		if !ok {
+			return t, true, nil
+		}
+		t.minute, t.precision = minute, MinutePrecision
+
+		second, ok, err := isoPrefixed(separator, isoNumber(2))(in)
+		if err != nil {
+			return isoTimeFields{}, false, err
+		}
+		if !ok {
+			return t, true, nil
+		}
+		t.second, t.precision = second, SecondPrecision
+
+		nanosecond, ok, err := isoFraction(in)
+		if err != nil {
+			return isoTimeFields{}, false, err
+		}
+		if ok {
+			t.nanosecond = nanosecond
+			t.precision = SubsecondPrecision
+		}
+		return t, true, nil
+	}
+}
+
+// Parse a decimal fraction of a second, separated by either a period or comma, into nanoseconds.
+// Digits beyond nanosecond precision are truncated.
+var isoFraction = func(in Input) (int, bool, error) {
+	m, ok, err := SequenceOf2(RuneIn(".,"), StringFrom(SkipOneOrMore(isoDigit)))(in)
+	if err != nil || !ok {
+		return 0, false, err
+	}
+	_, digits := m.Values()
+	if len(digits) > 9 {
+		digits = digits[:9]
+	}
+	digits += strings.Repeat("0", 9-len(digits))
+	nanosecond, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to parse fraction: %w", err)
+	}
+	return nanosecond, true, nil
+}
+
+// Z, ±HH:mm, ±HHmm or ±HH
+var isoOffset = func(in Input) (*time.Location, bool, error) {
+	_, ok, err := Rune('Z')(in)
+	if err != nil || ok {
+		return time.UTC, ok, err
+	}
+	m, ok, err := SequenceOf3(RuneIn("+-"), isoNumber(2), Optional(Longest(isoPrefixed(":", isoNumber(2)), isoNumber(2))))(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	sign, hours, minutes := m.Values()
+	return fixedZone(sign, hours, minutes.Values())
+}
+
+// Z or ±HH:mm, either case.
+var rfc3339Offset = func(in Input) (*time.Location, bool, error) {
+	_, ok, err := RuneIn("Zz")(in)
+	if err != nil || ok {
+		return time.UTC, ok, err
+	}
+	m, ok, err := SequenceOf4(RuneIn("+-"), isoNumber(2), Rune(':'), isoNumber(2))(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	sign, hours, _, minutes := m.Values()
+	return fixedZone(sign, hours, minutes)
+}
+
+func fixedZone(sign string, hours, minutes int) (*time.Location, bool, error) {
+	if hours > 23 || minutes > 59 {
+		return nil, false, fmt.Errorf("failed to parse offset: %s%02d:%02d out of range", sign, hours, minutes)
+	}
+	offset := hours*60*60 + minutes*60
+	if offset == 0 {
+		return time.UTC, true, nil
+	}
+	if sign == "-" {
+		offset = -offset
+	}
+	return time.FixedZone("", offset), true, nil
+}
+
+// FormatISO8601 formats the timestamp in the ISO 8601 extended format to its precision, e.g. 2024-02, 2024-W05 or
+// 2024-02-01T14:30Z, the inverse of ISO8601. Times are written with their offset, or Z for UTC.
+func FormatISO8601(ts Timestamp) string {
+	t := ts.Time
+	switch ts.Precision {
+	case YearPrecision:
+		return t.Format("2006")
+	case MonthPrecision:
+		return t.Format("2006-01")
+	case WeekPrecision:
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%04d-W%02d", year, week)
+	case DayPrecision:
+		return t.Format(time.DateOnly)
+	case HourPrecision:
+		return t.Format("2006-01-02T15") + formatOffset(t)
+	case MinutePrecision:
+		return t.Format("2006-01-02T15:04") + formatOffset(t)
+	case SecondPrecision:
+		return t.Format("2006-01-02T15:04:05") + formatOffset(t)
+	}
+	// A fraction of zero is still written so that the precision survives.
+	fraction := formatFraction(t.Nanosecond())
+	if fraction == "" {
+		fraction = ".0"
+	}
+	return t.Format("2006-01-02T15:04:05") + fraction + formatOffset(t)
+}
+
+// FormatRFC3339 formats the time as yyyy-MM-ddTHH:mm:ss[.fraction](Z|±hh:mm), the inverse of RFC3339.
+func FormatRFC3339(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// formatOffset formats the offset of the time as Z or ±hh:mm.
+func formatOffset(t time.Time) string {
+	return t.Format("Z07:00")
+}
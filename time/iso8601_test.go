@@ -0,0 +1,289 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+func TestISO8601(t *testing.T) {
+	tests := []ParserTest[Timestamp]{
+		{
+			Name:          "calendar date extended",
+			Input:         "2024-02-01",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "calendar date basic",
+			Input:         "20240201",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "reduced precision month",
+			Input:         "2024-05",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.May, 1, 0, 0, 0, 0, time.UTC), Precision: MonthPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "reduced precision year",
+			Input:         "2024",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC), Precision: YearPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week date extended",
+			Input:         "2024-W05-3",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week date basic",
+			Input:         "2024W053",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.January, 31, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week date without day",
+			Input:         "2024-W05",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.January, 29, 0, 0, 0, 0, time.UTC), Precision: WeekPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week date in the previous calendar year",
+			Input:         "2021-W01-1",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2021, time.January, 4, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week 53 in the next calendar year",
+			Input:         "2020-W53-7",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2021, time.January, 3, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "week 53 in a year with 52 weeks",
+			Input:          "2021-W53",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2021-W53",
+		},
+		{
+			Name:           "invalid weekday",
+			Input:          "2024-W05-8",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-W05-8",
+		},
+		{
+			Name:          "ordinal date extended",
+			Input:         "2024-032",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ordinal date basic",
+			Input:         "2024032",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ordinal date leap day",
+			Input:         "2024-366",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "ordinal date beyond the end of the year",
+			Input:          "2023-366",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2023-366",
+		},
+		{
+			Name:           "invalid day",
+			Input:          "2024-02-30",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-02-30",
+		},
+		{
+			Name:           "invalid month",
+			Input:          "2024-13",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-13",
+		},
+		{
+			Name:          "time with fractional seconds in UTC",
+			Input:         "2024-02-01T14:30:05.123Z",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 14, 30, 5, 123000000, time.UTC), Precision: SubsecondPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "time with offset",
+			Input:         "2024-02-01T14:30+02:00",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 14, 30, 0, 0, time.FixedZone("", 2*60*60)), Precision: MinutePrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "basic format with comma fraction and offset",
+			Input:         "20240201T143005,5-0530",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 14, 30, 5, 500000000, time.FixedZone("", -(5*60+30)*60)), Precision: SubsecondPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "hour precision with hour offset",
+			Input:         "2024-02-01T14-07",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.February, 1, 14, 0, 0, 0, time.FixedZone("", -7*60*60)), Precision: HourPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "week date with time",
+			Input:         "2024-W05-3T09:00:00Z",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC), Precision: SecondPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "end of day",
+			Input:         "2024-02-29T24:00:00",
+			Parser:        ISO8601,
+			ExpectedMatch: Timestamp{Time: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC), Precision: SecondPrecision},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "invalid hour",
+			Input:          "2024-02-01T25:00",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-02-01T25:00",
+		},
+		{
+			Name:           "invalid offset",
+			Input:          "2024-02-01T14:00+24:00",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-02-01T14:00+24:00",
+		},
+		{
+			Name:           "T without a time",
+			Input:          "2024-02-01Tea",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			RemainingInput: "2024-02-01Tea",
+		},
+		{
+			Name:           "remaining input",
+			Input:          "2024-02-01 is a thursday",
+			Parser:         ISO8601,
+			ExpectedMatch:  Timestamp{Time: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC), Precision: DayPrecision},
+			ExpectedOK:     true,
+			RemainingInput: " is a thursday",
+		},
+		{
+			Name:           "not a date",
+			Input:          "not a date",
+			Parser:         ISO8601,
+			ExpectedOK:     false,
+			RemainingInput: "not a date",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestRFC3339(t *testing.T) {
+	tests := []ParserTest[time.Time]{
+		{
+			Name:          "UTC",
+			Input:         "2024-02-01T14:30:05Z",
+			Parser:        RFC3339,
+			ExpectedMatch: time.Date(2024, time.February, 1, 14, 30, 5, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "lowercase with nanoseconds",
+			Input:         "2024-02-01t14:30:05.999999999z",
+			Parser:        RFC3339,
+			ExpectedMatch: time.Date(2024, time.February, 1, 14, 30, 5, 999999999, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "space separator with offset",
+			Input:         "2024-02-01 14:30:05-07:00",
+			Parser:        RFC3339,
+			ExpectedMatch: time.Date(2024, time.February, 1, 14, 30, 5, 0, time.FixedZone("", -7*60*60)),
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "missing seconds",
+			Input:          "2024-02-01T14:30Z",
+			Parser:         RFC3339,
+			ExpectedOK:     false,
+			RemainingInput: "2024-02-01T14:30Z",
+		},
+		{
+			Name:           "missing offset",
+			Input:          "2024-02-01T14:30:05",
+			Parser:         RFC3339,
+			ExpectedOK:     false,
+			RemainingInput: "2024-02-01T14:30:05",
+		},
+		{
+			Name:           "basic format",
+			Input:          "20240201T143005Z",
+			Parser:         RFC3339,
+			ExpectedOK:     false,
+			RemainingInput: "20240201T143005Z",
+		},
+		{
+			Name:           "invalid time",
+			Input:          "2024-02-01T14:60:00Z",
+			Parser:         RFC3339,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "2024-02-01T14:60:00Z",
+		},
+	}
+	RunTests(t, tests)
+}
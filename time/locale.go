@@ -0,0 +1,278 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Locale is a table of the month and weekday names accepted by MonthOfYear and DayOfWeek.
+// Names are matched case insensitively and the longest matching name wins, so the order of names does not matter.
+// Only the names are localised: ordinal suffixes (1st, 2nd) and connectives such as "the" and "of" are always English,
+// so a French date is accepted as 1 mars 2024 but not 1er mars 2024.
+type Locale struct {
+	// Name the locale is registered under, e.g. fr.
+	Name string
+	// Names and abbreviations of each month, indexed by time.Month - 1.
+	Months [12][]string
+	// Names and abbreviations of each day of the week, indexed by time.Weekday so Sunday is first.
+	Weekdays [7][]string
+	// Parsers for the names, built when the locale is registered or passed to WithLocale.
+	parsers *localeParsers
+}
+
+type localeParsers struct {
+	months, weekdays Parser[int]
+}
+
+var (
+	English = Locale{
+		Name: "en",
+		Months: [12][]string{
+			{"january", "jan"},
+			{"february", "feb"},
+			{"march", "mar"},
+			{"april", "apr"},
+			{"may"},
+			{"june", "jun"},
+			{"july", "jul"},
+			{"august", "aug"},
+			{"september", "sept", "sep"},
+			{"october", "oct"},
+			{"november", "nov"},
+			{"december", "dec"},
+		},
+		Weekdays: [7][]string{
+			{"sunday", "sun"},
+			{"monday", "mon"},
+			{"tuesday", "tues", "tue"},
+			{"wednesday", "weds", "wed"},
+			{"thursday", "thurs", "thur", "thu"},
+			{"friday", "fri"},
+			{"saturday", "sat"},
+		},
+	}
+	French = Locale{
+		Name: "fr",
+		Months: [12][]string{
+			{"janvier", "janv.", "janv"},
+			{"février", "fevrier", "févr.", "févr", "fevr"},
+			{"mars"},
+			{"avril", "avr.", "avr"},
+			{"mai"},
+			{"juin"},
+			{"juillet", "juil.", "juil"},
+			{"août", "aout"},
+			{"septembre", "sept.", "sept"},
+			{"octobre", "oct.", "oct"},
+			{"novembre", "nov.", "nov"},
+			{"décembre", "decembre", "déc.", "déc", "dec"},
+		},
+		Weekdays: [7][]string{
+			{"dimanche", "dim.", "dim"},
+			{"lundi", "lun.", "lun"},
+			{"mardi", "mar.", "mar"},
+			{"mercredi", "mer.", "mer"},
+			{"jeudi", "jeu.", "jeu"},
+			{"vendredi", "ven.", "ven"},
+			{"samedi", "sam.", "sam"},
+		},
+	}
+	German = Locale{
+		Name: "de",
+		Months: [12][]string{
+			{"januar", "jänner", "jan"},
+			{"februar", "feb"},
+			{"märz", "maerz", "mär"},
+			{"april", "apr"},
+			{"mai"},
+			{"juni", "jun"},
+			{"juli", "jul"},
+			{"august", "aug"},
+			{"september", "sept", "sep"},
+			{"oktober", "okt"},
+			{"november", "nov"},
+			{"dezember", "dez"},
+		},
+		Weekdays: [7][]string{
+			{"sonntag", "so"},
+			{"montag", "mo"},
+			{"dienstag", "di"},
+			{"mittwoch", "mi"},
+			{"donnerstag", "do"},
+			{"freitag", "fr"},
+			{"samstag", "sonnabend", "sa"},
+		},
+	}
+	Spanish = Locale{
+		Name: "es",
+		Months: [12][]string{
+			{"enero", "ene"},
+			{"febrero", "feb"},
+			{"marzo", "mar"},
+			{"abril", "abr"},
+			{"mayo", "may"},
+			{"junio", "jun"},
+			{"julio", "jul"},
+			{"agosto", "ago"},
+			{"septiembre", "setiembre", "sept", "sep"},
+			{"octubre", "oct"},
+			{"noviembre", "nov"},
+			{"diciembre", "dic"},
+		},
+		Weekdays: [7][]string{
+			{"domingo", "dom"},
+			{"lunes", "lun"},
+			{"martes", "mar"},
+			{"miércoles", "miercoles", "mié", "mie"},
+			{"jueves", "jue"},
+			{"viernes", "vie"},
+			{"sábado", "sabado", "sáb", "sab"},
+		},
+	}
+	Japanese = Locale{
+		Name: "ja",
+		Months: [12][]string{
+			{"1月", "一月"},
+			{"2月", "二月"},
+			{"3月", "三月"},
+			{"4月", "四月"},
+			{"5月", "五月"},
+			{"6月", "六月"},
+			{"7月", "七月"},
+			{"8月", "八月"},
+			{"9月", "九月"},
+			{"10月", "十月"},
+			{"11月", "十一月"},
+			{"12月", "十二月"},
+		},
+		Weekdays: [7][]string{
+			{"日曜日", "日曜"},
+			{"月曜日", "月曜"},
+			{"火曜日", "火曜"},
+			{"水曜日", "水曜"},
+			{"木曜日", "木曜"},
+			{"金曜日", "金曜"},
+			{"土曜日", "土曜"},
+		},
+	}
+)
+
+var (
+	localesMu sync.RWMutex
+	locales   = map[string]Locale{}
+)
+
+func init() {
+	for _, locale := range []*Locale{&English, &French, &German, &Spanish, &Japanese} {
+		*locale = locale.withParsers()
+		locales[locale.Name] = *locale
+	}
+}
+
+// RegisterLocale makes the locale available to LookupLocale, replacing any locale registered under the same name.
+func RegisterLocale(locale Locale) error {
+	if locale.Name == "" {
+		return fmt.Errorf("failed to register locale: name is required")
+	}
+	for i, names := range locale.Months {
+		if len(names) == 0 {
+			return fmt.Errorf("failed to register locale %s: %s has no names", locale.Name, time.Month(i+1))
+		}
+	}
+	for i, names := range locale.Weekdays {
+		if len(names) == 0 {
+			return fmt.Errorf("failed to register locale %s: %s has no names", locale.Name, time.Weekday(i))
+		}
+	}
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	locales[locale.Name] = locale.withParsers()
+	return nil
+}
+
+// LookupLocale returns the locale registered under the name.
+// English (en), French (fr), German (de), Spanish (es) and Japanese (ja) are registered by default.
+func LookupLocale(name string) (Locale, bool) {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	locale, ok := locales[name]
+	return locale, ok
+}
+
+// WithLocale runs the parser with month and weekday names taken from the locale instead of English.
+func WithLocale[T any](locale Locale, parser Parser[T]) Parser[T] {
+	return WithState(locale.withParsers(), parser)
+}
+
+// currentLocale returns the locale attached to the input, defaulting to English.
+func currentLocale(in Input) Locale {
+	if locale, ok := GetState[Locale](in); ok {
+		return locale
+	}
+	return English
+}
+
+// withParsers returns the locale with parsers built from its names.
+func (l Locale) withParsers() Locale {
+	l.parsers = &localeParsers{months: longestName(l.Months[:]), weekdays: longestName(l.Weekdays[:])}
+	return l
+}
+
+// monthNames returns the parser for the names of the months, building it if the locale was never registered or
+// passed to WithLocale.
+func (l Locale) monthNames() Parser[int] {
+	if l.parsers == nil {
+		return longestName(l.Months[:])
+	}
+	return l.parsers.months
+}
+
+// weekdayNames returns the parser for the names of the days of the week, see monthNames.
+func (l Locale) weekdayNames() Parser[int] {
+	if l.parsers == nil {
+		return longestName(l.Weekdays[:])
+	}
+	return l.parsers.weekdays
+}
+
+// longestName matches the longest of the names, case insensitively, returning the index of the list it is in.
+func longestName(names [][]string) Parser[int] {
+	var parsers []Parser[int]
+	for i, list := range names {
+		for _, name := range list {
+			parser := StringInsensitive(name)
+			parsers = append(parsers, func(in Input) (int, bool, error) {
+				_, ok, err := parser(in)
+				return i, ok, err
+			})
+		}
+	}
+	return Longest(parsers...)
+}
+
+// WeekdayName returns the first name of the day of the week in the locale, which DayOfWeek parses back to the same day.
+func (l Locale) WeekdayName(day time.Weekday) string {
+	return l.Weekdays[day][0]
+}
+
+// MonthName returns the first name of the month in the locale, which MonthOfYear parses back to the same month.
+func (l Locale) MonthName(month time.Month) string {
+	return l.Months[month-1][0]
+}
@@ -0,0 +1,199 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleMonthOfYear(t *testing.T) {
+	tests := []ParserTest[time.Month]{
+		{
+			Name:          "French with diacritics",
+			Input:         "février",
+			Parser:        WithLocale(French, MonthOfYear),
+			ExpectedMatch: time.February,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "French uppercase",
+			Input:         "FÉVRIER",
+			Parser:        WithLocale(French, MonthOfYear),
+			ExpectedMatch: time.February,
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "French abbreviation with period",
+			Input:          "déc. 2024",
+			Parser:         WithLocale(French, MonthOfYear),
+			ExpectedMatch:  time.December,
+			ExpectedOK:     true,
+			RemainingInput: " 2024",
+		},
+		{
+			Name:          "French longest name wins",
+			Input:         "juillet",
+			Parser:        WithLocale(French, MonthOfYear),
+			ExpectedMatch: time.July,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "German",
+			Input:         "März",
+			Parser:        WithLocale(German, MonthOfYear),
+			ExpectedMatch: time.March,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Spanish",
+			Input:         "setiembre",
+			Parser:        WithLocale(Spanish, MonthOfYear),
+			ExpectedMatch: time.September,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Japanese",
+			Input:         "11月",
+			Parser:        WithLocale(Japanese, MonthOfYear),
+			ExpectedMatch: time.November,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Japanese kanji numerals",
+			Input:         "十一月",
+			Parser:        WithLocale(Japanese, MonthOfYear),
+			ExpectedMatch: time.November,
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "English is not accepted in another locale",
+			Input:          "february",
+			Parser:         WithLocale(French, MonthOfYear),
+			ExpectedOK:     false,
+			RemainingInput: "february",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestLocaleDayOfWeek(t *testing.T) {
+	tests := []ParserTest[time.Weekday]{
+		{
+			Name:          "French",
+			Input:         "Mercredi",
+			Parser:        WithLocale(French, DayOfWeek),
+			ExpectedMatch: time.Wednesday,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "German abbreviation",
+			Input:         "Do",
+			Parser:        WithLocale(German, DayOfWeek),
+			ExpectedMatch: time.Thursday,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Spanish with diacritics",
+			Input:         "miércoles",
+			Parser:        WithLocale(Spanish, DayOfWeek),
+			ExpectedMatch: time.Wednesday,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "Japanese",
+			Input:         "水曜日",
+			Parser:        WithLocale(Japanese, DayOfWeek),
+			ExpectedMatch: time.Wednesday,
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "English by default",
+			Input:         "wednesday",
+			Parser:        DayOfWeek,
+			ExpectedMatch: time.Wednesday,
+			ExpectedOK:    true,
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestLocaleCompound(t *testing.T) {
+	tests := []ParserTest[time.Time]{
+		{
+			Name:          "French written date",
+			Input:         "3 mars 2024",
+			Parser:        WithLocale(French, DayMonthYear(DMY)),
+			ExpectedMatch: time.Date(2024, time.March, 3, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "German written date",
+			Input:         "1 Dezember 2024",
+			Parser:        WithLocale(German, DayMonthYear(DMY)),
+			ExpectedMatch: time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			// Ordinals are English only, see Locale.
+			Name:           "French ordinal",
+			Input:          "1er mars 2024",
+			Parser:         WithLocale(French, DayMonthYear(DMY)),
+			ExpectedOK:     false,
+			RemainingInput: "1er mars 2024",
+		},
+		{
+			Name:          "English ordinal in another locale",
+			Input:         "the 1st of mars 2024",
+			Parser:        WithLocale(French, DayMonthYear(DMY)),
+			ExpectedMatch: time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestRegisterLocale(t *testing.T) {
+	dutch := Locale{
+		Name: "nl",
+		Months: [12][]string{
+			{"januari", "jan"}, {"februari", "feb"}, {"maart", "mrt"}, {"april", "apr"}, {"mei"}, {"juni", "jun"},
+			{"juli", "jul"}, {"augustus", "aug"}, {"september", "sep"}, {"oktober", "okt"}, {"november", "nov"}, {"december", "dec"},
+		},
+		Weekdays: [7][]string{{"zondag", "zo"}, {"maandag", "ma"}, {"dinsdag", "di"}, {"woensdag", "wo"}, {"donderdag", "do"}, {"vrijdag", "vr"}, {"zaterdag", "za"}},
+	}
+	assert.NoError(t, RegisterLocale(dutch))
+
+	locale, ok := LookupLocale("nl")
+	assert.True(t, ok)
+	RunTests(t, []ParserTest[time.Month]{
+		{
+			Name:          "registered locale",
+			Input:         "mrt",
+			Parser:        WithLocale(locale, MonthOfYear),
+			ExpectedMatch: time.March,
+			ExpectedOK:    true,
+		},
+	})
+
+	_, ok = LookupLocale("xx")
+	assert.False(t, ok)
+	assert.Error(t, RegisterLocale(Locale{Name: "empty"}))
+	assert.Error(t, RegisterLocale(Locale{}))
+}
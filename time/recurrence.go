@@ -0,0 +1,657 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// Frequency is the unit a RecurrenceRule repeats in.
+type Frequency int
+
+const (
+	Daily Frequency = iota
+	Weekly
+	Monthly
+	Yearly
+)
+
+func (f Frequency) String() string {
+	switch f {
+	case Daily:
+		return "DAILY"
+	case Weekly:
+		return "WEEKLY"
+	case Monthly:
+		return "MONTHLY"
+	case Yearly:
+		return "YEARLY"
+	}
+	return fmt.Sprintf("Frequency(%d)", int(f))
+}
+
+// RecurrenceRule is a subset of an RFC 5545 recurrence rule.
+type RecurrenceRule struct {
+	Frequency Frequency
+	// Number of frequency units between occurrences, 0 is treated as 1.
+	Interval int
+	// Restrict or expand occurrences to these days of the week (BYDAY without ordinals).
+	Weekdays []time.Weekday
+	// Restrict or expand occurrences to these days of the month (BYMONTHDAY), from 1 to 31.
+	MonthDays []int
+	// Restrict or expand occurrences to these months (BYMONTH).
+	Months []time.Month
+	// Maximum number of occurrences counted from the start of the rule, 0 is unlimited.
+	Count int
+	// Last instant an occurrence may fall on, the zero time is unlimited.
+	Until time.Time
+	// Start of the rule (DTSTART), which provides the time of day and, unless the rule overrides them, the weekday,
+	// day of the month and month of occurrences. The zero time means the rule starts at the time passed to Next.
+	Start time.Time
+}
+
+// Number of periods Next will search before giving up on a rule that never matches, e.g. every month on the 31st in February.
+const maxRecurrencePeriods = 10000
+
+// Next returns up to n occurrences strictly after the given time, in order.
+// Fewer than n occurrences are returned if the rule ends first.
+func (r RecurrenceRule) Next(after time.Time, n int) []time.Time {
+	anchor := r.Start
+	if anchor.IsZero() {
+		anchor = after
+	}
+	interval := max(r.Interval, 1)
+
+	// Skip straight to the periods around after when there is no count to keep track of.
+	first := 0
+	if r.Count == 0 && after.After(anchor) {
+		first = max(r.periodsBetween(anchor, after)/interval-1, 0)
+	}
+
+	var occurrences []time.Time
+	count := 0
+	for period := first; period < first+maxRecurrencePeriods && len(occurrences) < n; period++ {
+		for _, t := range r.expand(anchor, period*interval) {
+			if t.Before(anchor) {
+				continue
+			}
+			if !r.Until.IsZero() && t.After(r.Until) {
+				return occurrences
+			}
+			count++
+			if r.Count > 0 && count > r.Count {
+				return occurrences
+			}
+			if t.After(after) {
+				occurrences = append(occurrences, t)
+				if len(occurrences) == n {
+					return occurrences
+				}
+			}
+		}
+	}
+	return occurrences
+}
+
+func (r RecurrenceRule) periodsBetween(from, to time.Time) int {
+	switch r.Frequency {
+	case Daily:
+		return int(to.Sub(from).Hours() / 24)
+	case Weekly:
+		return int(to.Sub(from).Hours() / (24 * 7))
+	case Monthly:
+		return (to.Year()-from.Year())*12 + int(to.Month()-from.Month())
+	case Yearly:
+		return to.Year() - from.Year()
+	}
+	return 0
+}
+
+// expand returns the occurrences in the period that is offset frequency units from the anchor, in order.
+func (r RecurrenceRule) expand(anchor time.Time, offset int) []time.Time {
+	year, month, day := anchor.Date()
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, anchor.Hour(), anchor.Minute(), anchor.Second(), anchor.Nanosecond(), anchor.Location())
+	}
+
+	var candidates []time.Time
+	switch r.Frequency {
+	case Daily:
+		candidates = append(candidates, at(year, month, day+offset))
+	case Weekly:
+		// Weeks start on a Monday.
+		monday := day + offset*7 - (int(anchor.Weekday())+6)%7
+		weekdays := r.Weekdays
+		if len(weekdays) == 0 {
+			weekdays = []time.Weekday{anchor.Weekday()}
+		}
+		for _, weekday := range weekdays {
+			candidates = append(candidates, at(year, month, monday+(int(weekday)+6)%7))
+		}
+	case Monthly:
+		first := at(year, month+time.Month(offset), 1)
+		candidates = r.monthDays(first.Year(), first.Month(), day, at)
+	case Yearly:
+		months := r.Months
+		if len(months) == 0 {
+			months = []time.Month{month}
+			// Every matching weekday in the year.
+			if len(r.Weekdays) > 0 && len(r.MonthDays) == 0 {
+				months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			}
+		}
+		for _, m := range months {
+			candidates = append(candidates, r.monthDays(year+offset, m, day, at)...)
+		}
+	}
+
+	var occurrences []time.Time
+	for _, t := range candidates {
+		if r.matches(t) && !slices.ContainsFunc(occurrences, t.Equal) {
+			occurrences = append(occurrences, t)
+		}
+	}
+	sort.Slice(occurrences, func(i, j int) bool { return occurrences[i].Before(occurrences[j]) })
+	return occurrences
+}
+
+// monthDays returns the candidate days in the month, skipping days the month does not have.
+func (r RecurrenceRule) monthDays(year int, month time.Month, anchorDay int, at func(int, time.Month, int) time.Time) []time.Time {
+	days := daysInMonth(year, month)
+	var candidates []time.Time
+	switch {
+	case len(r.MonthDays) > 0:
+		for _, day := range r.MonthDays {
+			if day <= days {
+				candidates = append(candidates, at(year, month, day))
+			}
+		}
+	case len(r.Weekdays) > 0:
+		for day := 1; day <= days; day++ {
+			candidates = append(candidates, at(year, month, day))
+		}
+	case anchorDay <= days:
+		candidates = append(candidates, at(year, month, anchorDay))
+	}
+	return candidates
+}
+
+func (r RecurrenceRule) matches(t time.Time) bool {
+	if len(r.Weekdays) > 0 && !slices.Contains(r.Weekdays, t.Weekday()) {
+		return false
+	}
+	if len(r.MonthDays) > 0 && !slices.Contains(r.MonthDays, t.Day()) {
+		return false
+	}
+	if len(r.Months) > 0 && !slices.Contains(r.Months, t.Month()) {
+		return false
+	}
+	return true
+}
+
+// Recurrence parses either an RFC 5545 recurrence rule or a human readable one, see RRule and HumanRecurrence.
+var Recurrence = func(in Input) (RecurrenceRule, bool, error) {
+	return Longest(RRule, HumanRecurrence)(in)
+}
+
+var (
+	weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	weekends = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+// HumanRecurrence parses a human readable recurrence made up of a frequency, an optional "on" clause and an optional end.
+//
+// Frequencies: daily, weekly, fortnightly, monthly, yearly, annually, every day, every 2 weeks, every other month,
+// every weekday, every weekend, every mon wed.
+// On clauses: on mon and wed, on weekdays, on the 1st and 15th, on jan 1st, on the 1st of jan.
+// Ends: until 2024-12-31, 10 times, for 10 occurrences. A date without a time includes the whole day in UTC.
+var HumanRecurrence = func(in Input) (RecurrenceRule, bool, error) {
+	start := in.Checkpoint()
+	rule, ok, err := recurrenceFrequency(in)
+	if err != nil || !ok {
+		return RecurrenceRule{}, false, err
+	}
+
+	for _, clause := range []func(in Input, rule *RecurrenceRule) (bool, error){recurrenceOn, recurrenceUntil, recurrenceCount} {
+		beforeClause := in.Checkpoint()
+		_, ok, err := InlineWhitespace(in)
+		if err != nil {
+			in.Restore(start)
+			return RecurrenceRule{}, false, err
+		}
+		if ok {
+			ok, err = clause(in, &rule)
+			if err != nil {
+				in.Restore(start)
+				return RecurrenceRule{}, false, err
+			}
+		}
+		if !ok {
+			in.Restore(beforeClause)
+		}
+	}
+
+	// Recurrences must end on a word boundary, e.g. "every mon" is not the start of "every month".
+	if r, size := PeekRune(in); size > 0 && unicode.IsLetter(r) {
+		in.Restore(start)
+		return RecurrenceRule{}, false, nil
+	}
+	return rule, true, nil
+}
+
+var recurrenceUnit = oneOf(map[string]Frequency{
+	"day": Daily, "days": Daily,
+	"week": Weekly, "weeks": Weekly,
+	"month": Monthly, "months": Monthly,
+	"year": Yearly, "years": Yearly,
+})
+
+var recurrenceFrequency = func(in Input) (RecurrenceRule, bool, error) {
+	every := SequenceOf2(StringInsensitive("every"), InlineWhitespace)
+	adverb := oneOf(map[string]RecurrenceRule{
+		"daily":       {Frequency: Daily, Interval: 1},
+		"weekly":      {Frequency: Weekly, Interval: 1},
+		"fortnightly": {Frequency: Weekly, Interval: 2},
+		"monthly":     {Frequency: Monthly, Interval: 1},
+		"yearly":      {Frequency: Yearly, Interval: 1},
+		"annually":    {Frequency: Yearly, Interval: 1},
+	})
+	unit := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf3(every, Optional(SequenceOf2(Longest(StringInsensitive("other"), StringFrom(SkipOneOrMore(isoDigit))), InlineWhitespace)), recurrenceUnit)(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		_, n, frequency := m.Values()
+		rule := RecurrenceRule{Frequency: frequency, Interval: 1}
+		if n.Ok() {
+			count, _ := n.Values().Values()
+			if strings.EqualFold(count, "other") {
+				rule.Interval = 2
+			} else if rule.Interval, err = strconv.Atoi(count); err != nil || rule.Interval < 1 {
+				return RecurrenceRule{}, false, fmt.Errorf("failed to parse recurrence: invalid interval %s", count)
+			}
+		}
+		return rule, true, nil
+	}
+	days := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf2(every, recurrenceWeekdays)(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		_, days := m.Values()
+		return RecurrenceRule{Frequency: Weekly, Interval: 1, Weekdays: days}, true, nil
+	}
+	weekday := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf2(every, oneOf(map[string][]time.Weekday{"weekday": weekdays, "weekend": weekends, "weekend day": weekends}))(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		_, days := m.Values()
+		return RecurrenceRule{Frequency: Daily, Interval: 1, Weekdays: days}, true, nil
+	}
+	return Longest(adverb, unit, days, weekday)(in)
+}
+
+// One or more days of the week separated by whitespace, commas or "and".
+var recurrenceWeekdays = func(in Input) ([]time.Weekday, bool, error) {
+	separator := Longest(
+		StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace),
+		StringFrom(InlineWhitespace, StringInsensitive("and"), InlineWhitespace),
+		InlineWhitespace,
+	)
+	m, ok, err := SequenceOf2(DayOfWeek, ZeroOrMore(SequenceOf2(separator, DayOfWeek)))(in)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	first, rest := m.Values()
+	days := []time.Weekday{first}
+	for _, r := range rest {
+		_, day := r.Values()
+		days = append(days, day)
+	}
+	return days, true, nil
+}
+
+func recurrenceOn(in Input, rule *RecurrenceRule) (bool, error) {
+	the := Optional(SequenceOf2(StringInsensitive("the"), InlineWhitespace))
+	separator := Longest(
+		StringFrom(OptionalInlineWhitespace, Rune(','), OptionalInlineWhitespace),
+		StringFrom(InlineWhitespace, StringInsensitive("and"), InlineWhitespace),
+	)
+	// mon and wed
+	days := func(in Input) (RecurrenceRule, bool, error) {
+		days, ok, err := Longest(recurrenceWeekdays, oneOf(map[string][]time.Weekday{"weekdays": weekdays, "weekends": weekends}))(in)
+		return RecurrenceRule{Weekdays: days}, ok, err
+	}
+	// the 1st and 15th
+	monthDays := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf3(the, ordinalDay, ZeroOrMore(SequenceOf3(separator, the, ordinalDay)))(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		_, first, rest := m.Values()
+		days := []int{first}
+		for _, r := range rest {
+			_, _, day := r.Values()
+			days = append(days, day)
+		}
+		return RecurrenceRule{MonthDays: days}, true, nil
+	}
+	// jan 1st or january 1
+	monthThenDay := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf3(MonthOfYear, InlineWhitespace, MonthDay)(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		month, _, day := m.Values()
+		return RecurrenceRule{Months: []time.Month{month}, MonthDays: []int{day}}, true, nil
+	}
+	// the 1st of january
+	dayThenMonth := func(in Input) (RecurrenceRule, bool, error) {
+		m, ok, err := SequenceOf5(the, ordinalDay, InlineWhitespace, Optional(SequenceOf2(StringInsensitive("of"), InlineWhitespace)), MonthOfYear)(in)
+		if err != nil || !ok {
+			return RecurrenceRule{}, false, err
+		}
+		_, day, _, _, month := m.Values()
+		return RecurrenceRule{Months: []time.Month{month}, MonthDays: []int{day}}, true, nil
+	}
+
+	start := in.Checkpoint()
+	m, ok, err := SequenceOf3(StringInsensitive("on"), InlineWhitespace, Longest(days, monthDays, monthThenDay, dayThenMonth))(in)
+	if err != nil || !ok {
+		return false, err
+	}
+	_, _, on := m.Values()
+	for _, day := range on.MonthDays {
+		// Check against a leap year so that the 29th of February is allowed.
+		limit := 31
+		if len(on.Months) > 0 {
+			limit = daysInMonth(2024, on.Months[0])
+		}
+		if day > limit {
+			in.Restore(start)
+			return false, fmt.Errorf("failed to parse recurrence: day %d out of range at position %d", day, start.Position())
+		}
+	}
+	rule.Weekdays, rule.MonthDays, rule.Months = on.Weekdays, on.MonthDays, on.Months
+	return true, nil
+}
+
+func recurrenceUntil(in Input, rule *RecurrenceRule) (bool, error) {
+	m, ok, err := SequenceOf3(StringInsensitive("until"), InlineWhitespace, ISO8601)(in)
+	if err != nil || !ok {
+		return false, err
+	}
+	_, _, until := m.Values()
+	rule.Until = until.endOf()
+	return true, nil
+}
+
+func recurrenceCount(in Input, rule *RecurrenceRule) (bool, error) {
+	m, ok, err := SequenceOf4(
+		Optional(SequenceOf2(StringInsensitive("for"), InlineWhitespace)),
+		StringFrom(SkipOneOrMore(isoDigit)),
+		InlineWhitespace,
+		oneOf(map[string]bool{"times": true, "occurrences": true}),
+	)(in)
+	if err != nil || !ok {
+		return false, err
+	}
+	_, count, _, _ := m.Values()
+	rule.Count, err = strconv.Atoi(count)
+	if err != nil || rule.Count < 1 {
+		return false, fmt.Errorf("failed to parse recurrence: invalid count %s", count)
+	}
+	return true, nil
+}
+
+// endOf returns the last instant covered by the timestamp when it has no time, otherwise the time itself.
+func (t Timestamp) endOf() time.Time {
+	switch t.Precision {
+	case YearPrecision:
+		return t.Time.AddDate(1, 0, 0).Add(-time.Nanosecond)
+	case MonthPrecision:
+		return t.Time.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	case WeekPrecision:
+		return t.Time.AddDate(0, 0, 7).Add(-time.Nanosecond)
+	case DayPrecision:
+		return t.Time.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t.Time
+}
+
+// RRule parses an RFC 5545 recurrence rule, e.g. RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10.
+// The RRULE: prefix is optional but without it the rule must start with FREQ.
+// FREQ, INTERVAL, COUNT, UNTIL, BYDAY (without ordinals), BYMONTHDAY (positive days only), BYMONTH and WKST=MO are supported,
+// anything else is an error.
+var RRule = func(in Input) (RecurrenceRule, bool, error) {
+	start := in.Checkpoint()
+	_, prefixed, err := StringInsensitive("RRULE:")(in)
+	if err != nil {
+		return RecurrenceRule{}, false, err
+	}
+	if !prefixed {
+		if s, ok := in.Peek(len("FREQ=")); !ok || !strings.EqualFold(s, "FREQ=") {
+			return RecurrenceRule{}, false, nil
+		}
+	}
+
+	fail := func(err error) (RecurrenceRule, bool, error) {
+		in.Restore(start)
+		return RecurrenceRule{}, false, err
+	}
+
+	rule := RecurrenceRule{Interval: 1}
+	seen := map[string]bool{}
+	separator := Optional(Rune(';'))
+	for i := 0; ; i++ {
+		beforePart := in.Checkpoint()
+		if i > 0 {
+			m, _, err := separator(in)
+			if err != nil {
+				return fail(err)
+			}
+			if !m.Ok() {
+				break
+			}
+		}
+		position := in.Checkpoint().Position()
+		m, ok, err := rrulePart(in)
+		if err != nil {
+			return fail(err)
+		}
+		if !ok {
+			if i == 0 {
+				return fail(nil)
+			}
+			in.Restore(beforePart)
+			break
+		}
+		key, _, value := m.Values()
+		key = strings.ToUpper(key)
+		if seen[key] {
+			return fail(fmt.Errorf("failed to parse recurrence: duplicate %s at position %d", key, position))
+		}
+		seen[key] = true
+		if err := rule.set(key, strings.ToUpper(value)); err != nil {
+			return fail(fmt.Errorf("failed to parse recurrence: %w at position %d", err, position))
+		}
+	}
+
+	if !seen["FREQ"] {
+		return fail(fmt.Errorf("failed to parse recurrence: missing FREQ at position %d", start.Position()))
+	}
+	if seen["COUNT"] && seen["UNTIL"] {
+		return fail(fmt.Errorf("failed to parse recurrence: COUNT and UNTIL are mutually exclusive at position %d", start.Position()))
+	}
+	return rule, true, nil
+}
+
+// rrulePart parses a KEY=value part of an RRULE.
+var rrulePart = WithGenerator(
+	SequenceOf3(StringFrom(SkipOneOrMore(RuneInRanges(unicode.Letter))), Rune('='), StringFrom(SkipOneOrMore(RuneNotIn(";\r\n\t ")))),
+	generateRRulePart,
+)
+
+// generateRRulePart generates one of the supported parts of an RRULE.
+func generateRRulePart(r *rand.Rand) string {
+	list := func(n func() string) string {
+		values := make([]string, 1+r.IntN(3))
+		for i := range values {
+			values[i] = n()
+		}
+		return strings.Join(values, ",")
+	}
+	switch r.IntN(7) {
+	case 0:
+		return "FREQ=" + []string{"DAILY", "WEEKLY", "MONTHLY", "YEARLY"}[r.IntN(4)]
+	case 1:
+		return "INTERVAL=" + strconv.Itoa(1+r.IntN(10))
+	case 2:
+		return "COUNT=" + strconv.Itoa(1+r.IntN(100))
+	case 3:
+		return fmt.Sprintf("UNTIL=%04d%02d%02d", 1000+r.IntN(9000), 1+r.IntN(12), 1+r.IntN(28))
+	case 4:
+		return "BYDAY=" + list(func() string { return []string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}[r.IntN(7)] })
+	case 5:
+		return "BYMONTHDAY=" + list(func() string { return strconv.Itoa(1 + r.IntN(31)) })
+	default:
+		return "BYMONTH=" + list(func() string { return strconv.Itoa(1 + r.IntN(12)) })
+	}
+}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// set assigns an RRULE part to the rule.
+func (r *RecurrenceRule) set(key, value string) error {
+	switch key {
+	case "FREQ":
+		frequencies := map[string]Frequency{"DAILY": Daily, "WEEKLY": Weekly, "MONTHLY": Monthly, "YEARLY": Yearly}
+		frequency, ok := frequencies[value]
+		if !ok {
+			return fmt.Errorf("unsupported FREQ %s", value)
+		}
+		r.Frequency = frequency
+	case "INTERVAL", "COUNT":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid %s %s", key, value)
+		}
+		if key == "INTERVAL" {
+			r.Interval = n
+		} else {
+			r.Count = n
+		}
+	case "UNTIL":
+		// Either a date or a date time, e.g. 20240131 or 20240131T090000Z.
+		in := NewInput(value)
+		until, ok, err := ISO8601(in)
+		if _, more := in.Peek(1); err != nil || !ok || more || until.Precision != DayPrecision && until.Precision != SecondPrecision {
+			return fmt.Errorf("invalid UNTIL %s", value)
+		}
+		r.Until = until.endOf()
+	case "BYDAY":
+		for _, day := range strings.Split(value, ",") {
+			weekday, ok := rruleWeekdays[day]
+			if !ok {
+				return fmt.Errorf("unsupported BYDAY %s", day)
+			}
+			r.Weekdays = append(r.Weekdays, weekday)
+		}
+	case "BYMONTHDAY":
+		for _, day := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(day)
+			if err != nil || n < 1 || n > 31 {
+				return fmt.Errorf("unsupported BYMONTHDAY %s", day)
+			}
+			r.MonthDays = append(r.MonthDays, n)
+		}
+	case "BYMONTH":
+		for _, month := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(month)
+			if err != nil || n < 1 || n > 12 {
+				return fmt.Errorf("invalid BYMONTH %s", month)
+			}
+			r.Months = append(r.Months, time.Month(n))
+		}
+	case "WKST":
+		// Weeks always start on a Monday.
+		if value != "MO" {
+			return fmt.Errorf("unsupported WKST %s", value)
+		}
+	default:
+		return fmt.Errorf("unsupported %s", key)
+	}
+	return nil
+}
+
+// FormatRRule formats the rule as an RFC 5545 recurrence rule, e.g. RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10,
+// the inverse of RRule and Recurrence. Start is not part of an RRULE so it is not written.
+// An Until at the end of a day in UTC is written as a date, otherwise as a UTC time to the second.
+// Rules with both a Count and an Until cannot be parsed back, see RRule.
+func FormatRRule(r RecurrenceRule) string {
+	parts := []string{"FREQ=" + r.Frequency.String()}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.Weekdays) > 0 {
+		days := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			for code, weekday := range rruleWeekdays {
+				if weekday == day {
+					days[i] = code
+				}
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.MonthDays) > 0 {
+		days := make([]string, len(r.MonthDays))
+		for i, day := range r.MonthDays {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.Months) > 0 {
+		months := make([]string, len(r.Months))
+		for i, month := range r.Months {
+			months[i] = strconv.Itoa(int(month))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		until := r.Until.UTC()
+		if day := (Timestamp{Time: until.Truncate(24 * time.Hour), Precision: DayPrecision}); day.endOf().Equal(until) {
+			parts = append(parts, "UNTIL="+day.Time.Format("20060102"))
+		} else {
+			parts = append(parts, "UNTIL="+until.Format("20060102T150405Z"))
+		}
+	}
+	return "RRULE:" + strings.Join(parts, ";")
+}
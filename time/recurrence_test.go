@@ -0,0 +1,260 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/liamawhite/parse/core"
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecurrence(t *testing.T) {
+	tests := []ParserTest[RecurrenceRule]{
+		{
+			Name:          "daily",
+			Input:         "daily",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Daily, Interval: 1},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every n weeks on days",
+			Input:         "every 2 weeks on mon wed",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Wednesday}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every month on a day",
+			Input:         "every month on the 15th",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Monthly, Interval: 1, MonthDays: []int{15}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every month on several days",
+			Input:         "Every month on the 1st and the 15th",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Monthly, Interval: 1, MonthDays: []int{1, 15}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every weekday",
+			Input:         "every weekday",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Daily, Interval: 1, Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every listed day",
+			Input:         "every tue, thu and sat",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Tuesday, time.Thursday, time.Saturday}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "every other month",
+			Input:         "every other month",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Monthly, Interval: 2},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "annually on a date",
+			Input:         "annually on jan 1st",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Yearly, Interval: 1, Months: []time.Month{time.January}, MonthDays: []int{1}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "day of month",
+			Input:         "yearly on the 29th of february",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Yearly, Interval: 1, Months: []time.Month{time.February}, MonthDays: []int{29}},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "until a date",
+			Input:         "weekly until 2024-12-31",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Weekly, Interval: 1, Until: time.Date(2024, time.December, 31, 23, 59, 59, 999999999, time.UTC)},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "number of times",
+			Input:         "every day for 10 times",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Daily, Interval: 1, Count: 10},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "remaining input",
+			Input:          "every week on fri to review",
+			Parser:         Recurrence,
+			ExpectedMatch:  RecurrenceRule{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Friday}},
+			ExpectedOK:     true,
+			RemainingInput: " to review",
+		},
+		{
+			Name:           "followed by non-ASCII punctuation",
+			Input:          "every week on fri—review",
+			Parser:         Recurrence,
+			ExpectedMatch:  RecurrenceRule{Frequency: Weekly, Interval: 1, Weekdays: []time.Weekday{time.Friday}},
+			ExpectedOK:     true,
+			RemainingInput: "—review",
+		},
+		{
+			Name:           "impossible date",
+			Input:          "annually on feb 30th",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "annually on feb 30th",
+		},
+		{
+			Name:           "zero interval",
+			Input:          "every 0 days",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "every 0 days",
+		},
+		{
+			Name:           "not a recurrence",
+			Input:          "everyone",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			RemainingInput: "everyone",
+		},
+		{
+			Name:          "RRULE",
+			Input:         "RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE;COUNT=10",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Wednesday}, Count: 10},
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "RRULE without prefix",
+			Input:         "FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1;UNTIL=20301231T235959Z",
+			Parser:        Recurrence,
+			ExpectedMatch: RecurrenceRule{Frequency: Yearly, Interval: 1, Months: []time.Month{time.January}, MonthDays: []int{1}, Until: time.Date(2030, time.December, 31, 23, 59, 59, 0, time.UTC)},
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "RRULE followed by a new line",
+			Input:          "RRULE:FREQ=DAILY\nnext",
+			Parser:         Recurrence,
+			ExpectedMatch:  RecurrenceRule{Frequency: Daily, Interval: 1},
+			ExpectedOK:     true,
+			RemainingInput: "\nnext",
+		},
+		{
+			Name:           "RRULE with unsupported part",
+			Input:          "RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "RRULE:FREQ=MONTHLY;BYDAY=-1FR",
+		},
+		{
+			Name:           "RRULE without FREQ",
+			Input:          "RRULE:COUNT=3",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "RRULE:COUNT=3",
+		},
+		{
+			Name:           "RRULE with COUNT and UNTIL",
+			Input:          "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240101",
+			Parser:         Recurrence,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "RRULE:FREQ=DAILY;COUNT=3;UNTIL=20240101",
+		},
+		{
+			Name:           "RRULE with duplicate part",
+			Input:          "FREQ=DAILY;FREQ=WEEKLY",
+			Parser:         RRule,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "FREQ=DAILY;FREQ=WEEKLY",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestRecurrenceRuleNext(t *testing.T) {
+	// Wednesday the 31st of January 2024.
+	after := time.Date(2024, time.January, 31, 9, 0, 0, 0, time.UTC)
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("every 2 weeks on mon wed", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Weekly, Interval: 2, Weekdays: []time.Weekday{time.Monday, time.Wednesday}}
+		assert.Equal(t, []time.Time{date(2024, time.February, 12), date(2024, time.February, 14), date(2024, time.February, 26)}, rule.Next(after, 3))
+	})
+
+	t.Run("every weekday", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Daily, Weekdays: []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}}
+		assert.Equal(t, []time.Time{date(2024, time.February, 1), date(2024, time.February, 2), date(2024, time.February, 5)}, rule.Next(after, 3))
+	})
+
+	t.Run("monthly skips short months", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Monthly, Interval: 1}
+		assert.Equal(t, []time.Time{date(2024, time.March, 31), date(2024, time.May, 31)}, rule.Next(after, 2))
+	})
+
+	t.Run("monthly on several days", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Monthly, MonthDays: []int{15, 1}}
+		assert.Equal(t, []time.Time{date(2024, time.February, 1), date(2024, time.February, 15), date(2024, time.March, 1)}, rule.Next(after, 3))
+	})
+
+	t.Run("leap day", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Yearly, Months: []time.Month{time.February}, MonthDays: []int{29}}
+		assert.Equal(t, []time.Time{date(2024, time.February, 29), date(2028, time.February, 29)}, rule.Next(after, 2))
+	})
+
+	t.Run("count from start", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Daily, Count: 3, Start: date(2024, time.January, 30)}
+		assert.Equal(t, []time.Time{date(2024, time.February, 1)}, rule.Next(after, 5))
+	})
+
+	t.Run("until", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Weekly, Until: time.Date(2024, time.February, 14, 23, 59, 59, 0, time.UTC)}
+		assert.Equal(t, []time.Time{date(2024, time.February, 7), date(2024, time.February, 14)}, rule.Next(after, 5))
+	})
+
+	t.Run("start long before", func(t *testing.T) {
+		rule := RecurrenceRule{Frequency: Weekly, Interval: 3, Start: date(1990, time.January, 1)}
+		next := rule.Next(after, 2)
+		assert.Len(t, next, 2)
+		assert.Equal(t, time.Monday, next[0].Weekday())
+		assert.Equal(t, 21*24*time.Hour, next[1].Sub(next[0]))
+		assert.True(t, next[0].After(after) && next[0].Before(after.AddDate(0, 0, 22)))
+	})
+
+	t.Run("parsed", func(t *testing.T) {
+		rule, ok, err := Recurrence(core.NewInput("every month on the 15th"))
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, []time.Time{date(2024, time.February, 15)}, rule.Next(after, 1))
+	})
+}
@@ -0,0 +1,275 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	. "github.com/liamawhite/parse/core"
+)
+
+// RelativeDate parses natural language dates relative to the reference time, resolving them in the given location.
+//
+// The following phrases are supported, case insensitively:
+//   - today, tomorrow, yesterday, the day after tomorrow, the day before yesterday
+//   - friday or this friday: the next friday on or after today, so friday on a friday is today
+//   - next friday: the first friday strictly after today, so next friday on a thursday is tomorrow
+//   - last friday: the last friday strictly before today
+//   - next week/month/year and last week/month/year: the same day one unit after or before today
+//   - in 3 days, 3 days from now, 3 days ago: any Duration after or before the reference time
+//   - the 3rd: the next 3rd of the month on or after today
+//   - the 3rd of this/next/last month: the 3rd of the given month, which is an error if the month is too short
+//
+// Dates are returned at midnight unless the phrase contains a duration with a clock component (in 2 hours), in which case the time of the reference is kept.
+// Adding or subtracting months or years clamps to the end of the month, so next month on the 31st of January is the 29th of February in a leap year.
+func RelativeDate(ref time.Time, loc *time.Location) Parser[time.Time] {
+	now := ref.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	parser := Longest(
+		relativeDay(today),
+		relativeWeekday(today),
+		relativeUnit(today),
+		relativeDuration(now),
+		relativeMonthDay(today),
+	)
+	return func(in Input) (time.Time, bool, error) {
+		start := in.Checkpoint()
+		match, ok, err := parser(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		// Phrases must end on a word boundary, e.g. "todays" is not "today".
+		if r, size := PeekRune(in); size > 0 && unicode.IsLetter(r) {
+			in.Restore(start)
+			return time.Time{}, false, nil
+		}
+		return match, true, nil
+	}
+}
+
+// phrase matches the words, case insensitively, separated by inline whitespace.
+func phrase(words ...string) Parser[string] {
+	parsers := make([]Parser[string], 0, len(words)*2-1)
+	for i, word := range words {
+		if i > 0 {
+			parsers = append(parsers, InlineWhitespace)
+		}
+		parsers = append(parsers, StringInsensitive(word))
+	}
+	return StringFrom(parsers...)
+}
+
+// oneOf matches the longest of the phrases and returns the value associated with it.
+func oneOf[T any](phrases map[string]T) Parser[T] {
+	keys := make([]string, 0, len(phrases))
+	for p := range phrases {
+		keys = append(keys, p)
+	}
+	sort.Strings(keys)
+	parsers := make([]Parser[string], 0, len(keys))
+	for _, p := range keys {
+		parsers = append(parsers, phrase(strings.Fields(p)...))
+	}
+	return func(in Input) (T, bool, error) {
+		var t T
+		match, ok, err := Longest(parsers...)(in)
+		if err != nil || !ok {
+			return t, false, err
+		}
+		return phrases[strings.Join(strings.Fields(strings.ToLower(match)), " ")], true, nil
+	}
+}
+
+func relativeDay(today time.Time) Parser[time.Time] {
+	days := oneOf(map[string]int{
+		"today":                    0,
+		"tomorrow":                 1,
+		"yesterday":                -1,
+		"the day after tomorrow":   2,
+		"day after tomorrow":       2,
+		"the day before yesterday": -2,
+		"day before yesterday":     -2,
+	})
+	return func(in Input) (time.Time, bool, error) {
+		n, ok, err := days(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		return today.AddDate(0, 0, n), true, nil
+	}
+}
+
+type relativeModifier int
+
+const (
+	thisModifier relativeModifier = iota
+	nextModifier
+	lastModifier
+)
+
+var modifier = oneOf(map[string]relativeModifier{"this": thisModifier, "next": nextModifier, "last": lastModifier})
+
+func relativeWeekday(today time.Time) Parser[time.Time] {
+	return func(in Input) (time.Time, bool, error) {
+		m, ok, err := SequenceOf2(Optional(SequenceOf2(modifier, InlineWhitespace)), DayOfWeek)(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		mod, day := m.Values()
+		which := thisModifier
+		if mod.Ok() {
+			which, _ = mod.Values().Values()
+		}
+		diff := (int(day) - int(today.Weekday()) + 7) % 7
+		switch which {
+		case nextModifier:
+			if diff == 0 {
+				diff = 7
+			}
+		case lastModifier:
+			diff = -((int(today.Weekday()) - int(day) + 7) % 7)
+			if diff == 0 {
+				diff = -7
+			}
+		}
+		return today.AddDate(0, 0, diff), true, nil
+	}
+}
+
+func relativeUnit(today time.Time) Parser[time.Time] {
+	units := oneOf(map[string]Period{"day": {Days: 1}, "week": {Weeks: 1}, "month": {Months: 1}, "year": {Years: 1}})
+	return func(in Input) (time.Time, bool, error) {
+		m, ok, err := SequenceOf3(oneOf(map[string]bool{"next": true, "last": false}), InlineWhitespace, units)(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		next, _, period := m.Values()
+		if !next {
+			period = period.Negate()
+		}
+		return addClamped(today, period), true, nil
+	}
+}
+
+func relativeDuration(now time.Time) Parser[time.Time] {
+	in := func(in Input) (Period, bool, error) {
+		m, ok, err := SequenceOf3(StringInsensitive("in"), InlineWhitespace, Duration)(in)
+		if err != nil || !ok {
+			return Period{}, false, err
+		}
+		_, _, period := m.Values()
+		return period, true, nil
+	}
+	direction := oneOf(map[string]bool{"from now": true, "from today": true, "later": true, "ago": false})
+	suffixed := func(in Input) (Period, bool, error) {
+		m, ok, err := SequenceOf3(Duration, InlineWhitespace, direction)(in)
+		if err != nil || !ok {
+			return Period{}, false, err
+		}
+		period, _, after := m.Values()
+		if !after {
+			period = period.Negate()
+		}
+		return period, true, nil
+	}
+	return func(input Input) (time.Time, bool, error) {
+		period, ok, err := Longest(in, suffixed)(input)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		match := addClamped(now, period)
+		if period.Clock == 0 {
+			match = time.Date(match.Year(), match.Month(), match.Day(), 0, 0, 0, 0, now.Location())
+		}
+		return match, true, nil
+	}
+}
+
+// relativeMonthDay parses an ordinal day of the month, optionally preceded by "the" and followed by "of this/next/last month".
+func relativeMonthDay(today time.Time) Parser[time.Time] {
+	the := Optional(SequenceOf2(StringInsensitive("the"), InlineWhitespace))
+	of := Optional(SequenceOf6(InlineWhitespace, StringInsensitive("of"), InlineWhitespace, modifier, InlineWhitespace, StringInsensitive("month")))
+	return func(in Input) (time.Time, bool, error) {
+		start := in.Checkpoint()
+		m, ok, err := SequenceOf3(the, ordinalDay, of)(in)
+		if err != nil || !ok {
+			return time.Time{}, false, err
+		}
+		_, day, month := m.Values()
+
+		if !month.Ok() {
+			// Find the first month, starting with this one, that has the day on or after today.
+			for i := 0; ; i++ {
+				first := time.Date(today.Year(), today.Month()+time.Month(i), 1, 0, 0, 0, 0, today.Location())
+				if day > daysInMonth(first.Year(), first.Month()) {
+					continue
+				}
+				if date := first.AddDate(0, 0, day-1); !date.Before(today) {
+					return date, true, nil
+				}
+			}
+		}
+
+		_, _, _, which, _, _ := month.Values().Values()
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		switch which {
+		case nextModifier:
+			first = first.AddDate(0, 1, 0)
+		case lastModifier:
+			first = first.AddDate(0, -1, 0)
+		}
+		if days := daysInMonth(first.Year(), first.Month()); day > days {
+			in.Restore(start)
+			return time.Time{}, false, fmt.Errorf("failed to parse date: day %d out of range, %s %d has %d days", day, first.Month(), first.Year(), days)
+		}
+		return first.AddDate(0, 0, day-1), true, nil
+	}
+}
+
+// addClamped adds the period to the time, clamping to the end of the month rather than overflowing into the next.
+func addClamped(t time.Time, p Period) time.Time {
+	months := p.Years*12 + p.Months
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	day := t.Day()
+	if days := daysInMonth(first.Year(), first.Month()); day > days {
+		day = days
+	}
+	return first.AddDate(0, 0, day-1+p.Weeks*7+p.Days).Add(p.Clock)
+}
+
+// FormatRelativeDate formats the day of the date relative to the day of the reference time in the given location,
+// as today, tomorrow, yesterday, in 3 days or 3 days ago, which RelativeDate parses back to midnight on that day.
+func FormatRelativeDate(ref time.Time, loc *time.Location, date time.Time) string {
+	day := func(t time.Time) time.Time {
+		year, month, d := t.In(loc).Date()
+		return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+	}
+	switch days := int(day(date).Sub(day(ref)) / (24 * time.Hour)); {
+	case days == 0:
+		return "today"
+	case days == 1:
+		return "tomorrow"
+	case days == -1:
+		return "yesterday"
+	case days > 0:
+		return fmt.Sprintf("in %d days", days)
+	default:
+		return fmt.Sprintf("%d days ago", -days)
+	}
+}
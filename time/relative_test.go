@@ -0,0 +1,222 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+func TestRelativeDate(t *testing.T) {
+	// Wednesday the 31st of January 2024.
+	wednesday := RelativeDate(time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC), time.UTC)
+	friday := RelativeDate(time.Date(2024, time.February, 2, 10, 30, 0, 0, time.UTC), time.UTC)
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2024, month, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	tests := []ParserTest[time.Time]{
+		{
+			Name:          "today",
+			Input:         "today",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.January, 31),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "tomorrow",
+			Input:         "Tomorrow",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 1),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "day before yesterday",
+			Input:         "the day before yesterday",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.January, 29),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "weekday",
+			Input:         "friday",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 2),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "weekday on the same weekday",
+			Input:         "friday",
+			Parser:        friday,
+			ExpectedMatch: date(time.February, 2),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next weekday",
+			Input:         "next fri",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 2),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next weekday on the same weekday",
+			Input:         "next friday",
+			Parser:        friday,
+			ExpectedMatch: date(time.February, 9),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "last weekday",
+			Input:         "last Monday",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.January, 29),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "last weekday on the same weekday",
+			Input:         "last friday",
+			Parser:        friday,
+			ExpectedMatch: date(time.January, 26),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next week",
+			Input:         "next week",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 7),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "next month clamps to the end of the month",
+			Input:         "next month",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 29),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "last year",
+			Input:         "last year",
+			Parser:        wednesday,
+			ExpectedMatch: time.Date(2023, time.January, 31, 0, 0, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "in days",
+			Input:         "in 3 days",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 3),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "in hours keeps the time",
+			Input:         "in 2 hours",
+			Parser:        wednesday,
+			ExpectedMatch: time.Date(2024, time.January, 31, 12, 30, 0, 0, time.UTC),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "from now",
+			Input:         "2 weeks from now",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 14),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ago",
+			Input:         "1 month ago",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.December, 31).AddDate(-1, 0, 0),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ordinal later this month",
+			Input:         "the 31st",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.January, 31),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ordinal skips months that are too short",
+			Input:         "30th",
+			Parser:        friday,
+			ExpectedMatch: date(time.March, 30),
+			ExpectedOK:    true,
+		},
+		{
+			Name:          "ordinal of next month",
+			Input:         "the 3rd of next month",
+			Parser:        wednesday,
+			ExpectedMatch: date(time.February, 3),
+			ExpectedOK:    true,
+		},
+		{
+			Name:           "ordinal beyond the end of the month",
+			Input:          "the 30th of next month",
+			Parser:         wednesday,
+			ExpectedOK:     false,
+			WantErr:        true,
+			RemainingInput: "the 30th of next month",
+		},
+		{
+			Name:           "remaining input",
+			Input:          "tomorrow at noon",
+			Parser:         wednesday,
+			ExpectedMatch:  date(time.February, 1),
+			ExpectedOK:     true,
+			RemainingInput: " at noon",
+		},
+		{
+			Name:           "not a word boundary",
+			Input:          "todays",
+			Parser:         wednesday,
+			ExpectedOK:     false,
+			RemainingInput: "todays",
+		},
+		{
+			Name:           "followed by non-ASCII punctuation",
+			Input:          "tomorrow—maybe",
+			Parser:         wednesday,
+			ExpectedMatch:  date(time.February, 1),
+			ExpectedOK:     true,
+			RemainingInput: "—maybe",
+		},
+		{
+			Name:           "not a relative date",
+			Input:          "someday",
+			Parser:         wednesday,
+			ExpectedOK:     false,
+			RemainingInput: "someday",
+		},
+	}
+	RunTests(t, tests)
+}
+
+func TestRelativeDateLocation(t *testing.T) {
+	// 23:00 UTC on the 31st is already the 1st of February in Tokyo.
+	tokyo := time.FixedZone("JST", 9*60*60)
+	parser := RelativeDate(time.Date(2024, time.January, 31, 23, 0, 0, 0, time.UTC), tokyo)
+	RunTests(t, []ParserTest[time.Time]{
+		{
+			Name:          "tomorrow",
+			Input:         "tomorrow",
+			Parser:        parser,
+			ExpectedMatch: time.Date(2024, time.February, 2, 0, 0, 0, 0, tokyo),
+			ExpectedOK:    true,
+		},
+	})
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Notedown Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package time_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/liamawhite/parse/test"
+	. "github.com/liamawhite/parse/time"
+)
+
+func TestRollback(t *testing.T) {
+	ref := time.Date(2024, time.January, 31, 10, 30, 0, 0, time.UTC)
+
+	t.Run("YearMonthDay", func(t *testing.T) { CheckRollback(t, YearMonthDay, "2024-02-01", "2024-02-30", "2024-13-01") })
+	t.Run("DayOfWeek", func(t *testing.T) { CheckRollback(t, DayOfWeek, "monday", "Tues", "funday") })
+	t.Run("DaysOfWeek", func(t *testing.T) { CheckRollback(t, DaysOfWeek, "mon tue wed", "mon fun") })
+	t.Run("MonthDay", func(t *testing.T) { CheckRollback(t, MonthDay, "1st", "2st", "32", "2024") })
+	t.Run("MonthNumber", func(t *testing.T) { CheckRollback(t, MonthNumber, "1", "12", "13") })
+	t.Run("MonthOfYear", func(t *testing.T) { CheckRollback(t, MonthOfYear, "january", "Sept", "juneteenth") })
+	t.Run("DayMonthYear", func(t *testing.T) {
+		CheckRollback(t, DayMonthYear(DMY), "3rd March 2024", "the 3rd of March, 2024", "March 3, 2024", "30/02/2024", "3/4-2024")
+	})
+	t.Run("Duration", func(t *testing.T) {
+		CheckRollback(t, Duration, "P1Y2M3DT4H5M6.5S", "1 hour and 30 minutes", "1.5 days", "PT")
+	})
+	t.Run("ISO8601", func(t *testing.T) {
+		CheckRollback(t, ISO8601, "2024-02-01T14:30:05.123+02:00", "2024-W05-3", "2024-032", "2024-02-30", "2024-02-01T25:00")
+	})
+	t.Run("RFC3339", func(t *testing.T) { CheckRollback(t, RFC3339, "2024-02-01T14:30:05Z", "2024-02-01 14:30:05+24:00") })
+	t.Run("TimeOfDay", func(t *testing.T) {
+		CheckRollback(t, TimeOfDay, "14:30:05.5 UTC", "2:30 p.m.", "13pm", "noon Europe/London", "9am Europe/Nowhere")
+	})
+	t.Run("DateTime", func(t *testing.T) {
+		CheckRollback(t, DateTime(ref, time.UTC), "2024-02-01T14:30", "tomorrow at noon", "9am on friday", "2024-02-30 9am", "3rd March 2024 14:30", "14:30 on 30th February 2024")
+	})
+	t.Run("RelativeDate", func(t *testing.T) {
+		CheckRollback(t, RelativeDate(ref, time.UTC), "the day after tomorrow", "next friday", "3 days ago", "the 30th of next month", "todays")
+	})
+	t.Run("Interval", func(t *testing.T) {
+		CheckRollback(t, Interval(ref, time.UTC), "2024-01-01..2024-01-31", "2024-01-31..2024-01-01", "mon-fri", "nov to feb 2025", "2024-01-01/P1M")
+	})
+	t.Run("Recurrence", func(t *testing.T) {
+		CheckRollback(t, Recurrence, "every 2 weeks on mon and wed until 2024-12-31", "RRULE:FREQ=WEEKLY;COUNT=2;UNTIL=20241231", "every other month on the 31st")
+	})
+	t.Run("Cron", func(t *testing.T) {
+		CheckRollback(t, Cron, "*/15 9-17 * * MON-FRI", "0 0 30 2 *", "60 * * * *", "@every 1h30m", "@every 0s")
+	})
+}